@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/fakeboard"
+)
+
+func main() {
+	addr := flag.String("addr", ":8090", "address to listen on")
+	jobs := flag.Int("jobs", fakeboard.DefaultJobs, "number of generated jobs")
+	seed := flag.Int64("seed", 1, "seed for the generated jobs and injected failures")
+	pageSize := flag.Int("page-size", fakeboard.DefaultPageSize, "listings per page")
+	latency := flag.Duration("latency", 0, "delay added to every response")
+	errorRate := flag.Float64("error-rate", 0, "fraction of requests answered with 500")
+	rateLimitRate := flag.Float64("rate-limit-rate", 0, "fraction of requests answered with 429")
+	retryAfter := flag.Duration("retry-after", fakeboard.DefaultRetryAfter, "Retry-After sent with 429 responses")
+	flag.Parse()
+
+	board := fakeboard.New(fakeboard.Options{
+		Jobs:          *jobs,
+		Seed:          *seed,
+		PageSize:      *pageSize,
+		Latency:       *latency,
+		ErrorRate:     *errorRate,
+		RateLimitRate: *rateLimitRate,
+		RetryAfter:    *retryAfter,
+	})
+
+	log.Printf("Fake job board serving %d jobs on %s", *jobs, *addr)
+	host, port, err := net.SplitHostPort(*addr)
+	if err == nil {
+		if host == "" {
+			host = "localhost"
+		}
+		log.Printf("Point the server at it with SCRAPER_BASE_URL=http://%s and raise its limit with SCRAPER_BASE_URL_RATE_LIMIT",
+			net.JoinHostPort(host, port))
+	}
+	log.Fatal(http.ListenAndServe(*addr, board))
+}
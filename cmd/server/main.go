@@ -1,21 +1,72 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/Illuminateee/web-scrapper.git/internal/api"
+	"github.com/Illuminateee/web-scrapper.git/internal/currency"
+	"github.com/Illuminateee/web-scrapper.git/internal/dedupe"
+	"github.com/Illuminateee/web-scrapper.git/internal/scraper"
+	"github.com/Illuminateee/web-scrapper.git/internal/skills"
+	"github.com/Illuminateee/web-scrapper.git/internal/storage"
 	"github.com/gorilla/mux"
 	"github.com/rs/cors"
 )
 
 func main() {
+	// Load the skill taxonomy before storage, which indexes jobs by canonical skill
+	if err := loadSkillTaxonomy(); err != nil {
+		log.Fatalf("Failed to load skill taxonomy: %v", err)
+	}
+
+	// Initialize storage backend
+	jobStorage, err := newStorage()
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+
+	// Load declarative site definitions
+	sitesPath := os.Getenv("SITES_PATH")
+	if sitesPath == "" {
+		sitesPath = "sites.json"
+	}
+	sites, err := scraper.NewSiteStore(sitesPath)
+	if err != nil {
+		log.Fatalf("Failed to load site definitions: %v", err)
+	}
+
+	// Load the exchange rates used to compare salaries
+	exchangeRatesPath, err := loadExchangeRates()
+	if err != nil {
+		log.Fatalf("Failed to load exchange rates: %v", err)
+	}
+
+	// Cache scraper responses on disk
+	httpCache, err := newHTTPCache()
+	if err != nil {
+		log.Fatalf("Failed to open HTTP cache: %v", err)
+	}
+
 	// Initialize router
 	router := mux.NewRouter()
 
 	// Setup API routes
-	api.SetupRoutes(router)
+	api.SetupRoutes(router, api.Config{
+		Storage:           jobStorage,
+		SearchTTL:         searchTTL(),
+		Sites:             sites,
+		DetailConcurrency: detailConcurrency(),
+		HTTPCache:         httpCache,
+		ScraperBaseURL:    scraperBaseURL(),
+		ExchangeRatesPath: exchangeRatesPath,
+
+		ScraperBaseURLRateLimit: scraperBaseURLRateLimit(),
+	})
 
 	// Setup CORS
 	c := cors.New(cors.Options{
@@ -37,3 +88,215 @@ func main() {
 	log.Printf("Server starting on port %s", port)
 	log.Fatal(http.ListenAndServe(":"+port, handler))
 }
+
+// newStorage selects the storage backend from the STORAGE_BACKEND environment variable
+func newStorage() (storage.JobStorage, error) {
+	thresholds := duplicateThresholds()
+	closeAfter := closeAfterMisses()
+
+	switch backend := os.Getenv("STORAGE_BACKEND"); backend {
+	case "", "memory":
+		log.Printf("Using in-memory storage")
+		s := storage.NewInMemoryStorage()
+		s.SetDuplicateThresholds(thresholds)
+		s.SetClosedAfterMisses(closeAfter)
+		return s, nil
+	case "sqlite":
+		path := os.Getenv("SQLITE_PATH")
+		if path == "" {
+			path = "jobs.db"
+		}
+		log.Printf("Using SQLite storage at %s", path)
+		s, err := storage.NewSQLiteStorage(path)
+		if err != nil {
+			return nil, err
+		}
+		s.SetDuplicateThresholds(thresholds)
+		s.SetClosedAfterMisses(closeAfter)
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q (expected \"memory\" or \"sqlite\")", backend)
+	}
+}
+
+// duplicateThresholds reads when jobs from different sources are merged from the
+// DEDUPE_COMPANY_THRESHOLD, DEDUPE_TITLE_THRESHOLD and DEDUPE_DESCRIPTION_THRESHOLD
+// environment variables, each a similarity from 0 to 1
+func duplicateThresholds() dedupe.Thresholds {
+	thresholds := dedupe.DefaultThresholds
+	for name, threshold := range map[string]*float64{
+		"DEDUPE_COMPANY_THRESHOLD":     &thresholds.CompanySimilarity,
+		"DEDUPE_TITLE_THRESHOLD":       &thresholds.TitleSimilarity,
+		"DEDUPE_DESCRIPTION_THRESHOLD": &thresholds.DescriptionSimilarity,
+	} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			log.Printf("Invalid %s %q, using default of %g", name, value, *threshold)
+			continue
+		}
+		*threshold = parsed
+	}
+	return thresholds
+}
+
+// searchTTL reads how long finished searches are kept from the SEARCH_TTL environment variable
+func searchTTL() time.Duration {
+	value := os.Getenv("SEARCH_TTL")
+	if value == "" {
+		return api.DefaultSearchTTL
+	}
+
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid SEARCH_TTL %q, using default of %s", value, api.DefaultSearchTTL)
+		return api.DefaultSearchTTL
+	}
+	return ttl
+}
+
+// closeAfterMisses reads how many consecutive scrapes must miss a job before it is closed
+// from the CLOSE_AFTER_MISSES environment variable; 0 never closes jobs
+func closeAfterMisses() int {
+	value := os.Getenv("CLOSE_AFTER_MISSES")
+	if value == "" {
+		return storage.DefaultClosedAfterMisses
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Printf("Invalid CLOSE_AFTER_MISSES %q, using default of %d", value, storage.DefaultClosedAfterMisses)
+		return storage.DefaultClosedAfterMisses
+	}
+	return n
+}
+
+// detailConcurrency reads how many detail pages are fetched at once from the DETAIL_CONCURRENCY environment variable
+func detailConcurrency() int {
+	value := os.Getenv("DETAIL_CONCURRENCY")
+	if value == "" {
+		return api.DefaultDetailConcurrency
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Printf("Invalid DETAIL_CONCURRENCY %q, using default of %d", value, api.DefaultDetailConcurrency)
+		return api.DefaultDetailConcurrency
+	}
+	return n
+}
+
+// newHTTPCache opens the scraper response cache from the HTTP_CACHE_DIR, HTTP_CACHE_TTL and
+// HTTP_CACHE_MAX_BYTES environment variables. HTTP_CACHE_DIR=off disables it.
+func newHTTPCache() (*scraper.HTTPCache, error) {
+	dir := os.Getenv("HTTP_CACHE_DIR")
+	switch dir {
+	case "off":
+		log.Printf("HTTP cache disabled")
+		return nil, nil
+	case "":
+		dir = "http-cache"
+	}
+
+	ttl := scraper.DefaultHTTPCacheTTL
+	if value := os.Getenv("HTTP_CACHE_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			log.Printf("Invalid HTTP_CACHE_TTL %q, using default of %s", value, ttl)
+		} else {
+			ttl = parsed
+		}
+	}
+
+	maxBytes := int64(scraper.DefaultHTTPCacheMaxBytes)
+	if value := os.Getenv("HTTP_CACHE_MAX_BYTES"); value != "" {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || parsed <= 0 {
+			log.Printf("Invalid HTTP_CACHE_MAX_BYTES %q, using default of %d", value, maxBytes)
+		} else {
+			maxBytes = parsed
+		}
+	}
+
+	log.Printf("Caching scraper responses in %s (fresh for %s, up to %d bytes)", dir, ttl, maxBytes)
+	return scraper.NewHTTPCache(dir, ttl, maxBytes)
+}
+
+// scraperBaseURL reads the SCRAPER_BASE_URL environment variable, which sends the built-in
+// scrapers to a fake board or mirror instead of the real sites
+func scraperBaseURL() string {
+	baseURL := os.Getenv("SCRAPER_BASE_URL")
+	if baseURL != "" {
+		log.Printf("Built-in scrapers will request %s instead of the real sites", baseURL)
+	}
+	return baseURL
+}
+
+// scraperBaseURLRateLimit reads the requests per minute allowed to the SCRAPER_BASE_URL host
+// from SCRAPER_BASE_URL_RATE_LIMIT; unset applies the default host limit
+func scraperBaseURLRateLimit() scraper.HostRateLimit {
+	value := os.Getenv("SCRAPER_BASE_URL_RATE_LIMIT")
+	if value == "" {
+		return scraper.HostRateLimit{}
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Printf("Invalid SCRAPER_BASE_URL_RATE_LIMIT %q, using the default host limit", value)
+		return scraper.HostRateLimit{}
+	}
+	return scraper.HostRateLimit{RateLimit: n}
+}
+
+// dataPath returns the path of a data file from the environment variable env, or fallback.
+// A path set explicitly must exist, so a mistyped path is not mistaken for a missing file.
+func dataPath(env, fallback string) (string, error) {
+	path := os.Getenv(env)
+	if path == "" {
+		return fallback, nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("%s: %w", env, err)
+	}
+	return path, nil
+}
+
+// loadExchangeRates installs the exchange-rate table stored at the EXCHANGE_RATES_PATH
+// environment variable and returns the path. Without the file at the default path the
+// built-in table is used.
+func loadExchangeRates() (string, error) {
+	path, err := dataPath("EXCHANGE_RATES_PATH", "exchange_rates.json")
+	if err != nil {
+		return "", err
+	}
+
+	table, err := currency.LoadTable(path)
+	if err != nil {
+		return "", err
+	}
+	currency.SetRates(table)
+
+	log.Printf("Using %s exchange rates from %s (%d currencies)", table.Base, table.Date, len(table.Rates))
+	return path, nil
+}
+
+// loadSkillTaxonomy installs the skill taxonomy stored at the SKILL_TAXONOMY_PATH environment
+// variable. Without the file at the default path the built-in taxonomy is used.
+func loadSkillTaxonomy() error {
+	path, err := dataPath("SKILL_TAXONOMY_PATH", "skills.json")
+	if err != nil {
+		return err
+	}
+
+	taxonomy, err := skills.LoadTaxonomy(path)
+	if err != nil {
+		return err
+	}
+	skills.SetDefault(taxonomy)
+
+	log.Printf("Using a taxonomy of %d skills", len(taxonomy.Skills()))
+	return nil
+}
@@ -2,29 +2,37 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/Illuminateee/web-scrapper.git/internal/api"
+	"github.com/Illuminateee/web-scrapper.git/internal/currency"
+	"github.com/Illuminateee/web-scrapper.git/internal/dedupe"
+	"github.com/Illuminateee/web-scrapper.git/internal/fakeboard"
 	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/salary"
 	"github.com/Illuminateee/web-scrapper.git/internal/scraper"
+	"github.com/Illuminateee/web-scrapper.git/internal/skills"
+	"github.com/Illuminateee/web-scrapper.git/internal/storage"
+	"github.com/gorilla/mux"
 )
 
 func main() {
 	fmt.Println("🔍 Testing Job Scrapers")
 	fmt.Println("=" + fmt.Sprintf("%*s", 50, "="))
 
-	// Create HTTP client
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			MaxIdleConns:       10,
-			IdleConnTimeout:    30 * time.Second,
-			DisableCompression: true,
-		},
-	}
-
 	// Create test search filters
 	filters := models.SearchFilters{
 		Keywords:        []string{"developer", "engineer", "programmer"},
@@ -38,25 +46,672 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
+	// Show how salary strings are parsed and annualized
+	fmt.Println("\n💰 Testing Salary Parsing (examples)...")
+	testSalaryParsing()
+
+	// Test salary filters and analytics across currencies
+	fmt.Println("\n💱 Testing Currency Conversion (both storage backends)...")
+	testCurrencyConversion()
+
+	// Test canonical skill names in storage
+	fmt.Println("\n🏷️  Testing Skill Taxonomy...")
+	testSkillTaxonomy()
+
+	// Test merging duplicates in storage
+	fmt.Println("\n👯 Testing Duplicate Detection (both storage backends)...")
+	testDuplicateDetection()
+
+	// Test stable job IDs and updating stored jobs in place
+	fmt.Println("\n🪪 Testing Stable Job IDs (both storage backends)...")
+	testStableIDs()
+
+	// Test lifecycle tracking: closing, lifecycle queries, time to fill and reposts
+	fmt.Println("\n⏳ Testing Job Lifecycle (both storage backends)...")
+	testJobLifecycle()
+
+	// Test change history of stored jobs and the recently changed filter
+	fmt.Println("\n📜 Testing Change History (both storage backends)...")
+	testChangeHistory()
+
+	// Test ranking, sorting and highlighting of full-text search
+	fmt.Println("\n🔎 Testing Full-Text Search (both storage backends)...")
+	testFullTextSearch()
+
 	// Test Mock Scraper
 	fmt.Println("\n📝 Testing Mock Scraper...")
 	testMockScraper(ctx, filters)
 
-	// Test RemoteOK Scraper
-	fmt.Println("\n🌐 Testing RemoteOK Scraper...")
-	testRemoteOKScraper(ctx, filters, client)
+	// Test Indeed Scraper against a saved feed
+	fmt.Println("\n📰 Testing Indeed Scraper (saved feed)...")
+	testIndeedScraper(ctx, filters)
+
+	// Test generic feed scraper against a saved Atom feed
+	fmt.Println("\n📡 Testing Feed Scraper (saved Atom feed)...")
+	testFeedScraper(ctx, filters)
+
+	// Test selector scraper against saved result pages
+	fmt.Println("\n🧩 Testing Selector Scraper (saved result pages)...")
+	testSelectorScraper(ctx, filters)
 
-	// Test WeWorkRemotely Scraper
-	fmt.Println("\n💼 Testing WeWorkRemotely Scraper...")
-	testWeWorkRemotelyScraper(ctx, filters, client)
+	// Test schema.org JobPosting extraction against saved job pages
+	fmt.Println("\n🏷️  Testing JobPosting Scraper (saved job pages)...")
+	testJobPostingScraper(ctx, filters)
+
+	// Test pagination against a local JobStreet-style board
+	fmt.Println("\n📚 Testing Pagination (local JobStreet board)...")
+	testPagination(ctx)
+
+	// Test retries against a flaky local board
+	fmt.Println("\n🔁 Testing Retries (flaky local board)...")
+	testRetries(ctx)
+
+	// Test per-domain rate limiting against a local job page
+	fmt.Println("\n⏱️  Testing Rate Limiting (local job pages)...")
+	testRateLimiting(ctx)
+
+	// Test robots.txt compliance against a local careers site
+	fmt.Println("\n🤖 Testing robots.txt (local careers site)...")
+	testRobots(ctx)
+
+	// Test the HTTP response cache against a local careers site
+	fmt.Println("\n💾 Testing HTTP Cache (local careers site)...")
+	testHTTPCache(ctx)
+
+	// Test the circuit breaker against a local board that goes down
+	fmt.Println("\n🔌 Testing Circuit Breaker (local board outage)...")
+	testCircuitBreaker(ctx)
+
+	// Test detail page enrichment against saved WeWorkRemotely pages
+	fmt.Println("\n🔎 Testing Detail Enrichment (saved WeWorkRemotely pages)...")
+	testDetailEnrichment(ctx)
+
+	// Test the whole server against a local fake job board
+	fmt.Println("\n🌐 Testing End to End (local fake job board)...")
+	testEndToEnd()
 
 	// Test Scraper Registry
 	fmt.Println("\n🏭 Testing Scraper Registry...")
-	testScraperRegistry(ctx, filters)
+	testScraperRegistry()
 
 	fmt.Println("\n✅ All tests completed!")
 }
 
+func testSalaryParsing() {
+	// The corpus in internal/salary/testdata is checked by go test ./internal/salary
+	for _, text := range []string{"€60–80k", "$52.50/hr", "Rp 10 - 15 juta per bulan", "$45-60"} {
+		parsed := salary.Parse(text)
+		low, high := parsed.Annual()
+		fmt.Printf("   📋 %-28q %s %d-%d a year (as %v-%v per %s, confidence %.2f)\n",
+			text, parsed.Currency, low, high, parsed.Min, parsed.Max, parsed.Period, parsed.Confidence)
+	}
+}
+
+func testCurrencyConversion() {
+	// Round rates keep the expected numbers readable
+	previous := currency.Rates()
+	defer currency.SetRates(previous)
+	currency.SetRates(&currency.Table{Base: "USD", Date: "2024-01-01", Rates: map[string]float64{
+		"USD": 1, "EUR": 0.8, "IDR": 16000,
+	}})
+
+	posted := time.Now()
+	jobs := []models.Job{
+		{ID: "usd", Title: "Go Developer", URL: "https://example.com/usd", SalaryMin: 100000, SalaryMax: 120000, SalaryCurrency: "USD", PostedDate: posted},
+		{ID: "eur", Title: "Go Developer", URL: "https://example.com/eur", SalaryMin: 64000, SalaryMax: 80000, SalaryCurrency: "EUR", PostedDate: posted},
+		{ID: "idr", Title: "Go Developer", URL: "https://example.com/idr", SalaryMin: 180000000, SalaryMax: 240000000, SalaryCurrency: "IDR", PostedDate: posted},
+		{ID: "chf", Title: "Go Developer", URL: "https://example.com/chf", SalaryMin: 90000, SalaryMax: 110000, SalaryCurrency: "CHF", PostedDate: posted},
+	}
+
+	sqlitePath := filepath.Join(os.TempDir(), fmt.Sprintf("currency-check-%d.db", time.Now().UnixNano()))
+	defer os.Remove(sqlitePath)
+	sqliteStorage, err := storage.NewSQLiteStorage(sqlitePath)
+	if err != nil {
+		log.Printf("❌ Error opening SQLite storage: %v", err)
+		return
+	}
+	defer sqliteStorage.Close()
+
+	backends := []struct {
+		name    string
+		storage storage.JobStorage
+	}{
+		{"memory", storage.NewInMemoryStorage()},
+		{"sqlite", sqliteStorage},
+	}
+
+	for _, backend := range backends {
+		if err := backend.storage.Store(jobs); err != nil {
+			log.Printf("❌ %s: error storing jobs: %v", backend.name, err)
+			continue
+		}
+
+		// 50,000 USD lets the USD and EUR jobs through; the IDR one pays about 13,000 USD
+		// and CHF has no rate
+		usd, err := backend.storage.Search(models.SearchFilters{MinSalary: 50000})
+		if err != nil {
+			log.Printf("❌ %s: error searching: %v", backend.name, err)
+			continue
+		}
+		// The same threshold in rupiah
+		idr, err := backend.storage.Search(models.SearchFilters{MinSalary: 800000000, SalaryCurrency: "IDR"})
+		if err != nil {
+			log.Printf("❌ %s: error searching: %v", backend.name, err)
+			continue
+		}
+		// Analytics in euros: midpoints of 88,000, 72,000 and 10,500 EUR
+		eur, err := backend.storage.Search(models.SearchFilters{SalaryCurrency: "EUR"})
+		if err != nil {
+			log.Printf("❌ %s: error searching: %v", backend.name, err)
+			continue
+		}
+
+		fmt.Printf("   ✅ %s: min 50,000 USD matched %s, min 800,000,000 IDR matched %s\n",
+			backend.name, jobIDs(usd.Jobs), jobIDs(idr.Jobs))
+		fmt.Printf("   📋 %s: average %.0f %s, range %d-%d, median %.0f\n", backend.name,
+			eur.Analytics.AverageSalary, eur.Analytics.SalaryCurrency, eur.Analytics.SalaryRange.Min,
+			eur.Analytics.SalaryRange.Max, eur.Analytics.SalaryRange.Median)
+
+		if jobIDs(usd.Jobs) != "eur,usd" || jobIDs(idr.Jobs) != "eur,usd" ||
+			eur.Analytics.SalaryCurrency != "EUR" || eur.Analytics.SalaryRange.Min != 10500 ||
+			eur.Analytics.SalaryRange.Max != 88000 || eur.Analytics.SalaryRange.Median != 72000 {
+			log.Printf("❌ %s: salaries were not compared in one currency", backend.name)
+		}
+
+		// Conversion only applies to comparisons; the jobs keep what the posting said
+		for _, job := range eur.Jobs {
+			if job.ID == "idr" && (job.SalaryMin != 180000000 || job.SalaryCurrency != "IDR") {
+				log.Printf("❌ %s: stored salary was changed to %d %s", backend.name, job.SalaryMin, job.SalaryCurrency)
+			}
+		}
+	}
+}
+
+func testSkillTaxonomy() {
+	fmt.Printf("   📋 %d skills in the taxonomy; go test ./internal/skills checks the corpus\n", len(skills.Default().Skills()))
+
+	// Sources spell skills their own way; filters and top skills use canonical names
+	jobStorage := storage.NewInMemoryStorage()
+	jobStorage.Store([]models.Job{
+		{ID: "a", URL: "https://example.com/a", Skills: []string{"golang", "k8s"}},
+		{ID: "b", URL: "https://example.com/b", Skills: []string{"Go", "Kubernetes", "postgres"}},
+		{ID: "c", URL: "https://example.com/c", Skills: []string{"python"}},
+	})
+	response, err := jobStorage.Search(models.SearchFilters{Skills: []string{"GO", "kubernetes"}})
+	if err != nil {
+		log.Printf("❌ Error searching: %v", err)
+		return
+	}
+	var top []string
+	for _, skill := range response.Analytics.TopSkills {
+		top = append(top, fmt.Sprintf("%s (%s) x%d", skill.Skill, skill.Category, skill.Count))
+	}
+	sort.Strings(top)
+	fmt.Printf("   📋 Skills GO, kubernetes matched %s; top skills: %s\n", jobIDs(response.Jobs), strings.Join(top, ", "))
+	if jobIDs(response.Jobs) != "a,b" || strings.Join(top, ", ") != "Go (language) x2, Kubernetes (devops) x2, PostgreSQL (database) x1" {
+		log.Printf("❌ Skills were not compared by canonical name")
+	}
+}
+
+// duplicatePair is one entry of internal/dedupe/testdata/pairs.json
+type duplicatePair struct {
+	Name      string     `json:"name"`
+	A         models.Job `json:"a"`
+	B         models.Job `json:"b"`
+	Duplicate bool       `json:"duplicate"`
+}
+
+func testDuplicateDetection() {
+	// go test ./internal/dedupe classifies the pairs; here the first is stored
+	data, err := os.ReadFile("internal/dedupe/testdata/pairs.json")
+	if err != nil {
+		log.Printf("❌ Error reading labeled pairs: %v", err)
+		return
+	}
+	var pairs []duplicatePair
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		log.Printf("❌ Error parsing labeled pairs: %v", err)
+		return
+	}
+
+	sqlitePath := filepath.Join(os.TempDir(), fmt.Sprintf("dedupe-check-%d.db", time.Now().UnixNano()))
+	defer os.Remove(sqlitePath)
+	sqliteStorage, err := storage.NewSQLiteStorage(sqlitePath)
+	if err != nil {
+		log.Printf("❌ Error opening SQLite storage: %v", err)
+		return
+	}
+	defer sqliteStorage.Close()
+
+	backends := []struct {
+		name    string
+		storage storage.JobStorage
+	}{
+		{"memory", storage.NewInMemoryStorage()},
+		{"sqlite", sqliteStorage},
+	}
+
+	// The first pair is one posting on RemoteOK and WeWorkRemotely; a near copy on
+	// RemoteOK itself is a separate posting
+	original, duplicate := pairs[0].A, pairs[0].B
+	sameSource := original
+	sameSource.ID, sameSource.URL = "rok-1b", "https://remoteok.example.com/jobs/rok-1b"
+
+	for _, backend := range backends {
+		if err := backend.storage.Store([]models.Job{original, sameSource}); err != nil {
+			log.Printf("❌ %s: error storing jobs: %v", backend.name, err)
+			continue
+		}
+		// Storing the duplicate twice records it once
+		for i := 0; i < 2; i++ {
+			if err := backend.storage.Store([]models.Job{duplicate}); err != nil {
+				log.Printf("❌ %s: error storing duplicate: %v", backend.name, err)
+			}
+		}
+
+		response, err := backend.storage.Search(models.SearchFilters{})
+		if err != nil {
+			log.Printf("❌ %s: error searching: %v", backend.name, err)
+			continue
+		}
+
+		var alsoOn []string
+		for _, job := range response.Jobs {
+			for _, listing := range job.AlsoOn {
+				alsoOn = append(alsoOn, job.ID+" also on "+listing.Source+" as "+listing.ID)
+			}
+		}
+		fmt.Printf("   ✅ %s: stored %s; %s\n", backend.name, jobIDs(response.Jobs), strings.Join(alsoOn, ", "))
+		if jobIDs(response.Jobs) != "rok-1,rok-1b" || strings.Join(alsoOn, ", ") != "rok-1 also on WeWorkRemotely as wwr-1" {
+			log.Printf("❌ %s: the cross-source duplicate was not merged into exactly one job", backend.name)
+		}
+	}
+}
+
+func testStableIDs() {
+	urls := []struct{ raw, canonical string }{
+		{"HTTPS://WeWorkRemotely.com:443/remote-jobs/acme-go-engineer/?utm_source=feed&ref=rss#apply", "https://weworkremotely.com/remote-jobs/acme-go-engineer"},
+		{"https://id.jobstreet.com/job/80123456?tracking=search&trk=1&sol=abc", "https://id.jobstreet.com/job/80123456?sol=abc&tracking=search"},
+		{"https://example.com/", "https://example.com/"},
+	}
+	for _, u := range urls {
+		if got := scraper.CanonicalURL(u.raw); got != u.canonical {
+			log.Printf("❌ CanonicalURL(%q) = %q, want %q", u.raw, got, u.canonical)
+		}
+	}
+	fmt.Printf("   ✅ %d URLs canonicalized\n", len(urls))
+
+	sqlitePath := filepath.Join(os.TempDir(), fmt.Sprintf("ids-check-%d.db", time.Now().UnixNano()))
+	defer os.Remove(sqlitePath)
+	sqliteStorage, err := storage.NewSQLiteStorage(sqlitePath)
+	if err != nil {
+		log.Printf("❌ Error opening SQLite storage: %v", err)
+		return
+	}
+	defer sqliteStorage.Close()
+
+	backends := []struct {
+		name    string
+		storage storage.JobStorage
+	}{
+		{"memory", storage.NewInMemoryStorage()},
+		{"sqlite", sqliteStorage},
+	}
+
+	posted := time.Now().AddDate(0, 0, -3)
+	first := models.Job{ID: "remoteok-1", Title: "Go Developer", Company: "Acme", URL: "https://remoteok.com/remote-jobs/1",
+		Source: "RemoteOK", PostedDate: posted, Skills: []string{"Go"}}
+	// The same posting scraped again: the title changed and a salary was added
+	second := first
+	second.Title, second.SalaryMin, second.SalaryMax, second.SalaryCurrency = "Senior Go Developer", 120000, 150000, "USD"
+	// A job stored under an ID from before IDs were stable, found again by URL
+	legacy := models.Job{ID: "wwr-python-developer-1718000000", Title: "Python Developer", Company: "Initech",
+		URL: "https://weworkremotely.com/remote-jobs/initech-python-developer", Source: "WeWorkRemotely", PostedDate: posted}
+	renamed := legacy
+	renamed.ID = "wwr-initech-python-developer"
+
+	for _, backend := range backends {
+		if err := backend.storage.Store([]models.Job{first, legacy}); err != nil {
+			log.Printf("❌ %s: error storing jobs: %v", backend.name, err)
+			continue
+		}
+		before, err := backend.storage.Search(models.SearchFilters{})
+		if err != nil {
+			log.Printf("❌ %s: error searching: %v", backend.name, err)
+			continue
+		}
+		time.Sleep(10 * time.Millisecond)
+		if err := backend.storage.Store([]models.Job{second, renamed}); err != nil {
+			log.Printf("❌ %s: error storing jobs again: %v", backend.name, err)
+			continue
+		}
+		after, err := backend.storage.Search(models.SearchFilters{})
+		if err != nil {
+			log.Printf("❌ %s: error searching: %v", backend.name, err)
+			continue
+		}
+
+		var updated models.Job
+		for _, job := range after.Jobs {
+			if job.ID == first.ID {
+				updated = job
+			}
+		}
+		fmt.Printf("   ✅ %s: stored %s after storing %s again; %q paying %d %s\n",
+			backend.name, jobIDs(after.Jobs), jobIDs(before.Jobs), updated.Title, updated.SalaryMin, updated.SalaryCurrency)
+		if jobIDs(after.Jobs) != "remoteok-1,wwr-initech-python-developer" || updated.Title != second.Title ||
+			updated.SalaryMin != second.SalaryMin || !updated.LastSeen.After(before.Jobs[0].LastSeen) {
+			log.Printf("❌ %s: stored jobs were not updated by ID and URL", backend.name)
+		}
+	}
+}
+
+// jobIDs joins the sorted IDs of jobs
+func jobIDs(jobs []models.Job) string {
+	ids := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		ids = append(ids, job.ID)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+func testJobLifecycle() {
+	sqlitePath := filepath.Join(os.TempDir(), fmt.Sprintf("lifecycle-check-%d.db", time.Now().UnixNano()))
+	defer os.Remove(sqlitePath)
+	sqliteStorage, err := storage.NewSQLiteStorage(sqlitePath)
+	if err != nil {
+		log.Printf("❌ Error opening SQLite storage: %v", err)
+		return
+	}
+	defer sqliteStorage.Close()
+
+	backends := []struct {
+		name    string
+		storage storage.JobStorage
+	}{
+		{"memory", storage.NewInMemoryStorage()},
+		{"sqlite", sqliteStorage},
+	}
+
+	now := time.Now()
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+	yesterday := daysAgo(1)
+	listing := func(id, title, company, skill string, posted time.Time) models.Job {
+		return models.Job{ID: "remoteok-" + id, Title: title, Company: company, Location: "Remote", Source: "RemoteOK",
+			URL: "https://remoteok.com/remote-jobs/" + id, PostedDate: posted, FirstSeen: posted, Skills: []string{skill},
+			Description: fmt.Sprintf("%s is hiring a %s to build and run our %s services with a small remote team.", company, title, skill)}
+	}
+	veteran := listing("10", "Staff Engineer", "Initech", "Rust", daysAgo(90))
+	python := listing("11", "Python Developer", "Acme", "Python", daysAgo(20))
+	golang := listing("12", "Go Engineer", "Globex", "Go", daysAgo(10))
+	expiring := listing("13", "Support Engineer", "Hooli", "Zendesk", daysAgo(5))
+	expiring.ValidThrough = &yesterday
+	// Acme posts the Python job again under a new ID and URL
+	pythonAgain := python
+	pythonAgain.ID, pythonAgain.URL = "remoteok-21", "https://remoteok.com/remote-jobs/21"
+
+	search := func(backend storage.JobStorage, filters models.SearchFilters) *models.SearchResponse {
+		response, err := backend.Search(filters)
+		if err != nil {
+			log.Printf("❌ error searching with %+v: %v", filters, err)
+			return &models.SearchResponse{}
+		}
+		return response
+	}
+
+	for _, backend := range backends {
+		if err := backend.storage.Store([]models.Job{veteran, python, golang, expiring}); err != nil {
+			log.Printf("❌ %s: error storing jobs: %v", backend.name, err)
+			continue
+		}
+		// Three scrapes in a row no longer list the Python and Go jobs
+		for i := 0; i < storage.DefaultClosedAfterMisses; i++ {
+			found := []models.Job{veteran, expiring}
+			if err := backend.storage.Store(found); err != nil {
+				log.Printf("❌ %s: error storing jobs: %v", backend.name, err)
+			}
+			if err := backend.storage.RecordScrape("RemoteOK", models.SearchFilters{}, found); err != nil {
+				log.Printf("❌ %s: error recording scrape: %v", backend.name, err)
+			}
+		}
+
+		closed := search(backend.storage, models.SearchFilters{Status: models.JobStatusClosed})
+		recent := search(backend.storage, models.SearchFilters{ClosedWithinDays: 7})
+		old := search(backend.storage, models.SearchFilters{OpenLongerThanDays: 60})
+		expired := search(backend.storage, models.SearchFilters{Status: models.JobStatusExpired})
+		fmt.Printf("   ✅ %s: closed %s, closed in 7 days %s, open over 60 days %s, expired %s\n",
+			backend.name, jobIDs(closed.Jobs), jobIDs(recent.Jobs), jobIDs(old.Jobs), jobIDs(expired.Jobs))
+		if jobIDs(closed.Jobs) != "remoteok-11,remoteok-12" || jobIDs(recent.Jobs) != "remoteok-11,remoteok-12" ||
+			jobIDs(old.Jobs) != "remoteok-10" || jobIDs(expired.Jobs) != "remoteok-13" {
+			log.Printf("❌ %s: unexpected lifecycle query results", backend.name)
+		}
+
+		fill := search(backend.storage, models.SearchFilters{}).Analytics.TimeToFill
+		if fill == nil {
+			log.Printf("❌ %s: no time to fill in analytics", backend.name)
+			continue
+		}
+		fillDays := func(groups []models.GroupFillTime) string {
+			parts := make([]string, 0, len(groups))
+			for _, group := range groups {
+				parts = append(parts, fmt.Sprintf("%s %.0f", group.Name, group.AverageDays))
+			}
+			sort.Strings(parts)
+			return strings.Join(parts, ", ")
+		}
+		fmt.Printf("   ✅ %s: %d closed jobs filled in %.0f days on average; by company %s; by skill %s\n",
+			backend.name, fill.ClosedJobs, fill.AverageDays, fillDays(fill.ByCompany), fillDays(fill.BySkill))
+		if fill.ClosedJobs != 2 || fillDays(fill.ByCompany) != "Acme 20, Globex 10" || fillDays(fill.BySkill) != "Go 10, Python 20" {
+			log.Printf("❌ %s: unexpected time to fill", backend.name)
+		}
+
+		// The Go job comes back under its own ID, the Python job as a new posting
+		if err := backend.storage.Store([]models.Job{golang, pythonAgain}); err != nil {
+			log.Printf("❌ %s: error storing reposted jobs: %v", backend.name, err)
+			continue
+		}
+		all := search(backend.storage, models.SearchFilters{})
+		reposted := search(backend.storage, models.SearchFilters{MinReposts: 1})
+		var reposters []string
+		for _, company := range reposted.Analytics.TopReposters {
+			reposters = append(reposters, fmt.Sprintf("%s %d", company.Company, company.Count))
+		}
+		sort.Strings(reposters)
+		fmt.Printf("   ✅ %s: reposted %s (%s) of %d jobs, %d open\n",
+			backend.name, jobIDs(reposted.Jobs), strings.Join(reposters, ", "), all.Total, all.Analytics.StatusDistribution[models.JobStatusOpen])
+		if jobIDs(reposted.Jobs) != "remoteok-12,remoteok-21" || all.Total != 4 ||
+			strings.Join(reposters, ", ") != "Acme 1, Globex 1" || all.Analytics.StatusDistribution[models.JobStatusOpen] != 3 {
+			log.Printf("❌ %s: reposted jobs were not reopened", backend.name)
+		}
+	}
+}
+
+func testChangeHistory() {
+	sqlitePath := filepath.Join(os.TempDir(), fmt.Sprintf("history-check-%d.db", time.Now().UnixNano()))
+	defer os.Remove(sqlitePath)
+	sqliteStorage, err := storage.NewSQLiteStorage(sqlitePath)
+	if err != nil {
+		log.Printf("❌ Error opening SQLite storage: %v", err)
+		return
+	}
+	defer sqliteStorage.Close()
+
+	backends := []struct {
+		name    string
+		storage storage.JobStorage
+	}{
+		{"memory", storage.NewInMemoryStorage()},
+		{"sqlite", sqliteStorage},
+	}
+
+	posted := time.Now().AddDate(0, 0, -5)
+	original := models.Job{ID: "remoteok-30", Title: "Go Developer", Company: "Acme", Location: "Remote", Source: "RemoteOK",
+		URL: "https://remoteok.com/remote-jobs/30", PostedDate: posted, Skills: []string{"Go", "PostgreSQL"},
+		SalaryMin: 100000, SalaryMax: 120000, SalaryCurrency: "USD", Requirements: []string{"3+ years of Go"},
+		Description: "Acme is hiring a Go developer to build payment APIs on PostgreSQL and run them in production with a small remote team."}
+	untouched := models.Job{ID: "remoteok-31", Title: "Data Analyst", Company: "Globex", Location: "Remote", Source: "RemoteOK",
+		URL: "https://remoteok.com/remote-jobs/31", PostedDate: posted, Skills: []string{"SQL"}}
+	// The company raises the salary and retitles the job
+	raised := original
+	raised.Title, raised.SalaryMin, raised.SalaryMax = "Senior Go Developer", 110000, 135000
+	// The same posting on WeWorkRemotely lists benefits
+	elsewhere := raised
+	elsewhere.ID, elsewhere.Source, elsewhere.URL = "wwr-acme-senior-go-developer", "WeWorkRemotely", "https://weworkremotely.com/remote-jobs/acme-senior-go-developer"
+	elsewhere.Benefits = []string{"Home office budget"}
+
+	fields := func(revision models.JobRevision) string {
+		names := make([]string, 0, len(revision.Changes))
+		for _, change := range revision.Changes {
+			names = append(names, change.Field)
+		}
+		return strings.Join(names, ",")
+	}
+
+	for _, backend := range backends {
+		for _, jobs := range [][]models.Job{{original, untouched}, {original, untouched}, {raised}, {elsewhere}} {
+			if err := backend.storage.Store(jobs); err != nil {
+				log.Printf("❌ %s: error storing jobs: %v", backend.name, err)
+			}
+		}
+
+		history, err := backend.storage.History(elsewhere.ID)
+		if err != nil || history == nil {
+			log.Printf("❌ %s: no history for %s: %v", backend.name, elsewhere.ID, err)
+			continue
+		}
+		var revisions []string
+		for _, revision := range history.Revisions {
+			revisions = append(revisions, fmt.Sprintf("%s: %s", revision.Source, fields(revision)))
+		}
+		fmt.Printf("   ✅ %s: %s has %d revisions (%s)\n", backend.name, history.JobID, len(history.Revisions), strings.Join(revisions, "; "))
+		if history.JobID != original.ID || strings.Join(revisions, "; ") != "RemoteOK: title,salary; WeWorkRemotely: benefits" {
+			log.Printf("❌ %s: unexpected revisions", backend.name)
+		}
+		if missing, err := backend.storage.History("remoteok-404"); err != nil || missing != nil {
+			log.Printf("❌ %s: history of a job never stored should be missing", backend.name)
+		}
+
+		changed, err := backend.storage.Search(models.SearchFilters{ChangedWithinDays: 7})
+		if err != nil {
+			log.Printf("❌ %s: error searching: %v", backend.name, err)
+			continue
+		}
+		salary, _ := backend.storage.Search(models.SearchFilters{ChangedWithinDays: 7, ChangedFields: []string{"salary_min"}})
+		location, _ := backend.storage.Search(models.SearchFilters{ChangedFields: []string{"location"}})
+		fmt.Printf("   ✅ %s: changed in 7 days %s, salary changed %s, location changed %d\n",
+			backend.name, jobIDs(changed.Jobs), jobIDs(salary.Jobs), len(location.Jobs))
+		if jobIDs(changed.Jobs) != original.ID || jobIDs(salary.Jobs) != original.ID || len(location.Jobs) != 0 {
+			log.Printf("❌ %s: unexpected recently changed jobs", backend.name)
+		}
+	}
+}
+
+func testFullTextSearch() {
+	// go test ./internal/fulltext checks stemming, ranking and snippets; here both backends
+	// rank and highlight the same way
+	sqlitePath := filepath.Join(os.TempDir(), fmt.Sprintf("fulltext-check-%d.db", time.Now().UnixNano()))
+	defer os.Remove(sqlitePath)
+	sqliteStorage, err := storage.NewSQLiteStorage(sqlitePath)
+	if err != nil {
+		log.Printf("❌ Error opening SQLite storage: %v", err)
+		return
+	}
+	defer sqliteStorage.Close()
+
+	backends := []struct {
+		name    string
+		storage storage.JobStorage
+	}{
+		{"memory", storage.NewInMemoryStorage()},
+		{"sqlite", sqliteStorage},
+	}
+
+	now := time.Now()
+	jobs := []models.Job{
+		{ID: "a", URL: "https://example.com/a", Source: "RemoteOK", Title: "Golang Backend Engineer", Company: "Acme",
+			Skills: []string{"Go", "PostgreSQL"}, SalaryMin: 110000, SalaryMax: 130000, SalaryCurrency: "USD", PostedDate: now.AddDate(0, 0, -3),
+			Description: "Acme builds payment APIs. You will design backend services in Go & run them on PostgreSQL."},
+		{ID: "b", URL: "https://example.com/b", Source: "RemoteOK", Title: "Backend Developer", Company: "Globex",
+			Skills: []string{"Python"}, SalaryMin: 140000, SalaryMax: 160000, SalaryCurrency: "USD", PostedDate: now.AddDate(0, 0, -1),
+			Description: "Globex maintains backend APIs in Python. Experience with golang is a plus."},
+		{ID: "c", URL: "https://example.com/c", Source: "RemoteOK", Title: "Frontend Developer", Company: "Initech",
+			Skills: []string{"React"}, PostedDate: now.AddDate(0, 0, -2),
+			Description: "Initech is looking for developers who love React and design systems."},
+		{ID: "d", URL: "https://example.com/d", Source: "RemoteOK", Title: "Data Analyst", Company: "Umbrella",
+			Skills: []string{"SQL"}, SalaryMin: 80000, SalaryMax: 100000, SalaryCurrency: "USD", PostedDate: now.AddDate(0, 0, -4),
+			Description: "Umbrella needs an analyst for reporting."},
+	}
+
+	searches := []struct {
+		name    string
+		filters models.SearchFilters
+		want    string
+	}{
+		{"golang backend", models.SearchFilters{Keywords: []string{"golang backend"}}, "a,b"},
+		{"developers", models.SearchFilters{Keywords: []string{"developers"}}, "c,b"},
+		{"developers by date", models.SearchFilters{Keywords: []string{"developers"}, Sort: models.SortDate}, "b,c"},
+		{"all by salary", models.SearchFilters{Sort: models.SortSalary}, "b,a,d,c"},
+		{"all", models.SearchFilters{}, "b,c,a,d"},
+	}
+
+	// Unlike jobIDs, keeps the order of the results
+	order := func(jobs []models.Job) string {
+		ids := make([]string, 0, len(jobs))
+		for _, job := range jobs {
+			ids = append(ids, job.ID)
+		}
+		return strings.Join(ids, ",")
+	}
+
+	check := func(name string, jobStorage storage.JobStorage) {
+		var results []string
+		for _, search := range searches {
+			response, err := jobStorage.Search(search.filters)
+			if err != nil {
+				log.Printf("❌ %s: error searching %s: %v", name, search.name, err)
+				return
+			}
+			results = append(results, fmt.Sprintf("%s → %s", search.name, order(response.Jobs)))
+			if order(response.Jobs) != search.want {
+				log.Printf("❌ %s: %s found %s, want %s", name, search.name, order(response.Jobs), search.want)
+			}
+		}
+		fmt.Printf("   ✅ %s: %s\n", name, strings.Join(results, "; "))
+
+		response, err := jobStorage.Search(models.SearchFilters{Keywords: []string{"golang backend"}})
+		if err != nil || len(response.Jobs) != 2 {
+			log.Printf("❌ %s: golang backend search failed: %v", name, err)
+			return
+		}
+		top, second := response.Jobs[0], response.Jobs[1]
+		fmt.Printf("   📋 %s: scores %.2f > %.2f, title %s, snippet %s\n", name, top.Score, second.Score, top.Highlight.Title, second.Highlight.Snippet)
+		if top.Score <= second.Score || top.Highlight == nil || second.Highlight == nil ||
+			top.Highlight.Title != "<mark>Golang</mark> <mark>Backend</mark> Engineer" ||
+			!strings.Contains(top.Highlight.Snippet, "<mark>backend</mark>") || !strings.Contains(top.Highlight.Snippet, "&amp;") ||
+			second.Highlight.Snippet != "Globex maintains <mark>backend</mark> APIs in Python. Experience with <mark>golang</mark> is a plus." {
+			log.Printf("❌ %s: unexpected scores or highlights", name)
+		}
+	}
+
+	for _, backend := range backends {
+		if err := backend.storage.Store(jobs); err != nil {
+			log.Printf("❌ %s: error storing jobs: %v", backend.name, err)
+			continue
+		}
+		check(backend.name, backend.storage)
+	}
+
+	// The SQLite index lives in memory and is rebuilt when the database is opened again
+	reopened, err := storage.NewSQLiteStorage(sqlitePath)
+	if err != nil {
+		log.Printf("❌ Error reopening SQLite storage: %v", err)
+		return
+	}
+	defer reopened.Close()
+	check("sqlite reopened", reopened)
+}
+
 func testMockScraper(ctx context.Context, filters models.SearchFilters) {
 	mockScraper := scraper.NewMockJobScraper("TestMockScraper")
 
@@ -74,51 +729,683 @@ func testMockScraper(ctx context.Context, filters models.SearchFilters) {
 	}
 }
 
-func testRemoteOKScraper(ctx context.Context, filters models.SearchFilters, client *http.Client) {
-	remoteScraper := scraper.NewRemoteOKScraper(client)
+// testIndeedScraper shows what the Indeed scraper finds in the saved feed; go test
+// ./internal/scraper checks it
+func testIndeedScraper(ctx context.Context, filters models.SearchFilters) {
+	// Serve the saved feed instead of hitting the network
+	server := newFixtureServer("indeed.rss")
+	defer server.Close()
 
-	jobs, err := remoteScraper.Scrape(ctx, filters)
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &rewriteTransport{target: server.URL},
+	}
+	indeedScraper := scraper.NewIndeedScraper(client)
+
+	jobs, err := indeedScraper.Scrape(ctx, filters)
 	if err != nil {
-		log.Printf("❌ RemoteOK scraper error: %v", err)
+		log.Printf("❌ Indeed scraper error: %v", err)
 		return
 	}
 
-	fmt.Printf("   ✅ RemoteOK scraper found %d jobs\n", len(jobs))
-	if len(jobs) > 0 {
-		job := jobs[0]
-		fmt.Printf("   📋 Sample job: %s at %s\n", job.Title, job.Company)
-		fmt.Printf("   🔗 URL: %s\n", job.URL)
-		if len(job.Skills) > 0 {
-			fmt.Printf("   🏷️  Skills: %v\n", job.Skills[:min(3, len(job.Skills))])
+	fmt.Printf("   ✅ Indeed scraper parsed %d jobs\n", len(jobs))
+	for _, job := range jobs {
+		fmt.Printf("   📋 %s at %s [%s, %s] (Salary: $%d-%d)\n",
+			job.Title, job.Company, job.Location, job.RemoteOption, job.SalaryMin, job.SalaryMax)
+	}
+}
+
+// testFeedScraper shows what the feed scraper finds in the saved Atom feed; go test
+// ./internal/scraper checks it
+func testFeedScraper(ctx context.Context, filters models.SearchFilters) {
+	server := newFixtureServer("jobs.atom")
+	defer server.Close()
+
+	// Map the salary and location from the feed's own extension elements
+	mapping := scraper.DefaultFeedMapping()
+	mapping.Title = scraper.FeedFieldRule{Paths: []string{"title"}, Pattern: `^(.+?) at `}
+	mapping.Location = scraper.FeedFieldRule{Paths: []string{"location"}}
+	mapping.Salary = scraper.FeedFieldRule{Paths: []string{"salary"}}
+
+	feedScraper := scraper.NewFeedScraper("GopherJobs", server.URL+"/feed.atom", &http.Client{Timeout: 10 * time.Second}, mapping)
+
+	jobs, err := feedScraper.Scrape(ctx, filters)
+	if err != nil {
+		log.Printf("❌ Feed scraper error: %v", err)
+		return
+	}
+
+	fmt.Printf("   ✅ Feed scraper parsed %d jobs\n", len(jobs))
+	for _, job := range jobs {
+		fmt.Printf("   📋 %s at %s [%s, %s, %s] (Salary: $%d-%d) Skills: %v\n",
+			job.Title, job.Company, job.Location, job.RemoteOption, job.ExperienceLevel, job.SalaryMin, job.SalaryMax, job.Skills)
+	}
+}
+
+func testSelectorScraper(ctx context.Context, filters models.SearchFilters) {
+	server := httptest.NewServer(http.FileServer(http.Dir("internal/scraper/testdata")))
+	defer server.Close()
+
+	site := scraper.SiteDefinition{
+		ID:              "gopherboard",
+		Name:            "GopherBoard",
+		SearchURL:       server.URL + "/board-1.html?q={query}&l={location}",
+		ListingSelector: "li.job-card",
+		Fields: scraper.SiteFields{
+			Title:       scraper.SelectorRule{Selector: ".job-title"},
+			Company:     scraper.SelectorRule{Selector: ".company"},
+			Location:    scraper.SelectorRule{Selector: ".location"},
+			Salary:      scraper.SelectorRule{Selector: ".pay"},
+			Date:        scraper.SelectorRule{Selector: "time", Attr: "datetime"},
+			Link:        scraper.SelectorRule{Selector: "a.job-link"},
+			Description: scraper.SelectorRule{Selector: ".summary"},
+		},
+		Pagination: scraper.Pagination{NextSelector: ".pager a.next", MaxPages: 3},
+		Enabled:    true,
+	}
+	if err := site.Validate(); err != nil {
+		log.Printf("❌ Site definition is invalid: %v", err)
+		return
+	}
+
+	jobs, err := scraper.NewSelectorScraper(site, &http.Client{Timeout: 10 * time.Second}).Scrape(ctx, filters)
+	if err != nil {
+		log.Printf("❌ Selector scraper error: %v", err)
+		return
+	}
+
+	fmt.Printf("   ✅ Selector scraper parsed %d jobs\n", len(jobs))
+	for _, job := range jobs {
+		fmt.Printf("   📋 %s at %s [%s, %s, %s] (Salary: $%d-%d) %s\n",
+			job.Title, job.Company, job.Location, job.RemoteOption, job.PostedDate.Format("2006-01-02"), job.SalaryMin, job.SalaryMax, job.URL)
+	}
+
+	if len(jobs) != 3 || jobs[0].Company != "Harbor Metrics" || jobs[0].SalaryMax != 150000 ||
+		jobs[0].URL != server.URL+"/jobs/1842" || jobs[1].SalaryMin != 40*2080 || jobs[1].RemoteOption != "hybrid" ||
+		jobs[2].Title != "Junior Frontend Developer" || jobs[2].PostedDate.Format("2006-01-02") != "2026-10-01" {
+		log.Printf("❌ Selector scraper output does not match the saved pages")
+	}
+}
+
+// testJobPostingScraper shows what the JobPosting scraper finds in the saved job pages;
+// go test ./internal/scraper checks it
+func testJobPostingScraper(ctx context.Context, filters models.SearchFilters) {
+	server := httptest.NewServer(http.FileServer(http.Dir("internal/scraper/testdata")))
+	defer server.Close()
+
+	pages := []string{server.URL + "/posting-jsonld.html", server.URL + "/posting-microdata.html"}
+	jobs, err := scraper.NewJobPostingScraper("Careers", pages, &http.Client{Timeout: 10 * time.Second}).Scrape(ctx, filters)
+	if err != nil {
+		log.Printf("❌ JobPosting scraper error: %v", err)
+		return
+	}
+
+	fmt.Printf("   ✅ JobPosting scraper parsed %d jobs\n", len(jobs))
+	for _, job := range jobs {
+		fmt.Printf("   📋 %s at %s [%s, %s, %s, %s] (Salary: %s %d-%d) Degree: %v %s\n",
+			job.Title, job.Company, job.Location, job.RemoteOption, job.EmploymentType, job.ExperienceLevel,
+			job.SalaryCurrency, job.SalaryMin, job.SalaryMax, job.DegreeRequired, job.URL)
+	}
+}
+
+func testDetailEnrichment(ctx context.Context) {
+	var detailFetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/remote-jobs/remote-programming-jobs":
+			http.ServeFile(w, r, "internal/scraper/testdata/wwr-listing.html")
+		case "/remote-jobs/quillstone-senior-backend-engineer":
+			atomic.AddInt32(&detailFetches, 1)
+			http.ServeFile(w, r, "internal/scraper/testdata/wwr-detail.html")
+		default:
+			http.NotFound(w, r)
 		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &rewriteTransport{target: server.URL},
+	}
+	manager := scraper.NewScraperManager(nil)
+	manager.AddScraper(scraper.NewWeWorkRemotelyScraper(client))
+	manager.SetDetailConcurrency(2)
+
+	// The second search must reuse the cached detail page
+	var jobs []models.Job
+	for i := 0; i < 2; i++ {
+		jobs = manager.GetAllJobs(manager.ScrapeAll(ctx, models.SearchFilters{}))
+	}
+
+	fmt.Printf("   ✅ Enriched %d jobs with %d detail page fetches\n", len(jobs), atomic.LoadInt32(&detailFetches))
+	for _, job := range jobs {
+		fmt.Printf("   📋 %s at %s [%s, degree: %v] (Salary: $%d-%d) Skills: %v Requirements: %d Benefits: %d\n",
+			job.Title, job.Company, job.ExperienceLevel, job.DegreeRequired, job.SalaryMin, job.SalaryMax,
+			job.Skills, len(job.Requirements), len(job.Benefits))
+	}
+
+	if len(jobs) != 2 || atomic.LoadInt32(&detailFetches) != 1 || jobs[0].SalaryMax != 160000 ||
+		!jobs[0].DegreeRequired || len(jobs[0].Requirements) != 3 || len(jobs[0].Benefits) != 2 ||
+		jobs[1].Description != "Remote Frontend Developer position at Fernhill" {
+		log.Printf("❌ Detail enrichment output does not match the saved pages")
 	}
 }
 
-func testWeWorkRemotelyScraper(ctx context.Context, filters models.SearchFilters, client *http.Client) {
-	wwrScraper := scraper.NewWeWorkRemotelyScraper(client)
+func testPagination(ctx context.Context) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
 
-	jobs, err := wwrScraper.Scrape(ctx, filters)
+		fmt.Fprint(w, "<html><body>")
+		if page <= 2 {
+			for i := 1; i <= 2; i++ {
+				fmt.Fprintf(w, `<article data-automation="jobListing"><a href="/job/%d%d"><h3 data-automation="jobTitle">Developer %d.%d</h3></a>`+
+					`<span data-automation="jobCompany">Tech Indonesia</span><span data-automation="jobLocation">Jakarta</span></article>`, page, i, page, i)
+			}
+		}
+		fmt.Fprint(w, "</body></html>")
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &rewriteTransport{target: server.URL},
+	}
+	jobStreet := scraper.NewJobStreetScraper(client, "id")
+
+	cases := []struct {
+		name     string
+		filters  models.SearchFilters
+		jobs     int
+		requests int32
+	}{
+		{"until an empty page", models.SearchFilters{JobTitle: "developer"}, 4, 3},
+		{"until the limit is met", models.SearchFilters{JobTitle: "developer", Limit: 3}, 4, 2},
+		{"a single page", models.SearchFilters{JobTitle: "developer", MaxPages: 1}, 2, 1},
+	}
+
+	for _, c := range cases {
+		atomic.StoreInt32(&requests, 0)
+		jobs, err := jobStreet.Scrape(ctx, c.filters)
+		if err != nil {
+			log.Printf("❌ Pagination error: %v", err)
+			continue
+		}
+
+		fmt.Printf("   ✅ Paged %s: %d jobs from %d pages\n", c.name, len(jobs), atomic.LoadInt32(&requests))
+		if len(jobs) != c.jobs || atomic.LoadInt32(&requests) != c.requests {
+			log.Printf("❌ Paging %s should give %d jobs from %d pages", c.name, c.jobs, c.requests)
+		}
+	}
+}
+
+func testRetries(ctx context.Context) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := atomic.AddInt32(&requests, 1)
+		switch {
+		case r.URL.Path == "/missing.html":
+			http.NotFound(w, r)
+		case r.URL.Path == "/overloaded.html":
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case attempt == 1:
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case attempt == 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			http.ServeFile(w, r, "internal/scraper/testdata/board-1.html")
+		}
+	}))
+	defer server.Close()
+
+	client := scraper.WithRetry(&http.Client{Timeout: 10 * time.Second}, scraper.RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  50 * time.Millisecond,
+		MaxDelay:   5 * time.Second,
+	})
+
+	cases := []struct {
+		name     string
+		page     string
+		jobs     int
+		retries  int
+		requests int32
+	}{
+		{"503 then 429 then 200", "/board.html", 2, 2, 3},
+		{"permanent 404", "/missing.html", 0, 0, 1},
+		{"Retry-After beyond the maximum delay", "/overloaded.html", 0, 0, 1},
+	}
+
+	for _, c := range cases {
+		atomic.StoreInt32(&requests, 0)
+
+		site := scraper.SiteDefinition{
+			ID:              "flaky",
+			Name:            "Flaky Board",
+			SearchURL:       server.URL + c.page,
+			ListingSelector: "li.job-card",
+			Fields:          scraper.SiteFields{Title: scraper.SelectorRule{Selector: ".job-title"}},
+		}
+		manager := scraper.NewScraperManager(nil)
+		manager.AddScraper(scraper.NewSelectorScraper(site, client))
+
+		result := manager.ScrapeAll(ctx, models.SearchFilters{})[0]
+		fmt.Printf("   ✅ %s: %d jobs, %d retries, %d requests (error: %v)\n",
+			c.name, len(result.Jobs), result.Retries, atomic.LoadInt32(&requests), result.Error)
+		if len(result.Jobs) != c.jobs || result.Retries != c.retries || atomic.LoadInt32(&requests) != c.requests {
+			log.Printf("❌ Retrying %s should give %d jobs after %d retries", c.name, c.jobs, c.retries)
+		}
+	}
+}
+
+func testRateLimiting(ctx context.Context) {
+	server := newFixtureServer("posting-jsonld.html")
+	defer server.Close()
+
+	// 600 requests per minute with a burst of 2: robots.txt and the first page at once,
+	// then one page every 100ms
+	registry := scraper.NewScraperRegistry()
+	var pages []string
+	for i := 1; i <= 5; i++ {
+		pages = append(pages, fmt.Sprintf("%s/jobs/%d", server.URL, i))
+	}
+	registry.AddCustomScraper("local-careers", scraper.ScraperConfig{
+		Name:      "Local Careers",
+		Enabled:   true,
+		Type:      "jsonld",
+		URL:       server.URL,
+		Pages:     pages,
+		RateLimit: 600,
+		Burst:     2,
+	})
+
+	s, err := registry.CreateScraper("local-careers")
 	if err != nil {
-		log.Printf("❌ WeWorkRemotely scraper error: %v", err)
+		log.Printf("❌ Error creating scraper: %v", err)
 		return
 	}
 
-	fmt.Printf("   ✅ WeWorkRemotely scraper found %d jobs\n", len(jobs))
-	if len(jobs) > 0 {
-		job := jobs[0]
-		fmt.Printf("   📋 Sample job: %s at %s\n", job.Title, job.Company)
-		fmt.Printf("   🔗 URL: %s\n", job.URL)
-		if job.Description != "" {
-			desc := job.Description
-			if len(desc) > 100 {
-				desc = desc[:100] + "..."
+	start := time.Now()
+	jobs, err := s.Scrape(ctx, models.SearchFilters{})
+	elapsed := time.Since(start)
+	if err != nil {
+		log.Printf("❌ Error scraping: %v", err)
+		return
+	}
+
+	stats := registry.RateLimitStats()
+	if len(stats) != 1 {
+		log.Printf("❌ Expected one rate-limited domain, got %d", len(stats))
+		return
+	}
+	fmt.Printf("   ✅ %d pages in %s: %s allowed %d/min (burst %d), %d of %d requests waited %dms in total\n",
+		len(jobs), elapsed.Round(10*time.Millisecond), stats[0].Domain, stats[0].RateLimit, stats[0].Burst,
+		stats[0].Delayed, stats[0].Requests, stats[0].TotalWaitMs)
+	if len(jobs) != 5 || stats[0].Requests != 6 || stats[0].Delayed != 4 || elapsed < 350*time.Millisecond {
+		log.Printf("❌ robots.txt and five pages at 600/min with a burst of 2 should take about 400ms with four waits")
+	}
+
+	// Concurrent callers of one bucket are spaced evenly, even below a second apart
+	limiter := scraper.NewBurstRateLimiter(20, time.Second, 1)
+	start = time.Now()
+	done := make(chan struct{})
+	for i := 0; i < 5; i++ {
+		go func() {
+			limiter.Wait(ctx)
+			done <- struct{}{}
+		}()
+	}
+	for i := 0; i < 5; i++ {
+		<-done
+	}
+	elapsed = time.Since(start)
+	fmt.Printf("   ✅ 5 concurrent callers at 20/s took %s\n", elapsed.Round(10*time.Millisecond))
+	if elapsed < 180*time.Millisecond || elapsed > 400*time.Millisecond {
+		log.Printf("❌ 5 callers at 20/s with a burst of 1 should take about 200ms")
+	}
+}
+
+func testRobots(ctx context.Context) {
+	var pageRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprint(w, "User-agent: *\nDisallow: /\n\nUser-agent: JobScraper\nAllow: /jobs/\nDisallow: /jobs/private\nCrawl-delay: 0.2\n")
+			return
+		}
+		atomic.AddInt32(&pageRequests, 1)
+		http.ServeFile(w, r, "internal/scraper/testdata/posting-jsonld.html")
+	}))
+	defer server.Close()
+
+	registry := scraper.NewScraperRegistry()
+	registry.AddCustomScraper("robots-careers", scraper.ScraperConfig{
+		Name:      "Robots Careers",
+		Enabled:   true,
+		Type:      "jsonld",
+		URL:       server.URL,
+		Pages:     []string{server.URL + "/jobs/1", server.URL + "/jobs/2", server.URL + "/jobs/private/3"},
+		RateLimit: 600,
+	})
+
+	s, err := registry.CreateScraper("robots-careers")
+	if err != nil {
+		log.Printf("❌ Error creating scraper: %v", err)
+		return
+	}
+
+	start := time.Now()
+	jobs, err := s.Scrape(ctx, models.SearchFilters{})
+	elapsed := time.Since(start)
+	if err != nil {
+		log.Printf("❌ Error scraping: %v", err)
+		return
+	}
+
+	stats := registry.RobotsStats()
+	if len(stats) != 1 {
+		log.Printf("❌ Expected robots.txt of one site, got %d", len(stats))
+		return
+	}
+	fmt.Printf("   ✅ %d jobs from %d page requests in %s: robots.txt %s, %d rules, crawl delay %.1fs, %d blocked\n",
+		len(jobs), atomic.LoadInt32(&pageRequests), elapsed.Round(10*time.Millisecond),
+		stats[0].Status, stats[0].Rules, stats[0].CrawlDelay, stats[0].BlockedCount)
+	if len(jobs) != 2 || atomic.LoadInt32(&pageRequests) != 2 || stats[0].BlockedCount != 1 || elapsed < 180*time.Millisecond {
+		log.Printf("❌ robots.txt should block the private page and space the others by the crawl delay")
+	}
+
+	_, err = s.(*scraper.JobPostingScraper).FetchDocument(ctx, server.URL+"/jobs/private/4")
+	var robotsErr *scraper.RobotsError
+	if !errors.As(err, &robotsErr) || atomic.LoadInt32(&pageRequests) != 2 {
+		log.Printf("❌ Private pages should fail with a RobotsError before being requested, got %v", err)
+		return
+	}
+	fmt.Printf("   ✅ %v\n", err)
+}
+
+func testHTTPCache(ctx context.Context) {
+	var requests, notModified int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		etag := `"` + r.URL.Path + `-v1"`
+		if r.Header.Get("If-None-Match") == etag {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		http.ServeFile(w, r, "internal/scraper/testdata/posting-jsonld.html")
+	}))
+	defer server.Close()
+
+	dir, err := os.MkdirTemp("", "http-cache")
+	if err != nil {
+		log.Printf("❌ Error creating cache directory: %v", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	cache, err := scraper.NewHTTPCache(dir, 200*time.Millisecond, scraper.DefaultHTTPCacheMaxBytes)
+	if err != nil {
+		log.Printf("❌ Error opening cache: %v", err)
+		return
+	}
+	client := &http.Client{Timeout: 10 * time.Second, Transport: cache.Transport(nil)}
+	s := scraper.NewJobPostingScraper("Cached Careers", []string{server.URL + "/jobs/1"}, client)
+
+	fetch := func(step string) {
+		jobs, err := s.Scrape(ctx, models.SearchFilters{})
+		stats := cache.Stats()
+		fmt.Printf("   ✅ %s: %d jobs, %d server requests (%d not modified), %d hits (%d revalidated), %d misses\n",
+			step, len(jobs), atomic.LoadInt32(&requests), atomic.LoadInt32(&notModified), stats.Hits, stats.Revalidated, stats.Misses)
+		if err != nil || len(jobs) != 1 {
+			log.Printf("❌ %s should still find the job (error: %v)", step, err)
+		}
+	}
+
+	fetch("first fetch")
+	fetch("fresh repeat")
+	time.Sleep(250 * time.Millisecond)
+	fetch("stale repeat")
+
+	stats := cache.Stats()
+	if atomic.LoadInt32(&requests) != 2 || atomic.LoadInt32(&notModified) != 1 ||
+		stats.Hits != 2 || stats.Revalidated != 1 || stats.Misses != 1 {
+		log.Printf("❌ Expected a miss, a hit without a request and a hit revalidated by a 304")
+	}
+
+	// Reopen the cache with room for about one and a half responses; a second URL evicts the first
+	cache, err = scraper.NewHTTPCache(dir, time.Minute, stats.Bytes*3/2)
+	if err != nil {
+		log.Printf("❌ Error reopening cache: %v", err)
+		return
+	}
+	reopened := cache.Stats().Entries
+	client.Transport = cache.Transport(nil)
+	scraper.NewJobPostingScraper("Cached Careers", []string{server.URL + "/jobs/2"}, client).Scrape(ctx, models.SearchFilters{})
+	stats = cache.Stats()
+	fmt.Printf("   ✅ reopened with %d entry, after a second URL: %d entry, %d of %d bytes\n",
+		reopened, stats.Entries, stats.Bytes, stats.MaxBytes)
+	if reopened != 1 || stats.Entries != 1 || stats.Bytes > stats.MaxBytes {
+		log.Printf("❌ The size cap should evict the older response")
+	}
+}
+
+func testCircuitBreaker(ctx context.Context) {
+	var requests, down int32 = 0, 1
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&down) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		http.ServeFile(w, r, "internal/scraper/testdata/board-1.html")
+	}))
+	defer server.Close()
+
+	site := scraper.SiteDefinition{
+		ID:              "outage",
+		Name:            "Outage Board",
+		SearchURL:       server.URL + "/board.html",
+		ListingSelector: "li.job-card",
+		Fields:          scraper.SiteFields{Title: scraper.SelectorRule{Selector: ".job-title"}},
+	}
+	manager := scraper.NewScraperManager(nil)
+	manager.AddScraper(scraper.NewSelectorScraper(site, &http.Client{Timeout: 10 * time.Second}))
+	manager.SetCircuitBreaker(2, 300*time.Millisecond)
+
+	search := func(step string) models.ScrapingResult {
+		result := manager.ScrapeAll(ctx, models.SearchFilters{})[0]
+		status := manager.CircuitStatus(site.Name)
+		fmt.Printf("   ✅ %s: %d jobs, circuit %s after %d failures, %d requests (error: %v)\n",
+			step, len(result.Jobs), status.State, status.ConsecutiveFailures, atomic.LoadInt32(&requests), result.Error)
+		return result
+	}
+
+	search("first failure")
+	search("second failure")
+	skipped := search("while open")
+	if !errors.Is(skipped.Error, scraper.ErrCircuitOpen) || atomic.LoadInt32(&requests) != 2 ||
+		manager.CircuitStatus(site.Name).State != scraper.CircuitOpen {
+		log.Printf("❌ Circuit breaker should skip the board after two failures")
+	}
+
+	// Recover and wait for the cooldown; the next search is the probe
+	atomic.StoreInt32(&down, 0)
+	time.Sleep(350 * time.Millisecond)
+	probe := search("probe after cooldown")
+	if probe.Error != nil || len(probe.Jobs) != 2 || manager.CircuitStatus(site.Name).State != scraper.CircuitClosed {
+		log.Printf("❌ Circuit breaker should close after a successful probe")
+	}
+}
+
+func testEndToEnd() {
+	// Occasional 429s and 500s make the scrapers retry on the way
+	board := fakeboard.New(fakeboard.Options{Jobs: 40, Seed: 7, PageSize: 3, RateLimitRate: 0.05, ErrorRate: 0.05})
+	boardServer := httptest.NewServer(board)
+	defer boardServer.Close()
+
+	router := mux.NewRouter()
+	api.SetupRoutes(router, api.Config{
+		Storage:           storage.NewInMemoryStorage(),
+		DetailConcurrency: 2,
+		ScraperBaseURL:    boardServer.URL,
+
+		// The board is local, so it can take far more than a real site
+		ScraperBaseURLRateLimit: scraper.HostRateLimit{RateLimit: 60000, Burst: 100},
+	})
+	apiServer := httptest.NewServer(router)
+	defer apiServer.Close()
+
+	start := time.Now()
+	resp, err := http.Get(apiServer.URL + "/api/v1/jobs/search?limit=500")
+	if err != nil {
+		log.Printf("❌ Error searching: %v", err)
+		return
+	}
+	var response models.SearchResponse
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	resp.Body.Close()
+	if err != nil {
+		log.Printf("❌ Error decoding search response: %v", err)
+		return
+	}
+
+	// The board lists the same jobs on every source, so postings merge across sources;
+	// count each source's postings whether stored as the job or merged into another
+	bySource := make(map[string]int)
+	enriched, merged := 0, 0
+	for _, job := range response.Jobs {
+		bySource[job.Source]++
+		for _, listing := range job.AlsoOn {
+			bySource[listing.Source]++
+		}
+		if len(job.AlsoOn) > 0 {
+			merged++
+		}
+		// Only detail pages give a closing date, and merging keeps it
+		if dedupe.HasSource(job, "WeWorkRemotely") && job.ValidThrough != nil {
+			enriched++
+		}
+	}
+	fmt.Printf("   ✅ %d jobs stored in %s: %d RemoteOK, %d WeWorkRemotely (%d from detail pages), %d Indeed postings; %d jobs listed on several sources\n",
+		response.Total, time.Since(start).Round(10*time.Millisecond), bySource["RemoteOK"],
+		bySource["WeWorkRemotely"], enriched, bySource["Indeed"], merged)
+	if bySource["RemoteOK"] != 40 || bySource["WeWorkRemotely"] == 0 || bySource["Indeed"] == 0 || enriched == 0 {
+		log.Printf("❌ Every built-in scraper should have read the fake board, and WeWorkRemotely its detail pages")
+		return
+	}
+	if merged == 0 {
+		log.Printf("❌ Postings of the same job on several sources were not merged")
+	}
+
+	// Stored jobs are served by ID
+	resp, err = http.Get(apiServer.URL + "/api/v1/jobs/" + url.PathEscape(response.Jobs[0].ID))
+	if err != nil {
+		log.Printf("❌ Error getting job: %v", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("❌ Stored job %s returned status %d", response.Jobs[0].ID, resp.StatusCode)
+	}
+
+	// Searching again finds the same postings under the same IDs and updates them in place
+	resp, err = http.Get(apiServer.URL + "/api/v1/jobs/search?limit=500")
+	if err != nil {
+		log.Printf("❌ Error searching again: %v", err)
+		return
+	}
+	var again models.SearchResponse
+	err = json.NewDecoder(resp.Body).Decode(&again)
+	resp.Body.Close()
+	if err != nil {
+		log.Printf("❌ Error decoding search response: %v", err)
+		return
+	}
+
+	// The mock site makes up a different number of jobs on every search, so only the
+	// fake board's jobs are counted
+	lastSeen := make(map[string]time.Time, len(response.Jobs))
+	boardJobs := 0
+	for _, job := range response.Jobs {
+		lastSeen[job.ID] = job.LastSeen
+		if job.Source != "MockJobSite" {
+			boardJobs++
+		}
+	}
+	kept, refreshed, boardJobsAgain := 0, 0, 0
+	for _, job := range again.Jobs {
+		if job.Source != "MockJobSite" {
+			boardJobsAgain++
+		}
+		if previous, exists := lastSeen[job.ID]; exists {
+			kept++
+			if job.LastSeen.After(previous) {
+				refreshed++
 			}
-			fmt.Printf("   📄 Description: %s\n", desc)
 		}
 	}
+	fmt.Printf("   ✅ Second search: %d board jobs (%d before), %d jobs kept their ID, %d seen again\n",
+		boardJobsAgain, boardJobs, kept, refreshed)
+	if boardJobsAgain != boardJobs || kept != len(response.Jobs) || refreshed == 0 {
+		log.Printf("❌ A second search should update the stored jobs instead of adding them again")
+	}
+
+	resp, err = http.Get(apiServer.URL + "/api/v1/jobs/" + url.PathEscape(response.Jobs[0].ID))
+	if err != nil {
+		log.Printf("❌ Error getting job: %v", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("❌ Job %s from the first search returned status %d after the second", response.Jobs[0].ID, resp.StatusCode)
+	}
+
+	resp, err = http.Get(apiServer.URL + "/api/v1/jobs/" + url.PathEscape(response.Jobs[0].ID) + "/history")
+	if err != nil {
+		log.Printf("❌ Error getting job history: %v", err)
+		return
+	}
+	var history models.JobHistory
+	err = json.NewDecoder(resp.Body).Decode(&history)
+	resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusOK || history.JobID != response.Jobs[0].ID {
+		log.Printf("❌ History of job %s returned status %d: %v", response.Jobs[0].ID, resp.StatusCode, err)
+	} else {
+		fmt.Printf("   ✅ History of %s: %d revisions\n", history.JobID, len(history.Revisions))
+	}
+}
+
+// newFixtureServer serves a file from internal/scraper/testdata for every request
+func newFixtureServer(name string) *httptest.Server {
+	fixture := http.FileServer(http.Dir("internal/scraper/testdata"))
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.URL.Path = "/" + name
+		fixture.ServeHTTP(w, r)
+	}))
 }
 
-func testScraperRegistry(ctx context.Context, filters models.SearchFilters) {
+// rewriteTransport sends every request to a local test server
+type rewriteTransport struct {
+	target string
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target, err := url.Parse(t.target)
+	if err != nil {
+		return nil, err
+	}
+
+	req = req.Clone(req.Context())
+	req.URL.Scheme = target.Scheme
+	req.URL.Host = target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func testScraperRegistry() {
 	registry := scraper.NewScraperRegistry()
 
 	// List all scrapers
@@ -136,23 +1423,13 @@ func testScraperRegistry(ctx context.Context, filters models.SearchFilters) {
 		fmt.Printf("   • %s: %s [%s]\n", config.Name, status, config.Type)
 	}
 
-	// Test enabled scrapers
+	// Creating the enabled scrapers checks their configuration without going online
 	enabledScrapers := registry.GetEnabledScrapers()
-	fmt.Printf("\n   🚀 Testing %d enabled scrapers:\n", len(enabledScrapers))
-
-	totalJobs := 0
-	for _, scraper := range enabledScrapers {
-		jobs, err := scraper.Scrape(ctx, filters)
-		if err != nil {
-			log.Printf("   ❌ Scraper error: %v", err)
-			continue
-		}
-
-		fmt.Printf("   ✅ Found %d jobs from this scraper\n", len(jobs))
-		totalJobs += len(jobs)
+	fmt.Printf("\n   🚀 Created %d enabled scrapers:", len(enabledScrapers))
+	for _, s := range enabledScrapers {
+		fmt.Printf(" %s", s.Name())
 	}
-
-	fmt.Printf("   🎯 Total jobs found: %d\n", totalJobs)
+	fmt.Println()
 }
 
 // Helper function for min
@@ -4,11 +4,10 @@ go 1.25.1
 
 require (
 	github.com/PuerkitoBio/goquery v1.8.1
+	github.com/andybalholm/cascadia v1.3.1
 	github.com/gorilla/mux v1.8.0
+	github.com/mattn/go-sqlite3 v1.14.22
 	github.com/rs/cors v1.10.1
 )
 
-require (
-	github.com/andybalholm/cascadia v1.3.1 // indirect
-	golang.org/x/net v0.7.0 // indirect
-)
+require golang.org/x/net v0.7.0 // indirect
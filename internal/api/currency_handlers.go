@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/currency"
+)
+
+// CurrencyHandler serves and updates the exchange-rate table used to compare salaries
+type CurrencyHandler struct {
+	path string // file the table is saved to; empty keeps updates in memory
+}
+
+// NewCurrencyHandler creates a new exchange-rate handler
+func NewCurrencyHandler(path string) *CurrencyHandler {
+	return &CurrencyHandler{path: path}
+}
+
+// GetRates returns the exchange-rate table in use
+func (h *CurrencyHandler) GetRates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    currency.Rates(),
+	})
+}
+
+// UpdateRates replaces the exchange-rate table and saves it
+func (h *CurrencyHandler) UpdateRates(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	var table currency.Table
+	if err := json.NewDecoder(r.Body).Decode(&table); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := table.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if h.path != "" {
+		if err := currency.SaveTable(h.path, &table); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	currency.SetRates(&table)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Exchange rates updated successfully",
+		"data":    &table,
+	})
+}
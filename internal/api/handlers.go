@@ -3,14 +3,18 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/Illuminateee/web-scrapper.git/internal/currency"
 	"github.com/Illuminateee/web-scrapper.git/internal/models"
 	"github.com/Illuminateee/web-scrapper.git/internal/scraper"
+	"github.com/Illuminateee/web-scrapper.git/internal/skills"
 	"github.com/Illuminateee/web-scrapper.git/internal/storage"
 	"github.com/gorilla/mux"
 )
@@ -21,46 +25,63 @@ type JobHandler struct {
 	storage        storage.JobStorage
 }
 
-// NewJobHandler creates a new job handler
-func NewJobHandler() *JobHandler {
-	// Initialize scraper manager
-	scraperManager := scraper.NewScraperManager()
-
-	// Create HTTP client for scrapers
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			MaxIdleConns:       10,
-			IdleConnTimeout:    30 * time.Second,
-			DisableCompression: true,
-		},
+// NewJobHandler creates a new job handler backed by the given storage.
+// The scrapers used for each search are taken from the registry.
+func NewJobHandler(jobStorage storage.JobStorage, registry *scraper.ScraperRegistry) *JobHandler {
+	return &JobHandler{
+		scraperManager: scraper.NewScraperManager(registry),
+		storage:        jobStorage,
 	}
+}
 
-	// Add real job site scrapers
-	scraperManager.AddScraper(scraper.NewRemoteOKScraper(client))
-	scraperManager.AddScraper(scraper.NewWeWorkRemotelyScraper(client))
+// Config holds the dependencies and settings used to build the API
+type Config struct {
+	Storage   storage.JobStorage
+	SearchTTL time.Duration      // how long finished asynchronous searches are kept
+	Sites     *scraper.SiteStore // declarative site definitions; nil disables the /sites endpoints
 
-	// Add authenticated scrapers (demo mode)
-	linkedinScraper := scraper.NewLinkedInScraper(client)
-	jobstreetScraper := scraper.NewJobStreetScraper(client, "id") // Indonesia
-	scraperManager.AddScraper(linkedinScraper)
-	scraperManager.AddScraper(jobstreetScraper)
+	// DetailConcurrency is how many job detail pages are fetched at once per source
+	// to fill in listing-only jobs; 0 skips detail pages
+	DetailConcurrency int
 
-	// Keep one mock scraper for additional data
-	scraperManager.AddScraper(scraper.NewMockJobScraper("MockJobSite"))
+	// HTTPCache answers repeated scraper requests from disk; nil disables it
+	HTTPCache *scraper.HTTPCache
 
-	// Initialize storage
-	storage := storage.NewInMemoryStorage()
+	// ScraperBaseURL sends the built-in scrapers to this host instead of the real sites,
+	// such as a local cmd/fakeboard; empty uses the real sites
+	ScraperBaseURL string
 
-	return &JobHandler{
-		scraperManager: scraperManager,
-		storage:        storage,
-	}
+	// ScraperBaseURLRateLimit is the request budget of the ScraperBaseURL host; zero
+	// applies the default host limit
+	ScraperBaseURLRateLimit scraper.HostRateLimit
+
+	// ExchangeRatesPath is the file exchange-rate updates are saved to; empty keeps them in memory
+	ExchangeRatesPath string
 }
 
+// DefaultDetailConcurrency is the number of detail pages fetched at once when none is configured
+const DefaultDetailConcurrency = 4
+
 // SetupRoutes sets up the API routes
-func SetupRoutes(router *mux.Router) {
-	handler := NewJobHandler()
+func SetupRoutes(router *mux.Router, config Config) {
+	registry := scraper.NewScraperRegistry()
+	if config.HTTPCache != nil {
+		registry.SetHTTPCache(config.HTTPCache)
+	}
+	if config.ScraperBaseURL != "" {
+		// Only the built-in scrapers have a fixed site to replace
+		for name, scraperConfig := range registry.ListScrapers() {
+			switch scraperConfig.Type {
+			case "api", "public", "authenticated":
+				registry.SetBaseURL(name, config.ScraperBaseURL)
+			}
+		}
+		if baseURL, err := url.Parse(config.ScraperBaseURL); err == nil {
+			registry.SetHostRateLimit(baseURL.Hostname(), config.ScraperBaseURLRateLimit)
+		}
+	}
+	handler := NewJobHandler(config.Storage, registry)
+	handler.scraperManager.SetDetailConcurrency(config.DetailConcurrency)
 
 	// API routes
 	api := router.PathPrefix("/api/v1").Subrouter()
@@ -68,35 +89,76 @@ func SetupRoutes(router *mux.Router) {
 	// Job search endpoint
 	api.HandleFunc("/jobs/search", handler.SearchJobs).Methods("GET", "OPTIONS")
 
+	// Streaming search, one Server-Sent Event per source
+	api.HandleFunc("/jobs/search/stream", handler.SearchJobsStream).Methods("GET", "OPTIONS")
+
 	// Advanced search with custom job sites
 	api.HandleFunc("/jobs/search/advanced", handler.AdvancedSearch).Methods("POST", "OPTIONS")
 
+	// Asynchronous search lifecycle
+	searchHandler := NewSearchHandler(handler.scraperManager, handler.storage, config.SearchTTL)
+	api.HandleFunc("/searches", searchHandler.SubmitSearch).Methods("POST", "OPTIONS")
+	api.HandleFunc("/searches/{id}", searchHandler.GetSearch).Methods("GET", "OPTIONS")
+	api.HandleFunc("/searches/{id}", searchHandler.CancelSearch).Methods("DELETE", "OPTIONS")
+
 	// Get job by ID
 	api.HandleFunc("/jobs/{id}", handler.GetJob).Methods("GET", "OPTIONS")
 
+	// Revisions of a stored job
+	api.HandleFunc("/jobs/{id}/history", handler.GetJobHistory).Methods("GET", "OPTIONS")
+
 	// Health check
 	api.HandleFunc("/health", handler.HealthCheck).Methods("GET")
 
 	// Analytics endpoint
 	api.HandleFunc("/analytics", handler.GetAnalytics).Methods("GET", "OPTIONS")
 
+	// Skill taxonomy used for extraction, filters and top skills
+	api.HandleFunc("/skills", handler.ListSkills).Methods("GET", "OPTIONS")
+
 	// Clear cache endpoint
 	api.HandleFunc("/cache/clear", handler.ClearCache).Methods("POST", "OPTIONS")
 
+	// Exchange rates used to compare salaries in different currencies
+	currencyHandler := NewCurrencyHandler(config.ExchangeRatesPath)
+	api.HandleFunc("/currency/rates", currencyHandler.GetRates).Methods("GET", "OPTIONS")
+	api.HandleFunc("/currency/rates", currencyHandler.UpdateRates).Methods("PUT")
+
 	// Scraper management endpoints
-	registry := scraper.NewScraperRegistry()
-	scraperHandler := NewScraperHandler(registry)
+	scraperHandler := NewScraperHandler(registry, handler.scraperManager)
 
 	api.HandleFunc("/scrapers", scraperHandler.ListScrapers).Methods("GET", "OPTIONS")
+	api.HandleFunc("/scrapers", scraperHandler.AddScraper).Methods("POST")
 	api.HandleFunc("/scrapers/{name}", scraperHandler.GetScraperConfig).Methods("GET", "OPTIONS")
 	api.HandleFunc("/scrapers/{name}/enable", scraperHandler.EnableScraper).Methods("POST", "OPTIONS")
 	api.HandleFunc("/scrapers/{name}/disable", scraperHandler.DisableScraper).Methods("POST", "OPTIONS")
+	api.HandleFunc("/ratelimits", scraperHandler.RateLimits).Methods("GET", "OPTIONS")
+	api.HandleFunc("/robots", scraperHandler.Robots).Methods("GET", "OPTIONS")
+	api.HandleFunc("/cache/http", scraperHandler.HTTPCache).Methods("GET", "OPTIONS")
+
+	// Declarative site definitions, each registered as a "css" scraper
+	if config.Sites != nil {
+		for _, site := range config.Sites.List() {
+			registry.RegisterSite(site)
+		}
+
+		siteHandler := NewSiteHandler(registry, config.Sites)
+		api.HandleFunc("/sites", siteHandler.ListSites).Methods("GET", "OPTIONS")
+		api.HandleFunc("/sites", siteHandler.CreateSite).Methods("POST")
+		api.HandleFunc("/sites/{id}", siteHandler.GetSite).Methods("GET", "OPTIONS")
+		api.HandleFunc("/sites/{id}", siteHandler.UpdateSite).Methods("PUT")
+		api.HandleFunc("/sites/{id}", siteHandler.DeleteSite).Methods("DELETE")
+	}
 }
 
 // SearchJobs handles job search requests
 func (h *JobHandler) SearchJobs(w http.ResponseWriter, r *http.Request) {
 	// Parse query parameters
-	filters := h.parseSearchFilters(r)
+	filters, err := h.parseSearchFilters(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -109,8 +171,8 @@ func (h *JobHandler) SearchJobs(w http.ResponseWriter, r *http.Request) {
 	allJobs := h.scraperManager.GetAllJobs(results)
 
 	// Store jobs in cache
-	if err := h.storage.Store(allJobs); err != nil {
-		log.Printf("Error storing jobs: %v", err)
+	for _, result := range results {
+		storeResult(h.storage, result, filters)
 	}
 
 	// Search stored jobs
@@ -134,6 +196,12 @@ func (h *JobHandler) AdvancedSearch(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
+	code, err := salaryCurrency(searchRequest.Filters.SalaryCurrency)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	searchRequest.Filters.SalaryCurrency = code
 
 	// Create context with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -142,13 +210,18 @@ func (h *JobHandler) AdvancedSearch(w http.ResponseWriter, r *http.Request) {
 	log.Printf("Advanced search with filters: %+v", searchRequest.Filters)
 	log.Printf("Custom job sites: %+v", searchRequest.JobSites)
 
-	// For now, we'll use the existing scrapers but could be extended to use custom sites
-	results := h.scraperManager.ScrapeAll(ctx, searchRequest.Filters)
+	// Custom job sites replace the default scrapers for this search
+	var results []models.ScrapingResult
+	if sites := requestedJobSites(searchRequest); len(sites) > 0 {
+		results = h.scraperManager.ScrapeSites(ctx, searchRequest.Filters, sites, nil)
+	} else {
+		results = h.scraperManager.ScrapeAll(ctx, searchRequest.Filters)
+	}
 	allJobs := h.scraperManager.GetAllJobs(results)
 
 	// Store jobs in cache
-	if err := h.storage.Store(allJobs); err != nil {
-		log.Printf("Error storing jobs: %v", err)
+	for _, result := range results {
+		storeResult(h.storage, result, searchRequest.Filters)
 	}
 
 	// Search stored jobs
@@ -165,29 +238,88 @@ func (h *JobHandler) AdvancedSearch(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// storeResult stores the jobs of a successful scrape and records a complete scrape, so
+// that jobs the source no longer lists are closed. A partial scrape (a page failed or a
+// limit stopped it) leaves out jobs that are still listed, and a scrape that found nothing
+// is more likely broken than proof that every job is gone, so neither closes anything.
+func storeResult(jobStorage storage.JobStorage, result models.ScrapingResult, filters models.SearchFilters) {
+	if result.Error != nil {
+		return
+	}
+	if err := jobStorage.Store(result.Jobs); err != nil {
+		log.Printf("Error storing jobs: %v", err)
+		return
+	}
+	if !result.Complete || len(result.Jobs) == 0 {
+		return
+	}
+	if err := jobStorage.RecordScrape(result.Source, filters, result.Jobs); err != nil {
+		log.Printf("Error recording scrape of %s: %v", result.Source, err)
+	}
+}
+
+// requestedJobSites combines the active job sites and the job site URLs from the filters
+func requestedJobSites(searchRequest models.SearchRequest) []models.JobSiteConfig {
+	var sites []models.JobSiteConfig
+	for _, site := range searchRequest.JobSites {
+		if site.Active {
+			sites = append(sites, site)
+		}
+	}
+
+	for _, site := range searchRequest.Filters.JobSites {
+		if site == "" {
+			continue
+		}
+		config := models.JobSiteConfig{Name: site, Active: true}
+		if strings.HasPrefix(site, "http://") || strings.HasPrefix(site, "https://") {
+			config.URL = site
+		}
+		sites = append(sites, config)
+	}
+
+	return sites
+}
+
 // GetJob handles getting a specific job by ID
 func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	jobID := vars["id"]
 
-	// Search for the job by ID
-	filters := models.SearchFilters{Limit: 1000} // Get all jobs to find by ID
-	response, err := h.storage.Search(filters)
+	// A listing merged as a duplicate resolves to its canonical job
+	job, err := h.storage.Get(jobID)
 	if err != nil {
-		http.Error(w, "Error searching jobs", http.StatusInternalServerError)
+		log.Printf("Error reading job %s: %v", jobID, err)
+		http.Error(w, "Error reading job", http.StatusInternalServerError)
+		return
+	}
+	if job == nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
 		return
 	}
 
-	// Find job by ID
-	for _, job := range response.Jobs {
-		if job.ID == jobID {
-			w.Header().Set("Content-Type", "application/json")
-			json.NewEncoder(w).Encode(job)
-			return
-		}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
+
+// GetJobHistory returns the revisions of a stored job, found by its ID or the ID of a
+// listing merged into it
+func (h *JobHandler) GetJobHistory(w http.ResponseWriter, r *http.Request) {
+	jobID := mux.Vars(r)["id"]
+
+	history, err := h.storage.History(jobID)
+	if err != nil {
+		log.Printf("Error reading history of job %s: %v", jobID, err)
+		http.Error(w, "Error reading job history", http.StatusInternalServerError)
+		return
+	}
+	if history == nil {
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
 	}
 
-	http.Error(w, "Job not found", http.StatusNotFound)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
 }
 
 // GetAnalytics handles analytics requests
@@ -203,6 +335,17 @@ func (h *JobHandler) GetAnalytics(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response.Analytics)
 }
 
+// ListSkills returns the skill taxonomy
+func (h *JobHandler) ListSkills(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    skills.Default().Skills(),
+	})
+}
+
 // ClearCache handles cache clearing requests
 func (h *JobHandler) ClearCache(w http.ResponseWriter, r *http.Request) {
 	if err := h.storage.Clear(); err != nil {
@@ -223,8 +366,21 @@ func (h *JobHandler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// parseSearchFilters parses search filters from query parameters
-func (h *JobHandler) parseSearchFilters(r *http.Request) models.SearchFilters {
+// salaryCurrency normalizes the currency a search asks salaries in. A currency without
+// an exchange rate is an error, since salaries could not be converted to it.
+func salaryCurrency(code string) (string, error) {
+	if code == "" {
+		return "", nil
+	}
+	if !currency.Rates().Has(code) {
+		return "", fmt.Errorf("unknown salary_currency %q", code)
+	}
+	return currency.Normalize(code), nil
+}
+
+// parseSearchFilters parses search filters from query parameters. Invalid values are
+// ignored, except a salary currency without an exchange rate.
+func (h *JobHandler) parseSearchFilters(r *http.Request) (models.SearchFilters, error) {
 	filters := models.SearchFilters{
 		Limit: 50, // Default limit
 	}
@@ -257,6 +413,68 @@ func (h *JobHandler) parseSearchFilters(r *http.Request) models.SearchFilters {
 		}
 	}
 
+	// Search radius
+	if radius := r.URL.Query().Get("radius"); radius != "" {
+		if val, err := strconv.Atoi(radius); err == nil && val > 0 {
+			filters.Radius = val
+		}
+	}
+
+	// Recency
+	if days := r.URL.Query().Get("posted_within_days"); days != "" {
+		if val, err := strconv.Atoi(days); err == nil && val > 0 {
+			filters.PostedWithinDays = val
+		}
+	}
+
+	// Lifecycle status: open, closed or expired
+	if status := r.URL.Query().Get("status"); status != "" {
+		filters.Status = strings.ToLower(strings.TrimSpace(status))
+	}
+
+	// Jobs closed recently
+	if days := r.URL.Query().Get("closed_within_days"); days != "" {
+		if val, err := strconv.Atoi(days); err == nil && val > 0 {
+			filters.ClosedWithinDays = val
+		}
+	}
+
+	// Jobs open for a long time
+	if days := r.URL.Query().Get("open_longer_than_days"); days != "" {
+		if val, err := strconv.Atoi(days); err == nil && val > 0 {
+			filters.OpenLongerThanDays = val
+		}
+	}
+
+	// Jobs posted again after closing
+	if reposts := r.URL.Query().Get("min_reposts"); reposts != "" {
+		if val, err := strconv.Atoi(reposts); err == nil && val > 0 {
+			filters.MinReposts = val
+		}
+	}
+
+	// Jobs changed recently, optionally only in some fields
+	if days := r.URL.Query().Get("changed_within_days"); days != "" {
+		if val, err := strconv.Atoi(days); err == nil && val > 0 {
+			filters.ChangedWithinDays = val
+		}
+	}
+
+	if fields := r.URL.Query().Get("changed_fields"); fields != "" {
+		filters.ChangedFields = strings.Split(fields, ",")
+		// Trim spaces
+		for i, field := range filters.ChangedFields {
+			filters.ChangedFields[i] = strings.TrimSpace(field)
+		}
+	}
+
+	// Result pages fetched per source
+	if maxPages := r.URL.Query().Get("max_pages"); maxPages != "" {
+		if val, err := strconv.Atoi(maxPages); err == nil && val > 0 {
+			filters.MaxPages = val
+		}
+	}
+
 	// Job category
 	if category := r.URL.Query().Get("job_category"); category != "" {
 		filters.JobCategory = category
@@ -289,6 +507,13 @@ func (h *JobHandler) parseSearchFilters(r *http.Request) models.SearchFilters {
 		}
 	}
 
+	// Currency of the salary filters and analytics
+	code, err := salaryCurrency(r.URL.Query().Get("salary_currency"))
+	if err != nil {
+		return filters, err
+	}
+	filters.SalaryCurrency = code
+
 	// Experience level
 	if expLevel := r.URL.Query().Get("experience_level"); expLevel != "" {
 		filters.ExperienceLevel = expLevel
@@ -324,6 +549,14 @@ func (h *JobHandler) parseSearchFilters(r *http.Request) models.SearchFilters {
 		filters.Industry = industry
 	}
 
+	// Result order: relevance, date or salary
+	if order := r.URL.Query().Get("sort"); order != "" {
+		switch order = strings.ToLower(strings.TrimSpace(order)); order {
+		case models.SortRelevance, models.SortDate, models.SortSalary:
+			filters.Sort = order
+		}
+	}
+
 	// Pagination
 	if limit := r.URL.Query().Get("limit"); limit != "" {
 		if val, err := strconv.Atoi(limit); err == nil && val > 0 {
@@ -337,5 +570,5 @@ func (h *JobHandler) parseSearchFilters(r *http.Request) models.SearchFilters {
 		}
 	}
 
-	return filters
+	return filters, nil
 }
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/scraper"
+	"github.com/Illuminateee/web-scrapper.git/internal/storage"
+	"github.com/gorilla/mux"
+)
+
+// postingPage is a job page with one schema.org JobPosting
+const postingPage = `<html><head><script type="application/ld+json">
+{"@context": "https://schema.org", "@type": "JobPosting", "title": "Go Engineer %[1]s",
+ "hiringOrganization": {"@type": "Organization", "name": "Acme %[1]s"},
+ "identifier": "%[1]s", "datePosted": "2026-10-01", "description": "Build services in Go."}
+</script></head><body></body></html>`
+
+// feedItem is an RSS item of a Go job numbered id
+const feedItem = `<item><title>Go Engineer %[1]s</title><guid>%[1]s</guid>
+<link>https://jobs.example.com/%[1]s</link><source>Acme %[1]s</source>
+<description>Build services in Go.</description></item>`
+
+// newTestLifecycleServer serves the search of a careers site with one job per page. The
+// second job is listed while second is set.
+func newTestLifecycleServer(t *testing.T, second *atomic.Bool) (*httptest.Server, *storage.InMemoryStorage) {
+	t.Helper()
+
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/jobs/")
+		if id == "2" && !second.Load() {
+			fmt.Fprint(w, "<html><body>This job is no longer available.</body></html>")
+			return
+		}
+		fmt.Fprintf(w, postingPage, id)
+	}))
+	t.Cleanup(site.Close)
+
+	jobStorage := storage.NewInMemoryStorage()
+	jobStorage.SetClosedAfterMisses(1)
+
+	manager := scraper.NewScraperManager(nil)
+	pages := []string{site.URL + "/jobs/1", site.URL + "/jobs/2"}
+	manager.AddScraper(scraper.NewJobPostingScraper("Careers", pages, site.Client()))
+	handler := &JobHandler{scraperManager: manager, storage: jobStorage}
+
+	server := httptest.NewServer(http.HandlerFunc(handler.SearchJobs))
+	t.Cleanup(server.Close)
+	return server, jobStorage
+}
+
+// search runs a search and fails the test when it does not succeed
+func search(t *testing.T, url string) {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: status %d", url, resp.StatusCode)
+	}
+}
+
+// jobStatus returns the stored status of a job
+func jobStatus(t *testing.T, jobStorage storage.JobStorage, id string) string {
+	t.Helper()
+
+	job, err := jobStorage.Get(id)
+	if err != nil || job == nil {
+		t.Fatalf("Get(%q) = %v, %v", id, job, err)
+	}
+	return job.Status
+}
+
+func TestLimitedSearchClosesNothing(t *testing.T) {
+	var second atomic.Bool
+	second.Store(true)
+	server, jobStorage := newTestLifecycleServer(t, &second)
+
+	search(t, server.URL)
+	if status := jobStatus(t, jobStorage, "careers-2"); status != models.JobStatusOpen {
+		t.Fatalf("careers-2 is %s after the first search, want open", status)
+	}
+
+	// The limit stops the scraper after the first page, so the second job is not seen
+	search(t, server.URL+"?limit=1")
+	if status := jobStatus(t, jobStorage, "careers-2"); status != models.JobStatusOpen {
+		t.Errorf("careers-2 is %s after a search limited to one job, want open", status)
+	}
+
+	// A complete search that no longer finds it does close it
+	second.Store(false)
+	search(t, server.URL)
+	if status := jobStatus(t, jobStorage, "careers-2"); status != models.JobStatusClosed {
+		t.Errorf("careers-2 is %s after a complete search without it, want closed", status)
+	}
+	if status := jobStatus(t, jobStorage, "careers-1"); status != models.JobStatusOpen {
+		t.Errorf("careers-1 is %s, want open", status)
+	}
+}
+
+func TestCappedFeedClosesNothing(t *testing.T) {
+	// The feed carries the two newest jobs
+	var newest atomic.Value
+	newest.Store([]string{"2", "1"})
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel>`)
+		for _, id := range newest.Load().([]string) {
+			fmt.Fprintf(w, feedItem, id)
+		}
+		fmt.Fprint(w, `</channel></rss>`)
+	}))
+	defer feed.Close()
+
+	jobStorage := storage.NewInMemoryStorage()
+	jobStorage.SetClosedAfterMisses(1)
+	manager := scraper.NewScraperManager(nil)
+	manager.AddScraper(scraper.NewFeedScraper("Gopher Jobs", feed.URL, feed.Client(), scraper.DefaultFeedMapping()))
+	handler := &JobHandler{scraperManager: manager, storage: jobStorage}
+	server := httptest.NewServer(http.HandlerFunc(handler.SearchJobs))
+	defer server.Close()
+
+	search(t, server.URL)
+
+	// A new job pushes the oldest off the feed, though it is still listed on the site
+	newest.Store([]string{"3", "2"})
+	search(t, server.URL)
+	for _, id := range []string{"gopher-jobs-1", "gopher-jobs-2", "gopher-jobs-3"} {
+		if status := jobStatus(t, jobStorage, id); status != models.JobStatusOpen {
+			t.Errorf("%s is %s after it rolled off the feed, want open", id, status)
+		}
+	}
+}
+
+func TestUnknownSalaryCurrency(t *testing.T) {
+	handler := &JobHandler{scraperManager: scraper.NewScraperManager(nil), storage: storage.NewInMemoryStorage()}
+	router := mux.NewRouter()
+	router.HandleFunc("/search", handler.SearchJobs)
+	router.HandleFunc("/stream", handler.SearchJobsStream)
+	router.HandleFunc("/advanced", handler.AdvancedSearch)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	tests := []struct {
+		name, method, path, body string
+		want                     int
+	}{
+		{"search", "GET", "/search?salary_currency=XYZ", "", http.StatusBadRequest},
+		{"stream", "GET", "/stream?salary_currency=XYZ", "", http.StatusBadRequest},
+		{"advanced", "POST", "/advanced", `{"filters": {"salary_currency": "XYZ"}}`, http.StatusBadRequest},
+		{"known currency", "GET", "/search?salary_currency=eur", "", http.StatusOK},
+		{"advanced known currency", "POST", "/advanced", `{"filters": {"salary_currency": "eur"}}`, http.StatusOK},
+	}
+	for _, test := range tests {
+		req, err := http.NewRequest(test.method, server.URL+test.path, strings.NewReader(test.body))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != test.want {
+			t.Errorf("%s: status %d, want %d", test.name, resp.StatusCode, test.want)
+		}
+	}
+}
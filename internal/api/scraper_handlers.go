@@ -3,6 +3,8 @@ package api
 import (
 	"encoding/json"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/Illuminateee/web-scrapper.git/internal/scraper"
 	"github.com/gorilla/mux"
@@ -10,13 +12,16 @@ import (
 
 // ScraperHandler handles scraper configuration requests
 type ScraperHandler struct {
-	registry *scraper.ScraperRegistry
+	registry       *scraper.ScraperRegistry
+	scraperManager *scraper.ScraperManager
 }
 
-// NewScraperHandler creates a new scraper handler
-func NewScraperHandler(registry *scraper.ScraperRegistry) *ScraperHandler {
+// NewScraperHandler creates a new scraper handler. The manager's circuit breakers are
+// reported alongside each scraper's configuration.
+func NewScraperHandler(registry *scraper.ScraperRegistry, scraperManager *scraper.ScraperManager) *ScraperHandler {
 	return &ScraperHandler{
-		registry: registry,
+		registry:       registry,
+		scraperManager: scraperManager,
 	}
 }
 
@@ -28,14 +33,32 @@ func (h *ScraperHandler) ListScrapers(w http.ResponseWriter, r *http.Request) {
 	configs := h.registry.ListScrapers()
 
 	// Convert to status format for frontend
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
 	statuses := make([]scraper.ScraperStatus, 0, len(configs))
-	for _, config := range configs {
+	for _, name := range names {
+		config := configs[name]
+		stats, used := h.registry.GetScraperStats(name)
+		circuit := h.scraperManager.CircuitStatus(name)
+
 		status := scraper.ScraperStatus{
+			ID:           name,
 			Name:         config.Name,
 			Enabled:      config.Enabled,
 			Type:         config.Type,
 			RequiresAuth: config.RequiresAuth,
-			Status:       getScraperStatus(config),
+			LastError:    stats.LastError,
+			LastJobCount: stats.LastJobCount,
+			LastRetries:  stats.LastRetries,
+			Status:       getScraperStatus(config, stats, circuit),
+			Circuit:      circuit,
+		}
+		if used {
+			status.LastUsed = stats.LastUsed.Format(time.RFC3339)
 		}
 		statuses = append(statuses, status)
 	}
@@ -49,6 +72,66 @@ func (h *ScraperHandler) ListScrapers(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// AddScraperRequest represents a request to register a custom scraper
+type AddScraperRequest struct {
+	ID string `json:"id"`
+	scraper.ScraperConfig
+}
+
+// AddScraper registers a custom scraper, such as an RSS/Atom feed or a list of job pages
+func (h *ScraperHandler) AddScraper(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	var request AddScraperRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if request.ID == "" {
+		http.Error(w, "Scraper id is required", http.StatusBadRequest)
+		return
+	}
+	if _, err := h.registry.GetScraperConfig(request.ID); err == nil {
+		http.Error(w, "Scraper already exists: "+request.ID, http.StatusConflict)
+		return
+	}
+
+	config := request.ScraperConfig
+	if config.IgnoreRobots {
+		http.Error(w, "ignore_robots is only available for built-in API scrapers", http.StatusBadRequest)
+		return
+	}
+	if config.Name == "" {
+		config.Name = request.ID
+	}
+	switch config.Type {
+	case "rss":
+		if config.URL == "" {
+			http.Error(w, "Feed URL is required", http.StatusBadRequest)
+			return
+		}
+	case "jsonld":
+		if config.URL == "" && len(config.Pages) == 0 {
+			http.Error(w, "At least one page URL is required", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "Unsupported scraper type: "+config.Type, http.StatusBadRequest)
+		return
+	}
+
+	h.registry.AddCustomScraper(request.ID, config)
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Scraper added successfully",
+		"data":    config,
+	})
+}
+
 // EnableScraper enables a specific scraper
 func (h *ScraperHandler) EnableScraper(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -107,12 +190,59 @@ func (h *ScraperHandler) GetScraperConfig(w http.ResponseWriter, r *http.Request
 	})
 }
 
-// getScraperStatus determines the status of a scraper based on its configuration
-func getScraperStatus(config scraper.ScraperConfig) string {
+// RateLimits returns the request budget of every domain contacted so far and how long requests waited for it
+func (h *ScraperHandler) RateLimits(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    h.registry.RateLimitStats(),
+	})
+}
+
+// Robots returns the robots.txt state of every site checked so far and how many URLs it blocked
+func (h *ScraperHandler) Robots(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    h.registry.RobotsStats(),
+	})
+}
+
+// HTTPCache returns the hit and miss counters and size of the scraper response cache
+func (h *ScraperHandler) HTTPCache(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	stats, enabled := h.registry.HTTPCacheStats()
+	if !enabled {
+		http.Error(w, "HTTP cache is disabled", http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    stats,
+	})
+}
+
+// getScraperStatus determines the status of a scraper based on its configuration and last run
+func getScraperStatus(config scraper.ScraperConfig, stats scraper.ScraperStats, circuit scraper.CircuitStatus) string {
 	if !config.Enabled {
 		return "disabled"
 	}
 
+	if circuit.State == scraper.CircuitOpen {
+		return "circuit_open"
+	}
+
+	if stats.LastError != "" {
+		return "error"
+	}
+
 	if config.RequiresAuth {
 		return "requires_auth"
 	}
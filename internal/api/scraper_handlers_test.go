@@ -0,0 +1,134 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/scraper"
+	"github.com/Illuminateee/web-scrapper.git/internal/storage"
+	"github.com/gorilla/mux"
+)
+
+// newTestScraperServer serves the scraper and search routes from a registry with only
+// the mock scraper enabled, so nothing goes over the network
+func newTestScraperServer(t *testing.T) (*httptest.Server, *scraper.ScraperRegistry, *scraper.ScraperManager) {
+	t.Helper()
+
+	registry := scraper.NewScraperRegistry()
+	for name := range registry.ListScrapers() {
+		if name != "mock" {
+			registry.DisableScraper(name)
+		}
+	}
+	jobs := NewJobHandler(storage.NewInMemoryStorage(), registry)
+	scrapers := NewScraperHandler(registry, jobs.scraperManager)
+
+	router := mux.NewRouter()
+	api := router.PathPrefix("/api/v1").Subrouter()
+	api.HandleFunc("/jobs/search", jobs.SearchJobs).Methods("GET")
+	api.HandleFunc("/scrapers", scrapers.ListScrapers).Methods("GET")
+	api.HandleFunc("/scrapers/{name}/enable", scrapers.EnableScraper).Methods("POST")
+	api.HandleFunc("/scrapers/{name}/disable", scrapers.DisableScraper).Methods("POST")
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server, registry, jobs.scraperManager
+}
+
+// scraperStatus returns the listed status of one scraper
+func scraperStatus(t *testing.T, server *httptest.Server, id string) scraper.ScraperStatus {
+	t.Helper()
+
+	resp, err := http.Get(server.URL + "/api/v1/scrapers")
+	if err != nil {
+		t.Fatalf("GET /scrapers: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Data []scraper.ScraperStatus `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("GET /scrapers: failed to decode response: %v", err)
+	}
+	for _, status := range body.Data {
+		if status.ID == id {
+			return status
+		}
+	}
+	t.Fatalf("GET /scrapers: %s is not listed", id)
+	return scraper.ScraperStatus{}
+}
+
+// postStatus sends an empty POST and returns the response status
+func postStatus(t *testing.T, url string) int {
+	t.Helper()
+
+	resp, err := http.Post(url, "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestScraperStatusReportsLastRun(t *testing.T) {
+	server, registry, _ := newTestScraperServer(t)
+
+	if status := scraperStatus(t, server, "mock"); status.Status != "active" || status.LastUsed != "" {
+		t.Errorf("before a search: status %q, last used %q; want active and never used", status.Status, status.LastUsed)
+	}
+
+	resp, err := http.Get(server.URL + "/api/v1/jobs/search")
+	if err != nil {
+		t.Fatalf("GET /jobs/search: %v", err)
+	}
+	var response models.SearchResponse
+	json.NewDecoder(resp.Body).Decode(&response)
+	resp.Body.Close()
+
+	status := scraperStatus(t, server, "mock")
+	if status.LastUsed == "" || status.LastJobCount == 0 || status.LastJobCount != response.Analytics.TotalJobs {
+		t.Errorf("after a search: last used %q, %d jobs; want the %d jobs just found", status.LastUsed, status.LastJobCount, response.Analytics.TotalJobs)
+	}
+
+	registry.RecordResult("mock", models.ScrapingResult{Source: "MockJobSite", Error: errors.New("site unavailable")})
+	if status := scraperStatus(t, server, "mock"); status.Status != "error" || status.LastError != "site unavailable" {
+		t.Errorf("after a failed run: status %q, last error %q; want error", status.Status, status.LastError)
+	}
+}
+
+func TestDisabledScrapersLeaveTheNextSearch(t *testing.T) {
+	server, _, manager := newTestScraperServer(t)
+
+	if names := strings.Join(manager.Names(), ","); names != "MockJobSite" {
+		t.Fatalf("active scrapers %q, want MockJobSite", names)
+	}
+
+	if status := postStatus(t, server.URL+"/api/v1/scrapers/mock/disable"); status != http.StatusOK {
+		t.Fatalf("POST /scrapers/mock/disable: status %d", status)
+	}
+
+	if names := manager.Names(); len(names) != 0 {
+		t.Errorf("active scrapers %v after disabling mock, want none", names)
+	}
+	if status := scraperStatus(t, server, "mock"); status.Status != "disabled" || status.Enabled {
+		t.Errorf("disabled mock: status %q, enabled %v", status.Status, status.Enabled)
+	}
+
+	if status := postStatus(t, server.URL+"/api/v1/scrapers/mock/enable"); status != http.StatusOK {
+		t.Fatalf("POST /scrapers/mock/enable: status %d", status)
+	}
+	if names := strings.Join(manager.Names(), ","); names != "MockJobSite" {
+		t.Errorf("active scrapers %q after enabling mock again, want MockJobSite", names)
+	}
+
+	if status := postStatus(t, server.URL+"/api/v1/scrapers/unknown/enable"); status != http.StatusBadRequest {
+		t.Errorf("POST /scrapers/unknown/enable: status %d, want 400", status)
+	}
+}
@@ -0,0 +1,287 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/scraper"
+	"github.com/Illuminateee/web-scrapper.git/internal/storage"
+	"github.com/gorilla/mux"
+)
+
+// DefaultSearchTTL is how long finished searches are kept when no TTL is configured
+const DefaultSearchTTL = 30 * time.Minute
+
+// asyncSearch tracks a single search running in the background
+type asyncSearch struct {
+	id          string
+	filters     models.SearchFilters
+	status      models.SearchStatus
+	createdAt   time.Time
+	completedAt time.Time
+	sources     []models.SourceProgress
+	results     *storage.InMemoryStorage // jobs found by this search only
+	cancel      context.CancelFunc
+	mu          sync.Mutex
+}
+
+// SearchHandler handles asynchronous search requests
+type SearchHandler struct {
+	scraperManager *scraper.ScraperManager
+	storage        storage.JobStorage
+	ttl            time.Duration
+	searches       map[string]*asyncSearch
+	mu             sync.Mutex
+}
+
+// NewSearchHandler creates a new asynchronous search handler
+func NewSearchHandler(scraperManager *scraper.ScraperManager, jobStorage storage.JobStorage, ttl time.Duration) *SearchHandler {
+	if ttl <= 0 {
+		ttl = DefaultSearchTTL
+	}
+
+	return &SearchHandler{
+		scraperManager: scraperManager,
+		storage:        jobStorage,
+		ttl:            ttl,
+		searches:       make(map[string]*asyncSearch),
+	}
+}
+
+// SubmitSearch starts a search in the background and returns its ID immediately
+func (h *SearchHandler) SubmitSearch(w http.ResponseWriter, r *http.Request) {
+	filters := models.SearchFilters{
+		Limit: 50, // Default limit
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&filters); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+	code, err := salaryCurrency(filters.SalaryCurrency)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filters.SalaryCurrency = code
+
+	search, err := h.startSearch(filters)
+	if err != nil {
+		http.Error(w, "Error starting search", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/api/v1/searches/"+search.id)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(h.progress(search, filters))
+}
+
+// GetSearch reports per-source progress and the results collected so far
+func (h *SearchHandler) GetSearch(w http.ResponseWriter, r *http.Request) {
+	search := h.getSearch(mux.Vars(r)["id"])
+	if search == nil {
+		http.Error(w, "Search not found", http.StatusNotFound)
+		return
+	}
+
+	// Allow paging through the results without resubmitting the search
+	filters := search.filters
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		if val, err := strconv.Atoi(limit); err == nil && val > 0 {
+			filters.Limit = val
+		}
+	}
+	if offset := r.URL.Query().Get("offset"); offset != "" {
+		if val, err := strconv.Atoi(offset); err == nil && val >= 0 {
+			filters.Offset = val
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.progress(search, filters))
+}
+
+// CancelSearch cancels a running search, or forgets a finished one
+func (h *SearchHandler) CancelSearch(w http.ResponseWriter, r *http.Request) {
+	search := h.getSearch(mux.Vars(r)["id"])
+	if search == nil {
+		http.Error(w, "Search not found", http.StatusNotFound)
+		return
+	}
+
+	search.mu.Lock()
+	running := search.status == models.SearchRunning
+	if running {
+		search.status = models.SearchCancelled
+	}
+	search.mu.Unlock()
+
+	if running {
+		search.cancel()
+	} else {
+		h.mu.Lock()
+		delete(h.searches, search.id)
+		h.mu.Unlock()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.progress(search, search.filters))
+}
+
+// startSearch registers a new search and runs the scrapers in the background
+func (h *SearchHandler) startSearch(filters models.SearchFilters) (*asyncSearch, error) {
+	id, err := newSearchID()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+
+	search := &asyncSearch{
+		id:        id,
+		filters:   filters,
+		status:    models.SearchRunning,
+		createdAt: time.Now(),
+		results:   storage.NewInMemoryStorage(),
+		cancel:    cancel,
+	}
+	for _, name := range h.scraperManager.Names() {
+		search.sources = append(search.sources, models.SourceProgress{
+			Source: name,
+			Status: "running",
+		})
+	}
+
+	h.mu.Lock()
+	h.expireLocked()
+	h.searches[id] = search
+	h.mu.Unlock()
+
+	log.Printf("Starting search %s with filters: %+v", id, filters)
+
+	go h.runSearch(ctx, search)
+
+	return search, nil
+}
+
+// runSearch scrapes all sources, recording each result as soon as it arrives
+func (h *SearchHandler) runSearch(ctx context.Context, search *asyncSearch) {
+	defer search.cancel()
+
+	h.scraperManager.ScrapeAllWithProgress(ctx, search.filters, func(result models.ScrapingResult) {
+		if result.Error == nil {
+			if err := search.results.Store(result.Jobs); err != nil {
+				log.Printf("Error storing jobs for search %s: %v", search.id, err)
+			}
+		}
+		storeResult(h.storage, result, search.filters)
+
+		search.mu.Lock()
+		defer search.mu.Unlock()
+
+		progress := search.runningSourceLocked(result.Source)
+		progress.Retries = result.Retries
+		switch {
+		case result.Error == nil:
+			progress.Status = "completed"
+			progress.JobCount = len(result.Jobs)
+		case search.status == models.SearchCancelled:
+			progress.Status = "cancelled"
+			progress.Error = result.Error.Error()
+		default:
+			progress.Status = "failed"
+			progress.Error = result.Error.Error()
+		}
+	})
+
+	search.mu.Lock()
+	if search.status == models.SearchRunning {
+		search.status = models.SearchCompleted
+	}
+	search.completedAt = time.Now()
+	search.mu.Unlock()
+
+	log.Printf("Search %s finished", search.id)
+}
+
+// runningSourceLocked finds the progress entry for a source that is still running,
+// adding one if the source was enabled after the search started. search.mu must be held.
+func (search *asyncSearch) runningSourceLocked(source string) *models.SourceProgress {
+	for i := range search.sources {
+		if search.sources[i].Source == source && search.sources[i].Status == "running" {
+			return &search.sources[i]
+		}
+	}
+
+	search.sources = append(search.sources, models.SourceProgress{Source: source, Status: "running"})
+	return &search.sources[len(search.sources)-1]
+}
+
+// getSearch looks up a search by ID, dropping expired searches first
+func (h *SearchHandler) getSearch(id string) *asyncSearch {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.expireLocked()
+	return h.searches[id]
+}
+
+// expireLocked removes finished searches older than the TTL. h.mu must be held.
+func (h *SearchHandler) expireLocked() {
+	now := time.Now()
+	for id, search := range h.searches {
+		search.mu.Lock()
+		expired := !search.completedAt.IsZero() && now.Sub(search.completedAt) > h.ttl
+		search.mu.Unlock()
+
+		if expired {
+			delete(h.searches, id)
+		}
+	}
+}
+
+// progress builds the API representation of a search, paged according to filters
+func (h *SearchHandler) progress(search *asyncSearch, filters models.SearchFilters) models.SearchProgress {
+	results, err := search.results.Search(filters)
+	if err != nil {
+		log.Printf("Error reading results for search %s: %v", search.id, err)
+	}
+
+	search.mu.Lock()
+	defer search.mu.Unlock()
+
+	progress := models.SearchProgress{
+		ID:        search.id,
+		Status:    search.status,
+		CreatedAt: search.createdAt,
+		Sources:   append([]models.SourceProgress(nil), search.sources...),
+		Results:   results,
+	}
+	if !search.completedAt.IsZero() {
+		completedAt := search.completedAt
+		expiresAt := completedAt.Add(h.ttl)
+		progress.CompletedAt = &completedAt
+		progress.ExpiresAt = &expiresAt
+	}
+
+	return progress
+}
+
+// newSearchID generates a random search identifier
+func newSearchID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
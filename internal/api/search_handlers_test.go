@@ -0,0 +1,247 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/scraper"
+	"github.com/Illuminateee/web-scrapper.git/internal/storage"
+	"github.com/gorilla/mux"
+)
+
+// fakeScraper returns its jobs, after release is closed when it is set. It reports on
+// cancelled when its context ends while it waits.
+type fakeScraper struct {
+	name      string
+	jobs      []models.Job
+	release   chan struct{}
+	cancelled chan struct{}
+}
+
+func newFakeScraper(name string, jobCount int, blocking bool) *fakeScraper {
+	f := &fakeScraper{name: name, cancelled: make(chan struct{}, 1)}
+	if blocking {
+		f.release = make(chan struct{})
+	}
+	for i := 0; i < jobCount; i++ {
+		f.jobs = append(f.jobs, models.Job{
+			ID:         fmt.Sprintf("%s-%d", name, i),
+			Title:      fmt.Sprintf("%s job %d", name, i),
+			Company:    fmt.Sprintf("%s company %d", name, i),
+			URL:        fmt.Sprintf("https://%s.example.com/jobs/%d", name, i),
+			Source:     name,
+			PostedDate: time.Now().Add(-time.Duration(i) * time.Hour),
+		})
+	}
+	return f
+}
+
+func (f *fakeScraper) Name() string       { return f.name }
+func (f *fakeScraper) GetBaseURL() string { return "https://" + f.name + ".example.com" }
+
+func (f *fakeScraper) Scrape(ctx context.Context, filters models.SearchFilters) ([]models.Job, error) {
+	if f.release != nil {
+		select {
+		case <-f.release:
+		case <-ctx.Done():
+			select {
+			case f.cancelled <- struct{}{}:
+			default:
+			}
+			return nil, ctx.Err()
+		}
+	}
+	return f.jobs, nil
+}
+
+// newTestSearchServer serves the asynchronous search routes with the given scrapers
+func newTestSearchServer(t *testing.T, ttl time.Duration, scrapers ...scraper.JobScraper) *httptest.Server {
+	t.Helper()
+
+	manager := scraper.NewScraperManager(nil)
+	for _, s := range scrapers {
+		manager.AddScraper(s)
+	}
+	handler := NewSearchHandler(manager, storage.NewInMemoryStorage(), ttl)
+
+	router := mux.NewRouter()
+	api := router.PathPrefix("/api/v1").Subrouter()
+	api.HandleFunc("/searches", handler.SubmitSearch).Methods("POST")
+	api.HandleFunc("/searches/{id}", handler.GetSearch).Methods("GET")
+	api.HandleFunc("/searches/{id}", handler.CancelSearch).Methods("DELETE")
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// doSearchRequest sends a request and decodes the search progress it returns
+func doSearchRequest(t *testing.T, method, url string) (int, models.SearchProgress) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	var progress models.SearchProgress
+	if resp.StatusCode < 300 {
+		if err := json.NewDecoder(resp.Body).Decode(&progress); err != nil {
+			t.Fatalf("%s %s: failed to decode response: %v", method, url, err)
+		}
+	}
+	return resp.StatusCode, progress
+}
+
+// waitForSearch polls a search until it is no longer running
+func waitForSearch(t *testing.T, url string) models.SearchProgress {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		status, progress := doSearchRequest(t, "GET", url)
+		if status != http.StatusOK {
+			t.Fatalf("GET %s: status %d", url, status)
+		}
+		if progress.CompletedAt != nil {
+			return progress
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("search %s did not finish", url)
+	return models.SearchProgress{}
+}
+
+func TestSubmitSearchAndPageResults(t *testing.T) {
+	server := newTestSearchServer(t, time.Minute, newFakeScraper("alpha", 3, false), newFakeScraper("beta", 2, false))
+
+	resp, err := http.Post(server.URL+"/api/v1/searches", "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST /searches: %v", err)
+	}
+	var submitted models.SearchProgress
+	json.NewDecoder(resp.Body).Decode(&submitted)
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("POST /searches: status %d, want 202", resp.StatusCode)
+	}
+	if submitted.ID == "" || resp.Header.Get("Location") != "/api/v1/searches/"+submitted.ID {
+		t.Fatalf("POST /searches: id %q, location %q", submitted.ID, resp.Header.Get("Location"))
+	}
+
+	url := server.URL + "/api/v1/searches/" + submitted.ID
+	progress := waitForSearch(t, url)
+	if progress.Status != models.SearchCompleted || progress.ExpiresAt == nil {
+		t.Errorf("status %q, expires at %v; want completed with an expiry", progress.Status, progress.ExpiresAt)
+	}
+	for _, source := range progress.Sources {
+		if source.Status != "completed" {
+			t.Errorf("source %s: status %q, want completed", source.Source, source.Status)
+		}
+	}
+	if progress.Results == nil || progress.Results.Total != 5 {
+		t.Fatalf("results %+v, want 5 jobs", progress.Results)
+	}
+
+	// Results are paged without resubmitting the search
+	_, page := doSearchRequest(t, "GET", url+"?limit=2&offset=4")
+	if page.Results == nil || page.Results.Total != 5 || len(page.Results.Jobs) != 1 {
+		t.Errorf("limit 2 offset 4: got %+v, want 1 of 5 jobs", page.Results)
+	}
+}
+
+func TestSubmitSearchWithUnknownCurrency(t *testing.T) {
+	server := newTestSearchServer(t, time.Minute, newFakeScraper("alpha", 1, false))
+
+	resp, err := http.Post(server.URL+"/api/v1/searches", "application/json", strings.NewReader(`{"salary_currency": "XYZ"}`))
+	if err != nil {
+		t.Fatalf("POST /searches: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("POST /searches: status %d, want 400", resp.StatusCode)
+	}
+}
+
+func TestUnknownSearchIsNotFound(t *testing.T) {
+	server := newTestSearchServer(t, time.Minute)
+
+	for _, method := range []string{"GET", "DELETE"} {
+		if status, _ := doSearchRequest(t, method, server.URL+"/api/v1/searches/unknown"); status != http.StatusNotFound {
+			t.Errorf("%s unknown search: status %d, want 404", method, status)
+		}
+	}
+}
+
+func TestFinishedSearchesExpire(t *testing.T) {
+	ttl := 50 * time.Millisecond
+	server := newTestSearchServer(t, ttl, newFakeScraper("alpha", 1, false))
+
+	_, submitted := doSearchRequest(t, "POST", server.URL+"/api/v1/searches")
+	url := server.URL + "/api/v1/searches/" + submitted.ID
+	waitForSearch(t, url)
+
+	time.Sleep(2 * ttl)
+	if status, _ := doSearchRequest(t, "GET", url); status != http.StatusNotFound {
+		t.Errorf("expired search: status %d, want 404", status)
+	}
+}
+
+func TestDeleteForgetsFinishedSearch(t *testing.T) {
+	server := newTestSearchServer(t, time.Minute, newFakeScraper("alpha", 1, false))
+
+	_, submitted := doSearchRequest(t, "POST", server.URL+"/api/v1/searches")
+	url := server.URL + "/api/v1/searches/" + submitted.ID
+	waitForSearch(t, url)
+
+	if status, _ := doSearchRequest(t, "DELETE", url); status != http.StatusOK {
+		t.Fatalf("DELETE finished search: status %d, want 200", status)
+	}
+	if status, _ := doSearchRequest(t, "GET", url); status != http.StatusNotFound {
+		t.Errorf("deleted search: status %d, want 404", status)
+	}
+}
+
+func TestCancelRunningSearch(t *testing.T) {
+	blocked := newFakeScraper("slow", 1, true)
+	server := newTestSearchServer(t, time.Minute, blocked)
+
+	_, submitted := doSearchRequest(t, "POST", server.URL+"/api/v1/searches")
+	if submitted.Status != models.SearchRunning {
+		t.Fatalf("submitted search: status %q, want running", submitted.Status)
+	}
+	url := server.URL + "/api/v1/searches/" + submitted.ID
+
+	status, cancelled := doSearchRequest(t, "DELETE", url)
+	if status != http.StatusOK || cancelled.Status != models.SearchCancelled {
+		t.Fatalf("DELETE running search: status %d, search %q; want 200 and cancelled", status, cancelled.Status)
+	}
+
+	select {
+	case <-blocked.cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the scraper was not cancelled")
+	}
+
+	// A cancelled search is kept until it expires, with its sources marked cancelled
+	progress := waitForSearch(t, url)
+	if progress.Status != models.SearchCancelled {
+		t.Errorf("status %q after cancelling, want cancelled", progress.Status)
+	}
+	if len(progress.Sources) != 1 || progress.Sources[0].Status != "cancelled" {
+		t.Errorf("sources %+v, want slow cancelled", progress.Sources)
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/scraper"
+	"github.com/gorilla/mux"
+)
+
+// SiteHandler manages declarative site definitions, which become "css" scrapers
+type SiteHandler struct {
+	registry *scraper.ScraperRegistry
+	sites    *scraper.SiteStore
+}
+
+// NewSiteHandler creates a new site definition handler
+func NewSiteHandler(registry *scraper.ScraperRegistry, sites *scraper.SiteStore) *SiteHandler {
+	return &SiteHandler{
+		registry: registry,
+		sites:    sites,
+	}
+}
+
+// ListSites returns all stored site definitions
+func (h *SiteHandler) ListSites(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    h.sites.List(),
+	})
+}
+
+// GetSite returns a single site definition
+func (h *SiteHandler) GetSite(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	site, exists := h.sites.Get(mux.Vars(r)["id"])
+	if !exists {
+		http.Error(w, "Site not found", http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"data":    site,
+	})
+}
+
+// CreateSite validates and stores a new site definition and registers it as a scraper
+func (h *SiteHandler) CreateSite(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	var site scraper.SiteDefinition
+	if err := json.NewDecoder(r.Body).Decode(&site); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if _, err := h.registry.GetScraperConfig(site.ID); err == nil {
+		http.Error(w, "Scraper already exists: "+site.ID, http.StatusConflict)
+		return
+	}
+
+	if !h.save(w, site) {
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Site added successfully",
+		"data":    site,
+	})
+}
+
+// UpdateSite replaces an existing site definition
+func (h *SiteHandler) UpdateSite(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	id := mux.Vars(r)["id"]
+	if _, exists := h.sites.Get(id); !exists {
+		http.Error(w, "Site not found", http.StatusNotFound)
+		return
+	}
+
+	var site scraper.SiteDefinition
+	if err := json.NewDecoder(r.Body).Decode(&site); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	site.ID = id
+
+	if !h.save(w, site) {
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Site updated successfully",
+		"data":    site,
+	})
+}
+
+// DeleteSite removes a site definition and its scraper
+func (h *SiteHandler) DeleteSite(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+
+	id := mux.Vars(r)["id"]
+	if _, exists := h.sites.Get(id); !exists {
+		http.Error(w, "Site not found", http.StatusNotFound)
+		return
+	}
+
+	if err := h.sites.Delete(id); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	h.registry.RemoveScraper(id)
+
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"message": "Site deleted successfully",
+	})
+}
+
+// save persists a site definition and registers it, writing an error response on failure
+func (h *SiteHandler) save(w http.ResponseWriter, site scraper.SiteDefinition) bool {
+	if err := site.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	if err := h.sites.Save(site); err != nil {
+		http.Error(w, "Error saving site: "+err.Error(), http.StatusInternalServerError)
+		return false
+	}
+
+	h.registry.RegisterSite(site)
+	return true
+}
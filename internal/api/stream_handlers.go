@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/storage"
+)
+
+// streamResult is the payload of a "result" event, sent once per source
+type streamResult struct {
+	Source   string       `json:"source"`
+	Jobs     []models.Job `json:"jobs"`
+	JobCount int          `json:"job_count"`
+	Retries  int          `json:"retries"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// streamSummary is the payload of the final "analytics" event
+type streamSummary struct {
+	Total     int                 `json:"total"`
+	Analytics models.JobAnalytics `json:"analytics"`
+}
+
+// SearchJobsStream streams each source's results as Server-Sent Events as soon as it finishes,
+// followed by a final analytics event over everything stored
+func (h *JobHandler) SearchJobsStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	filters, err := h.parseSearchFilters(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Stop scraping if the client goes away
+	ctx, cancel := context.WithTimeout(r.Context(), 30*time.Second)
+	defer cancel()
+
+	log.Printf("Streaming job search with filters: %+v", filters)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	// Results arrive concurrently; funnel them through a channel so writes stay serialized
+	results := make(chan models.ScrapingResult)
+	go func() {
+		h.scraperManager.ScrapeAllWithProgress(ctx, filters, func(result models.ScrapingResult) {
+			results <- result
+		})
+		close(results)
+	}()
+
+	for result := range results {
+		event := streamResult{Source: result.Source, Retries: result.Retries}
+		if result.Error != nil {
+			event.Error = result.Error.Error()
+		} else {
+			storeResult(h.storage, result, filters)
+			event.Jobs = storage.FilterJobs(result.Jobs, filters)
+			event.JobCount = len(event.Jobs)
+		}
+
+		writeEvent(w, flusher, "result", event)
+	}
+
+	response, err := h.storage.Search(filters)
+	if err != nil {
+		writeEvent(w, flusher, "error", map[string]string{"error": "Error searching jobs"})
+		return
+	}
+
+	writeEvent(w, flusher, "analytics", streamSummary{
+		Total:     response.Total,
+		Analytics: response.Analytics,
+	})
+}
+
+// writeEvent writes a single Server-Sent Event with a JSON payload and flushes it to the client
+func writeEvent(w http.ResponseWriter, flusher http.Flusher, event string, data interface{}) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Error encoding %s event: %v", event, err)
+		return
+	}
+
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	flusher.Flush()
+}
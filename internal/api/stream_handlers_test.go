@@ -0,0 +1,120 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/scraper"
+	"github.com/Illuminateee/web-scrapper.git/internal/storage"
+)
+
+// sseEvent is one Server-Sent Event read from a stream
+type sseEvent struct {
+	name string
+	data string
+}
+
+// readEvent reads the next event of a stream
+func readEvent(t *testing.T, reader *bufio.Reader) sseEvent {
+	t.Helper()
+
+	var event sseEvent
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read event: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "" && event.name != "":
+			return event
+		case strings.HasPrefix(line, "event: "):
+			event.name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// newTestStreamServer serves the streaming search with the given scrapers
+func newTestStreamServer(t *testing.T, scrapers ...scraper.JobScraper) *httptest.Server {
+	t.Helper()
+
+	manager := scraper.NewScraperManager(nil)
+	for _, s := range scrapers {
+		manager.AddScraper(s)
+	}
+	handler := &JobHandler{scraperManager: manager, storage: storage.NewInMemoryStorage()}
+
+	server := httptest.NewServer(http.HandlerFunc(handler.SearchJobsStream))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStreamEventOrder(t *testing.T) {
+	fast := newFakeScraper("fast", 2, false)
+	slow := newFakeScraper("slow", 3, true)
+	server := newTestStreamServer(t, fast, slow)
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("GET stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("content type %q, want text/event-stream", contentType)
+	}
+	reader := bufio.NewReader(resp.Body)
+
+	// Each source is sent as soon as it finishes, before the slower ones
+	first := readEvent(t, reader)
+	var firstResult streamResult
+	json.Unmarshal([]byte(first.data), &firstResult)
+	if first.name != "result" || firstResult.Source != "fast" || firstResult.JobCount != 2 {
+		t.Fatalf("first event %s %s, want the fast source's result", first.name, first.data)
+	}
+
+	close(slow.release)
+	second := readEvent(t, reader)
+	var secondResult streamResult
+	json.Unmarshal([]byte(second.data), &secondResult)
+	if second.name != "result" || secondResult.Source != "slow" || secondResult.JobCount != 3 {
+		t.Fatalf("second event %s %s, want the slow source's result", second.name, second.data)
+	}
+
+	// Analytics come last, over every stored job
+	last := readEvent(t, reader)
+	var summary streamSummary
+	json.Unmarshal([]byte(last.data), &summary)
+	if last.name != "analytics" || summary.Total != 5 || summary.Analytics.TotalJobs != 5 {
+		t.Fatalf("last event %s %s, want analytics over 5 jobs", last.name, last.data)
+	}
+}
+
+func TestStreamCancelsScrapersOnDisconnect(t *testing.T) {
+	blocked := newFakeScraper("slow", 1, true)
+	server := newTestStreamServer(t, blocked)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// The client goes away while the scraper is still running
+	cancel()
+
+	select {
+	case <-blocked.cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the scraper was not cancelled after the client disconnected")
+	}
+}
@@ -0,0 +1,204 @@
+package currency
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// builtinRates is the exchange-rate table used when no table file is configured
+//
+//go:embed rates.json
+var builtinRates []byte
+
+// Table holds exchange rates as units of each currency per one unit of Base
+type Table struct {
+	Base   string             `json:"base"`
+	Date   string             `json:"date"` // day the rates were taken, YYYY-MM-DD
+	Source string             `json:"source,omitempty"`
+	Rates  map[string]float64 `json:"rates"`
+}
+
+// Validate checks the table and normalizes its codes to upper case
+func (t *Table) Validate() error {
+	t.Base = Normalize(t.Base)
+	if len(t.Base) != 3 {
+		return fmt.Errorf("base currency must be a three-letter ISO 4217 code, got %q", t.Base)
+	}
+
+	rates := make(map[string]float64, len(t.Rates)+1)
+	for code, rate := range t.Rates {
+		code = Normalize(code)
+		if len(code) != 3 {
+			return fmt.Errorf("invalid currency code %q", code)
+		}
+		if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+			return fmt.Errorf("rate for %s must be a positive number", code)
+		}
+		rates[code] = rate
+	}
+	if rate, exists := rates[t.Base]; exists && rate != 1 {
+		return fmt.Errorf("rate for the base currency %s must be 1", t.Base)
+	}
+	rates[t.Base] = 1
+	t.Rates = rates
+
+	return nil
+}
+
+// Has reports whether the table has a rate for a currency
+func (t *Table) Has(code string) bool {
+	_, exists := t.Rates[t.Resolve(code)]
+	return exists
+}
+
+// Resolve normalizes a currency code; an empty code is the base currency
+func (t *Table) Resolve(code string) string {
+	if code = Normalize(code); code == "" {
+		return t.Base
+	}
+	return code
+}
+
+// Currencies returns the codes the table has rates for, sorted
+func (t *Table) Currencies() []string {
+	codes := make([]string, 0, len(t.Rates))
+	for code := range t.Rates {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// Convert converts an amount between currencies. Empty codes are the base currency.
+// ok is false when either currency has no rate.
+func (t *Table) Convert(amount float64, from, to string) (float64, bool) {
+	from, to = t.Resolve(from), t.Resolve(to)
+	if from == to {
+		return amount, true
+	}
+
+	fromRate, fromExists := t.Rates[from]
+	toRate, toExists := t.Rates[to]
+	if !fromExists || !toExists {
+		return 0, false
+	}
+	return amount / fromRate * toRate, true
+}
+
+// ConvertRange converts a salary range, rounding to whole units. Zero bounds stay zero.
+func (t *Table) ConvertRange(min, max int, from, to string) (int, int, bool) {
+	convertedMin, ok := t.Convert(float64(min), from, to)
+	if !ok {
+		return 0, 0, false
+	}
+	convertedMax, _ := t.Convert(float64(max), from, to)
+	return int(math.Round(convertedMin)), int(math.Round(convertedMax)), true
+}
+
+// Normalize upper-cases and trims a currency code
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// ParseTable reads and validates a JSON exchange-rate table
+func ParseTable(data []byte) (*Table, error) {
+	var table Table
+	if err := json.Unmarshal(data, &table); err != nil {
+		return nil, fmt.Errorf("failed to parse exchange rates: %w", err)
+	}
+	if err := table.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid exchange rates: %w", err)
+	}
+	return &table, nil
+}
+
+// BuiltinTable returns the exchange-rate table shipped with the module
+func BuiltinTable() *Table {
+	table, err := ParseTable(builtinRates)
+	if err != nil {
+		panic(err)
+	}
+	return table
+}
+
+// LoadTable reads the exchange-rate table stored at path; a missing file is the built-in table
+func LoadTable(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return BuiltinTable(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read exchange rates: %w", err)
+	}
+
+	table, err := ParseTable(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return table, nil
+}
+
+// SaveTable validates a table and writes it to path atomically via a temporary file
+func SaveTable(path string, table *Table) error {
+	if err := table.Validate(); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(table, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode exchange rates: %w", err)
+	}
+
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create directory for exchange rates: %w", err)
+		}
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write exchange rates: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write exchange rates: %w", err)
+	}
+
+	return nil
+}
+
+var (
+	current   = BuiltinTable()
+	currentMu sync.RWMutex
+)
+
+// Rates returns the exchange-rate table used for salary filters and analytics
+func Rates() *Table {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+// SetRates replaces the exchange-rate table used for salary filters and analytics.
+// The table must not be modified afterwards.
+func SetRates(table *Table) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = table
+}
+
+// Convert converts an amount with the current table
+func Convert(amount float64, from, to string) (float64, bool) {
+	return Rates().Convert(amount, from, to)
+}
+
+// ConvertRange converts a salary range with the current table
+func ConvertRange(min, max int, from, to string) (int, int, bool) {
+	return Rates().ConvertRange(min, max, from, to)
+}
@@ -0,0 +1,399 @@
+package dedupe
+
+import (
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/fulltext"
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/skills"
+)
+
+// Thresholds decide when two jobs from different sources are the same posting
+type Thresholds struct {
+	// CompanySimilarity is the minimum word overlap (Jaccard) of the normalized company names
+	CompanySimilarity float64 `json:"company_similarity"`
+	// TitleSimilarity is the minimum word overlap (Jaccard) of the normalized titles
+	TitleSimilarity float64 `json:"title_similarity"`
+	// DescriptionSimilarity is the minimum estimated overlap of the descriptions' three-word shingles
+	DescriptionSimilarity float64 `json:"description_similarity"`
+	// MinDescriptionWords is how long both descriptions must be to be compared. Shorter
+	// ones, such as listing placeholders, are ignored and the titles must then match exactly.
+	MinDescriptionWords int `json:"min_description_words"`
+}
+
+// DefaultThresholds were chosen against testdata/pairs.json
+var DefaultThresholds = Thresholds{
+	CompanySimilarity:     0.6,
+	TitleSimilarity:       0.75,
+	DescriptionSimilarity: 0.5,
+	MinDescriptionWords:   20,
+}
+
+// signatureSize is the number of MinHash values kept per description
+const signatureSize = 128
+
+// Fingerprint holds the normalized fields of a job that duplicates are matched on
+type Fingerprint struct {
+	Block     string // first word of the normalized company; only jobs sharing it are compared
+	Company   []string
+	Title     []string
+	Location  []string
+	Remote    bool
+	Words     int      // description length in words
+	Signature []uint64 // MinHash of the description's shingles; nil without a description
+}
+
+// Match explains the comparison of two fingerprints
+type Match struct {
+	Duplicate           bool    `json:"duplicate"`
+	Company             float64 `json:"company"`
+	Title               float64 `json:"title"`
+	Description         float64 `json:"description"`
+	DescriptionCompared bool    `json:"description_compared"`
+	Location            bool    `json:"location"`
+}
+
+// Score orders matches; the best scoring duplicate of a job is merged into
+func (m Match) Score() float64 {
+	return m.Company + m.Title + m.Description
+}
+
+// Matcher finds duplicate jobs
+type Matcher struct {
+	thresholds Thresholds
+}
+
+// NewMatcher creates a matcher using thresholds
+func NewMatcher(thresholds Thresholds) *Matcher {
+	return &Matcher{thresholds: thresholds}
+}
+
+// Thresholds returns the matcher's thresholds
+func (m *Matcher) Thresholds() Thresholds {
+	return m.thresholds
+}
+
+// Compare compares two fingerprints
+func (m *Matcher) Compare(a, b Fingerprint) Match {
+	match := Match{
+		Company:  jaccard(a.Company, b.Company),
+		Title:    jaccard(a.Title, b.Title),
+		Location: locationsCompatible(a, b),
+	}
+
+	minWords := max(m.thresholds.MinDescriptionWords, 1)
+	if a.Words >= minWords && b.Words >= minWords {
+		match.DescriptionCompared = true
+		match.Description = similarity(a.Signature, b.Signature)
+	}
+
+	if len(a.Company) == 0 || match.Company < m.thresholds.CompanySimilarity || !match.Location {
+		return match
+	}
+	if match.DescriptionCompared {
+		match.Duplicate = match.Title >= m.thresholds.TitleSimilarity && match.Description >= m.thresholds.DescriptionSimilarity
+	} else {
+		match.Duplicate = match.Title == 1
+	}
+	return match
+}
+
+// NewFingerprint normalizes the fields of a job used for matching
+func NewFingerprint(job models.Job) Fingerprint {
+	company := NormalizeCompany(job.Company)
+	location := words(job.Location)
+
+	fingerprint := Fingerprint{
+		Company:  company,
+		Title:    NormalizeTitle(job.Title),
+		Location: location,
+		Remote:   isRemote(location),
+	}
+	if len(company) > 0 {
+		fingerprint.Block = company[0]
+	}
+
+	description := words(fulltext.StripTags(job.Description))
+	fingerprint.Words = len(description)
+	fingerprint.Signature = minHash(description)
+	return fingerprint
+}
+
+// Merge folds a duplicate listing into the canonical job: the duplicate is recorded in
+// AlsoOn and fills in whatever the canonical job is missing
+func Merge(canonical *models.Job, duplicate models.Job) {
+	if Listed(*canonical, duplicate) {
+		return
+	}
+	canonical.AlsoOn = append(canonical.AlsoOn, models.JobSource{
+		Source: duplicate.Source,
+		URL:    duplicate.URL,
+		ID:     duplicate.ID,
+	})
+	combine(canonical, duplicate)
+}
+
+// Refresh updates a stored job with a new scrape of one of its listings (see Listed). A new
+// scrape of the job itself replaces its fields with what the source lists now, so edits
+// such as a shorter description or a dropped skill are kept; only what storage tracks
+// about the job and its merged duplicates carry over. A new scrape of a merged duplicate
+// updates its AlsoOn entry and fills in what the job is missing, as Merge does.
+func Refresh(stored *models.Job, listing models.Job) {
+	for i, other := range stored.AlsoOn {
+		if sameListing(other, listing) {
+			stored.AlsoOn[i] = models.JobSource{Source: listing.Source, URL: listing.URL, ID: listing.ID}
+			combine(stored, listing)
+			return
+		}
+	}
+
+	previous := *stored
+	*stored = listing
+	stored.AlsoOn = previous.AlsoOn
+	stored.FirstSeen, stored.LastSeen = previous.FirstSeen, previous.LastSeen
+	stored.Status, stored.ClosedAt = previous.Status, previous.ClosedAt
+	stored.Reposts, stored.MissedScrapes = previous.Reposts, previous.MissedScrapes
+	stored.FieldsChanged = previous.FieldsChanged
+
+	// Sources without a date report the time of the scrape; the first date seen is the real one
+	if !previous.PostedDate.IsZero() && (stored.PostedDate.IsZero() || previous.PostedDate.Before(stored.PostedDate)) {
+		stored.PostedDate = previous.PostedDate
+	}
+}
+
+// combine fills in what the canonical job is missing from another listing of it
+func combine(canonical *models.Job, other models.Job) {
+	// Listing pages often carry a placeholder; keep the fullest description
+	if len(other.Description) > len(canonical.Description) {
+		canonical.Description = other.Description
+	}
+	if len(canonical.Requirements) == 0 {
+		canonical.Requirements = other.Requirements
+	}
+	if len(canonical.Benefits) == 0 {
+		canonical.Benefits = other.Benefits
+	}
+	canonical.Skills = skills.Canonicalize(append(append([]string{}, canonical.Skills...), other.Skills...))
+	if canonical.SalaryMin == 0 && canonical.SalaryMax == 0 {
+		canonical.SalaryMin, canonical.SalaryMax, canonical.SalaryCurrency = other.SalaryMin, other.SalaryMax, other.SalaryCurrency
+	}
+	if !other.PostedDate.IsZero() && (canonical.PostedDate.IsZero() || other.PostedDate.Before(canonical.PostedDate)) {
+		canonical.PostedDate = other.PostedDate
+	}
+	if canonical.ValidThrough == nil {
+		canonical.ValidThrough = other.ValidThrough
+	}
+	if canonical.EmploymentType == "" {
+		canonical.EmploymentType = other.EmploymentType
+	}
+	if canonical.CompanySize == "" {
+		canonical.CompanySize = other.CompanySize
+	}
+	if canonical.Industry == "" {
+		canonical.Industry = other.Industry
+	}
+}
+
+// Listed reports whether listing is a new scrape of the job or of one of its merged
+// duplicates: it has the same ID, or the same URL on the same source. Boards such as
+// Indeed link to pages of other sources, so a URL alone does not identify a listing.
+func Listed(job models.Job, listing models.Job) bool {
+	if sameListing(models.JobSource{Source: job.Source, URL: job.URL, ID: job.ID}, listing) {
+		return true
+	}
+	for _, other := range job.AlsoOn {
+		if sameListing(other, listing) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameListing reports whether listing is a new scrape of a recorded listing
+func sameListing(recorded models.JobSource, listing models.Job) bool {
+	if listing.ID != "" && recorded.ID == listing.ID {
+		return true
+	}
+	return recorded.URL == listing.URL && strings.EqualFold(recorded.Source, listing.Source)
+}
+
+// HasSource reports whether a job or one of its merged duplicates comes from source.
+// Listings from the same source are never merged; the source tells its own postings apart.
+func HasSource(job models.Job, source string) bool {
+	if strings.EqualFold(job.Source, source) {
+		return true
+	}
+	for _, other := range job.AlsoOn {
+		if strings.EqualFold(other.Source, source) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	// companySuffixes are legal forms dropped from company names
+	companySuffixes = map[string]bool{
+		"inc": true, "incorporated": true, "llc": true, "ltd": true, "limited": true, "corp": true,
+		"corporation": true, "co": true, "company": true, "gmbh": true, "ag": true, "sa": true,
+		"bv": true, "plc": true, "pty": true, "tbk": true, "sdn": true, "bhd": true, "oy": true,
+		"ab": true, "srl": true, "as": true, "lp": true, "llp": true,
+	}
+	// companyPrefixes are legal forms and articles dropped from the start of company names
+	companyPrefixes = map[string]bool{"the": true, "pt": true, "cv": true}
+
+	// titleAbbreviations expands the abbreviations job boards use in titles
+	titleAbbreviations = map[string]string{
+		"sr": "senior", "snr": "senior", "jr": "junior", "jnr": "junior",
+		"eng": "engineer", "engr": "engineer", "dev": "developer", "mgr": "manager",
+		"golang": "go", "fullstack": "full stack", "frontend": "front end", "backend": "back end",
+		"swe": "software engineer", "sre": "site reliability engineer",
+	}
+	// titleNoise are words about the work arrangement rather than the role
+	titleNoise = map[string]bool{
+		"remote": true, "hybrid": true, "worldwide": true, "anywhere": true, "fully": true,
+		"100": true, "wfh": true, "m": true, "f": true, "d": true, "w": true,
+	}
+	// titleBrackets matches parenthesized and bracketed parts such as "(Remote)"
+	titleBrackets = regexp.MustCompile(`\([^)]*\)|\[[^\]]*\]`)
+	// titleTail matches a trailing " - Location" or " | Company" part
+	titleTail = regexp.MustCompile(`\s+[-–—|]\s+.*$`)
+
+	// remoteWords mark a location as remote
+	remoteWords = map[string]bool{"remote": true, "worldwide": true, "anywhere": true, "global": true, "telecommute": true}
+)
+
+// NormalizeCompany returns the words of a company name without legal forms
+func NormalizeCompany(company string) []string {
+	tokens := words(strings.ReplaceAll(company, "&", " and "))
+	for len(tokens) > 1 && companyPrefixes[tokens[0]] {
+		tokens = tokens[1:]
+	}
+	for len(tokens) > 1 && companySuffixes[tokens[len(tokens)-1]] {
+		tokens = tokens[:len(tokens)-1]
+	}
+	return tokens
+}
+
+// NormalizeTitle returns the words of a job title with abbreviations expanded and
+// location or arrangement notes removed
+func NormalizeTitle(title string) []string {
+	title = titleBrackets.ReplaceAllString(title, " ")
+	title = titleTail.ReplaceAllString(title, "")
+
+	var tokens []string
+	for _, word := range words(title) {
+		if expanded, exists := titleAbbreviations[word]; exists {
+			tokens = append(tokens, strings.Fields(expanded)...)
+		} else if !titleNoise[word] {
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens
+}
+
+// words lower-cases text and splits it into letter and digit runs
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// isRemote reports whether location words describe remote work
+func isRemote(location []string) bool {
+	for _, word := range location {
+		if remoteWords[word] {
+			return true
+		}
+	}
+	return false
+}
+
+// locationsCompatible reports whether two locations can be the same posting: either is
+// unknown, both are remote, or they share a word ("Jakarta" and "Jakarta, Indonesia")
+func locationsCompatible(a, b Fingerprint) bool {
+	if len(a.Location) == 0 || len(b.Location) == 0 || (a.Remote && b.Remote) {
+		return true
+	}
+	return jaccard(a.Location, b.Location) > 0
+}
+
+// jaccard returns the overlap of two word sets, from 0 to 1
+func jaccard(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	set := make(map[string]bool, len(a))
+	for _, word := range a {
+		set[word] = true
+	}
+	union := len(set)
+	shared := 0
+	seen := make(map[string]bool, len(b))
+	for _, word := range b {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		if set[word] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	return float64(shared) / float64(union)
+}
+
+// minHash returns the MinHash signature of the three-word shingles of a text
+func minHash(tokens []string) []uint64 {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	signature := make([]uint64, signatureSize)
+	for i := range signature {
+		signature[i] = ^uint64(0)
+	}
+
+	size := min(3, len(tokens))
+	for i := 0; i+size <= len(tokens); i++ {
+		hash := fnv.New64a()
+		hash.Write([]byte(strings.Join(tokens[i:i+size], " ")))
+		shingle := hash.Sum64()
+
+		for j := range signature {
+			if value := mix(shingle + uint64(j)*0x9e3779b97f4a7c15); value < signature[j] {
+				signature[j] = value
+			}
+		}
+	}
+	return signature
+}
+
+// mix is the splitmix64 finalizer, giving one independent hash per signature slot
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// similarity estimates the shingle overlap of two descriptions from their signatures
+func similarity(a, b []uint64) float64 {
+	if len(a) == 0 || len(a) != len(b) {
+		return 0
+	}
+	equal := 0
+	for i := range a {
+		if a[i] == b[i] {
+			equal++
+		}
+	}
+	return float64(equal) / float64(len(a))
+}
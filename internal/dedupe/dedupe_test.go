@@ -0,0 +1,87 @@
+package dedupe
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+)
+
+// labeledPair is one entry of testdata/pairs.json
+type labeledPair struct {
+	Name      string     `json:"name"`
+	A         models.Job `json:"a"`
+	B         models.Job `json:"b"`
+	Duplicate bool       `json:"duplicate"`
+}
+
+func TestLabeledPairs(t *testing.T) {
+	data, err := os.ReadFile("testdata/pairs.json")
+	if err != nil {
+		t.Fatalf("failed to read labeled pairs: %v", err)
+	}
+	var pairs []labeledPair
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		t.Fatalf("failed to parse labeled pairs: %v", err)
+	}
+
+	matcher := NewMatcher(DefaultThresholds)
+	for _, pair := range pairs {
+		match := matcher.Compare(NewFingerprint(pair.A), NewFingerprint(pair.B))
+		if match.Duplicate != pair.Duplicate {
+			t.Errorf("%s: duplicate %v, want %v (company %.2f, title %.2f, description %.2f, location %v)",
+				pair.Name, match.Duplicate, pair.Duplicate, match.Company, match.Title, match.Description, match.Location)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	canonical := models.Job{ID: "rok-1", Source: "RemoteOK", URL: "https://remoteok.example.com/rok-1",
+		Description: "Go APIs.", Skills: []string{"Go"}}
+	duplicate := models.Job{ID: "wwr-1", Source: "WeWorkRemotely", URL: "https://weworkremotely.example.com/wwr-1",
+		Description: "Build payment APIs in Go.", Skills: []string{"Docker"}, SalaryMin: 100000, SalaryMax: 120000, SalaryCurrency: "USD"}
+
+	// Merging the same listing twice records it once
+	Merge(&canonical, duplicate)
+	Merge(&canonical, duplicate)
+
+	if len(canonical.AlsoOn) != 1 || canonical.AlsoOn[0].ID != "wwr-1" {
+		t.Errorf("also on %+v, want wwr-1 once", canonical.AlsoOn)
+	}
+	if canonical.Description != duplicate.Description {
+		t.Errorf("description %q, want the longer %q", canonical.Description, duplicate.Description)
+	}
+	if got := strings.Join(canonical.Skills, ","); got != "Go,Docker" {
+		t.Errorf("skills %q, want Go,Docker", got)
+	}
+	if canonical.SalaryMax != 120000 {
+		t.Errorf("salary max %d, want 120000 from the duplicate", canonical.SalaryMax)
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	stored := models.Job{ID: "rok-1", Source: "RemoteOK", URL: "https://remoteok.example.com/rok-1",
+		Description: "Build payment APIs in Go.", Skills: []string{"Go", "Docker"}, Reposts: 1,
+		AlsoOn: []models.JobSource{{Source: "WeWorkRemotely", URL: "https://weworkremotely.example.com/wwr-1", ID: "wwr-1"}}}
+
+	// A new scrape of the job itself replaces its fields
+	Refresh(&stored, models.Job{ID: "rok-1", Source: "RemoteOK", URL: stored.URL, Description: "Go APIs.", Skills: []string{"Go"}})
+	if stored.Description != "Go APIs." || strings.Join(stored.Skills, ",") != "Go" {
+		t.Errorf("got %q with skills %v, want the new listing", stored.Description, stored.Skills)
+	}
+	if stored.Reposts != 1 || len(stored.AlsoOn) != 1 {
+		t.Errorf("reposts %d, also on %+v; want them kept", stored.Reposts, stored.AlsoOn)
+	}
+
+	// A new scrape of a merged duplicate only fills in what the job lacks
+	Refresh(&stored, models.Job{ID: "wwr-1", Source: "WeWorkRemotely", URL: "https://weworkremotely.example.com/wwr-1/",
+		Description: "Go.", Skills: []string{"Kubernetes"}})
+	if stored.ID != "rok-1" || stored.Description != "Go APIs." || strings.Join(stored.Skills, ",") != "Go,Kubernetes" {
+		t.Errorf("got %s %q with skills %v, want rok-1 with Go,Kubernetes", stored.ID, stored.Description, stored.Skills)
+	}
+	if stored.AlsoOn[0].URL != "https://weworkremotely.example.com/wwr-1/" {
+		t.Errorf("also on %+v, want the new URL", stored.AlsoOn)
+	}
+}
@@ -0,0 +1,385 @@
+// Package fakeboard serves generated job postings in the formats of the sites the scrapers
+// read: a RemoteOK-style JSON API, WeWorkRemotely-style HTML listings, an Indeed-style RSS
+// feed and job pages with schema.org JobPosting JSON-LD. It lets the whole pipeline run
+// against a local server, with injected latency, errors and rate limiting.
+package fakeboard
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"log"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults used for zero Options fields
+const (
+	DefaultJobs       = 60
+	DefaultPageSize   = 10
+	DefaultRetryAfter = time.Second
+)
+
+// Options configures the generated data and how badly the board behaves
+type Options struct {
+	Jobs     int   // number of generated postings
+	Seed     int64 // postings and injected failures are reproducible for a seed
+	PageSize int   // listings per WeWorkRemotely page and RSS feed page
+
+	Latency       time.Duration // added before every response
+	ErrorRate     float64       // fraction of requests answered with 500
+	RateLimitRate float64       // fraction of requests answered with 429
+	RetryAfter    time.Duration // Retry-After sent with 429 responses
+}
+
+// Board is an http.Handler serving a fixed set of generated jobs
+type Board struct {
+	opts Options
+	jobs []Job
+	now  time.Time
+	mux  *http.ServeMux
+	rng  *rand.Rand // decides which requests fail
+	mu   sync.Mutex
+}
+
+// New generates the board's jobs and sets up its routes
+func New(opts Options) *Board {
+	if opts.Jobs <= 0 {
+		opts.Jobs = DefaultJobs
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultPageSize
+	}
+	if opts.RetryAfter <= 0 {
+		opts.RetryAfter = DefaultRetryAfter
+	}
+
+	now := time.Now().UTC().Truncate(time.Hour)
+	b := &Board{
+		opts: opts,
+		jobs: generateJobs(opts.Jobs, opts.Seed, now),
+		now:  now,
+		mux:  http.NewServeMux(),
+		rng:  rand.New(rand.NewSource(opts.Seed + 1)),
+	}
+
+	b.mux.HandleFunc("GET /{$}", b.index)
+	b.mux.HandleFunc("GET /robots.txt", b.robots)
+	b.mux.HandleFunc("GET /api", b.remoteOK)
+	b.mux.HandleFunc("GET /remote-jobs/{name}", b.remoteJobs)
+	b.mux.HandleFunc("GET /rss", b.rss)
+	return b
+}
+
+// Jobs returns the generated postings
+func (b *Board) Jobs() []Job {
+	return append([]Job(nil), b.jobs...)
+}
+
+// ServeHTTP adds the configured latency and failures in front of the routes. The index and
+// robots.txt always answer, so failures only hit job data.
+func (b *Board) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if b.opts.Latency > 0 {
+		select {
+		case <-time.After(b.opts.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if r.URL.Path != "/" && r.URL.Path != "/robots.txt" {
+		b.mu.Lock()
+		roll := b.rng.Float64()
+		b.mu.Unlock()
+
+		switch {
+		case roll < b.opts.RateLimitRate:
+			log.Printf("fakeboard: 429 for %s", r.URL)
+			w.Header().Set("Retry-After", strconv.Itoa(int(b.opts.RetryAfter.Seconds()+0.5)))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		case roll < b.opts.RateLimitRate+b.opts.ErrorRate:
+			log.Printf("fakeboard: 500 for %s", r.URL)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	b.mux.ServeHTTP(w, r)
+}
+
+// origin returns the scheme and host the request was made to, for absolute links
+func origin(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// index lists the endpoints
+func (b *Board) index(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "fakeboard: %d generated jobs\n\n", len(b.jobs))
+	fmt.Fprintln(w, "GET /api                          RemoteOK-style JSON")
+	fmt.Fprintln(w, "GET /remote-jobs/{category}?page=N WeWorkRemotely-style listings")
+	fmt.Fprintln(w, "GET /remote-jobs/{slug}           job page with JobPosting JSON-LD")
+	fmt.Fprintln(w, "GET /rss?q=...&start=N            Indeed-style RSS feed")
+	fmt.Fprintf(w, "\nCategories: %s\n", strings.Join(Categories, ", "))
+}
+
+// robots allows everything
+func (b *Board) robots(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "User-agent: *")
+	fmt.Fprintln(w, "Allow: /")
+}
+
+// remoteOKJob is a posting in the RemoteOK API format
+type remoteOKJob struct {
+	Slug        string   `json:"slug"`
+	ID          string   `json:"id"`
+	Epoch       int64    `json:"epoch"`
+	Date        string   `json:"date"`
+	Company     string   `json:"company"`
+	Position    string   `json:"position"`
+	Tags        []string `json:"tags"`
+	Description string   `json:"description"`
+	Location    string   `json:"location"`
+	SalaryMin   int      `json:"salary_min"`
+	SalaryMax   int      `json:"salary_max"`
+	Apply       string   `json:"apply_url"`
+	URL         string   `json:"url"`
+}
+
+// remoteOK serves every job as a RemoteOK API response, which starts with a legal notice
+func (b *Board) remoteOK(w http.ResponseWriter, r *http.Request) {
+	base := origin(r)
+
+	items := []interface{}{
+		map[string]string{"legal": "API terms of service: link back to the job and mention fakeboard as the source."},
+	}
+	for _, job := range b.jobs {
+		jobURL := base + "/remote-jobs/" + job.Slug
+		items = append(items, remoteOKJob{
+			Slug:        job.Slug,
+			ID:          strconv.Itoa(job.ID),
+			Epoch:       job.Posted.Unix(),
+			Date:        job.Posted.Format(time.RFC3339),
+			Company:     job.Company,
+			Position:    job.Title,
+			Tags:        job.Tags,
+			Description: job.Description,
+			Location:    job.Location,
+			SalaryMin:   job.SalaryMin,
+			SalaryMax:   job.SalaryMax,
+			Apply:       jobURL,
+			URL:         jobURL,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
+// remoteJobs serves a category listing or, for a job slug, the job's page
+func (b *Board) remoteJobs(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	for _, category := range Categories {
+		if name == category {
+			b.listing(w, r, category)
+			return
+		}
+	}
+	for _, job := range b.jobs {
+		if name == job.Slug {
+			b.detail(w, r, job)
+			return
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// page reads a 1-based ?page parameter
+func page(r *http.Request) int {
+	n, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// listing serves one page of a category in WeWorkRemotely's markup, with a rel="next"
+// link while more pages follow
+func (b *Board) listing(w http.ResponseWriter, r *http.Request, category string) {
+	var jobs []Job
+	for _, job := range b.jobs {
+		if job.Category == category {
+			jobs = append(jobs, job)
+		}
+	}
+
+	current := page(r)
+	start := (current - 1) * b.opts.PageSize
+	end := min(start+b.opts.PageSize, len(jobs))
+	if start >= len(jobs) {
+		start = end
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "<!DOCTYPE html>\n<html><head><title>%s | fakeboard</title></head><body>\n", html.EscapeString(category))
+	body.WriteString("<section class=\"jobs\"><ul>\n")
+	for _, job := range jobs[start:end] {
+		body.WriteString("<li><article class=\"job\">")
+		fmt.Fprintf(&body, "<h2><a href=\"/remote-jobs/%s\">%s</a></h2>", job.Slug, html.EscapeString(job.Title))
+		fmt.Fprintf(&body, "<span class=\"company\"><a href=\"/company/%s\">%s</a></span>", slugify(job.Company), html.EscapeString(job.Company))
+		fmt.Fprintf(&body, "<span class=\"region\">%s</span>", html.EscapeString(job.Location))
+		if salary := salaryText(job); salary != "" {
+			fmt.Fprintf(&body, "<span class=\"salary\">%s</span>", salary)
+		}
+		fmt.Fprintf(&body, "<time datetime=\"%s\">%s</time>", job.Posted.Format(time.RFC3339), ago(job.Posted, b.now))
+		body.WriteString("</article></li>\n")
+	}
+	body.WriteString("</ul></section>\n")
+	if end < len(jobs) {
+		fmt.Fprintf(&body, "<div class=\"pagination\"><a rel=\"next\" class=\"next_page\" href=\"/remote-jobs/%s?page=%d\">Next</a></div>\n", category, current+1)
+	}
+	body.WriteString("</body></html>\n")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(body.String()))
+}
+
+// detail serves a job page with the posting as schema.org JobPosting JSON-LD
+func (b *Board) detail(w http.ResponseWriter, r *http.Request, job Job) {
+	posting := map[string]interface{}{
+		"@context":           "https://schema.org",
+		"@type":              "JobPosting",
+		"title":              job.Title,
+		"description":        job.Description,
+		"datePosted":         job.Posted.Format(time.RFC3339),
+		"validThrough":       job.Posted.AddDate(0, 0, 60).Format(time.RFC3339),
+		"employmentType":     "FULL_TIME",
+		"jobLocationType":    "TELECOMMUTE",
+		"hiringOrganization": map[string]string{"@type": "Organization", "name": job.Company},
+		"applicantLocationRequirements": map[string]string{
+			"@type": "Country",
+			"name":  job.Location,
+		},
+		"skills": strings.Join(job.Tags, ", "),
+		"url":    origin(r) + "/remote-jobs/" + job.Slug,
+	}
+	if job.SalaryMin > 0 {
+		posting["baseSalary"] = map[string]interface{}{
+			"@type":    "MonetaryAmount",
+			"currency": "USD",
+			"value": map[string]interface{}{
+				"@type":    "QuantitativeValue",
+				"minValue": job.SalaryMin,
+				"maxValue": job.SalaryMax,
+				"unitText": "YEAR",
+			},
+		}
+	}
+
+	// json.Marshal escapes <, > and &, so the description cannot close the script tag
+	data, err := json.Marshal(posting)
+	if err != nil {
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "<!DOCTYPE html>\n<html><head><title>%s at %s | fakeboard</title>\n", html.EscapeString(job.Title), html.EscapeString(job.Company))
+	fmt.Fprintf(&body, "<script type=\"application/ld+json\">%s</script>\n</head><body>\n", data)
+	fmt.Fprintf(&body, "<h1>%s</h1>\n<div id=\"job-listing-show-container\">%s</div>\n", html.EscapeString(job.Title), job.Description)
+	body.WriteString("</body></html>\n")
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write([]byte(body.String()))
+}
+
+// rssItem is a posting in Indeed's RSS format
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Source      string `xml:"source"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+	Description string `xml:"description"`
+}
+
+// rssFeed is an RSS 2.0 document
+type rssFeed struct {
+	XMLName xml.Name `xml:"rss"`
+	Version string   `xml:"version,attr"`
+	Channel struct {
+		Title string    `xml:"title"`
+		Link  string    `xml:"link"`
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+// rss serves the jobs matching any word of ?q as an Indeed-style feed, newest first,
+// PageSize items from the ?start offset
+func (b *Board) rss(w http.ResponseWriter, r *http.Request) {
+	words := strings.Fields(strings.ToLower(r.URL.Query().Get("q")))
+
+	var jobs []Job
+	for _, job := range b.jobs {
+		if matchesAny(job, words) {
+			jobs = append(jobs, job)
+		}
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].Posted.After(jobs[j].Posted) })
+
+	start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+	start = min(max(start, 0), len(jobs))
+	end := min(start+b.opts.PageSize, len(jobs))
+
+	base := origin(r)
+	feed := rssFeed{Version: "2.0"}
+	feed.Channel.Title = "fakeboard jobs"
+	feed.Channel.Link = base
+	for _, job := range jobs[start:end] {
+		description := "Company: " + html.EscapeString(job.Company) + "<br>"
+		if salary := salaryText(job); salary != "" {
+			description += "Salary: " + salary + " a year<br>"
+		}
+		description += job.Description
+
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       fmt.Sprintf("%s - %s - %s", job.Title, job.Company, job.Location),
+			Link:        base + "/remote-jobs/" + job.Slug,
+			Source:      job.Company,
+			GUID:        fmt.Sprintf("fakeboard-%d", job.ID),
+			PubDate:     job.Posted.Format(time.RFC1123Z),
+			Description: description,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+	xml.NewEncoder(w).Encode(feed)
+}
+
+// matchesAny reports whether a job's title, tags or description contains any of words;
+// no words matches every job
+func matchesAny(job Job, words []string) bool {
+	if len(words) == 0 {
+		return true
+	}
+	text := strings.ToLower(job.Title + " " + strings.Join(job.Tags, " ") + " " + job.Description)
+	for _, word := range words {
+		if strings.Contains(text, word) {
+			return true
+		}
+	}
+	return false
+}
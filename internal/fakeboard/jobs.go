@@ -0,0 +1,156 @@
+package fakeboard
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Job is a generated posting served by every endpoint of the board
+type Job struct {
+	ID          int
+	Slug        string
+	Title       string
+	Company     string
+	Category    string // WeWorkRemotely category slug
+	Location    string
+	Tags        []string
+	SalaryMin   int // yearly USD; 0 when the posting has no salary
+	SalaryMax   int
+	Posted      time.Time
+	Description string // HTML
+}
+
+// Categories are the WeWorkRemotely categories jobs are spread over
+var Categories = []string{
+	"remote-programming-jobs",
+	"remote-devops-sysadmin-jobs",
+	"remote-customer-support-jobs",
+	"remote-design-jobs",
+	"remote-sales-marketing-jobs",
+}
+
+// categoryTitles are the job titles generated for each category
+var categoryTitles = map[string][]string{
+	"remote-programming-jobs":      {"Backend Engineer", "Senior Go Developer", "Full Stack Developer", "Junior Python Developer", "Lead Frontend Engineer", "Software Engineer"},
+	"remote-devops-sysadmin-jobs":  {"DevOps Engineer", "Site Reliability Engineer", "Senior Platform Engineer", "Cloud Infrastructure Engineer"},
+	"remote-customer-support-jobs": {"Customer Support Specialist", "Technical Support Engineer", "Support Team Lead"},
+	"remote-design-jobs":           {"Product Designer", "Senior UX Designer", "UI Designer"},
+	"remote-sales-marketing-jobs":  {"Growth Marketing Manager", "Account Executive", "Content Marketing Lead"},
+}
+
+// categoryTags are the skills a category's postings draw their tags from
+var categoryTags = map[string][]string{
+	"remote-programming-jobs":      {"go", "python", "javascript", "typescript", "react", "postgresql", "docker", "aws", "graphql", "redis"},
+	"remote-devops-sysadmin-jobs":  {"kubernetes", "docker", "aws", "gcp", "terraform", "linux", "jenkins", "go"},
+	"remote-customer-support-jobs": {"zendesk", "sql", "api", "communication"},
+	"remote-design-jobs":           {"figma", "design systems", "css", "html"},
+	"remote-sales-marketing-jobs":  {"seo", "salesforce", "analytics", "copywriting"},
+}
+
+var (
+	companies = []string{
+		"Acme Remote", "Brightwave", "Cloudburst Labs", "Driftwood Software", "Evergreen Health",
+		"Fieldnote", "Gridline", "Harbor Analytics", "Inkwell", "Juniper Systems",
+	}
+	locations = []string{
+		"Remote", "Remote (US)", "Remote (Europe)", "Worldwide", "Remote (Americas)",
+	}
+	slugUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// generateJobs creates count postings from seed. The same seed always gives the same jobs,
+// dated relative to now.
+func generateJobs(count int, seed int64, now time.Time) []Job {
+	rng := rand.New(rand.NewSource(seed))
+
+	jobs := make([]Job, 0, count)
+	for i := 0; i < count; i++ {
+		id := 100000 + i
+		category := Categories[i%len(Categories)]
+		titles := categoryTitles[category]
+		title := titles[rng.Intn(len(titles))]
+		company := companies[rng.Intn(len(companies))]
+
+		// Three to five distinct tags from the category
+		pool := categoryTags[category]
+		var tags []string
+		for _, index := range rng.Perm(len(pool))[:min(len(pool), 3+rng.Intn(3))] {
+			tags = append(tags, pool[index])
+		}
+
+		// Most postings advertise a yearly salary
+		var salaryMin, salaryMax int
+		if rng.Intn(4) > 0 {
+			salaryMin = (60 + rng.Intn(90)) * 1000
+			salaryMax = salaryMin + (10+rng.Intn(40))*1000
+		}
+
+		job := Job{
+			ID:        id,
+			Slug:      fmt.Sprintf("%s-%s-%d", slugify(company), slugify(title), id),
+			Title:     title,
+			Company:   company,
+			Category:  category,
+			Location:  locations[rng.Intn(len(locations))],
+			Tags:      tags,
+			SalaryMin: salaryMin,
+			SalaryMax: salaryMax,
+			Posted:    now.Add(-time.Duration(rng.Intn(30*24)) * time.Hour),
+		}
+		job.Description = describe(job, rng)
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+// describe writes an HTML job description mentioning the posting's tags
+func describe(job Job, rng *rand.Rand) string {
+	years := 1 + rng.Intn(7)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<p>%s is hiring a %s to join our fully remote team.</p>", job.Company, job.Title)
+	fmt.Fprintf(&b, "<p>You will work with %s every day.</p>", strings.Join(job.Tags, ", "))
+	b.WriteString("<h3>Requirements</h3><ul>")
+	fmt.Fprintf(&b, "<li>%d+ years of professional experience</li>", years)
+	if rng.Intn(3) == 0 {
+		b.WriteString("<li>Bachelor's degree in Computer Science or a related field required</li>")
+	}
+	b.WriteString("<li>Excellent written communication</li></ul>")
+	return b.String()
+}
+
+// slugify lowercases text and joins its words with dashes
+func slugify(text string) string {
+	return strings.Trim(slugUnsafe.ReplaceAllString(strings.ToLower(text), "-"), "-")
+}
+
+// salaryText formats a salary range like "$80,000 - $100,000"
+func salaryText(job Job) string {
+	if job.SalaryMin == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s - %s", dollars(job.SalaryMin), dollars(job.SalaryMax))
+}
+
+// dollars formats an amount with thousands separators
+func dollars(amount int) string {
+	return fmt.Sprintf("$%d,%03d", amount/1000, amount%1000)
+}
+
+// ago describes how long ago t was the way WeWorkRemotely lists do
+func ago(t, now time.Time) string {
+	days := int(now.Sub(t).Hours() / 24)
+	switch {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "yesterday"
+	case days < 14:
+		return fmt.Sprintf("%d days ago", days)
+	default:
+		return fmt.Sprintf("%d weeks ago", days/7)
+	}
+}
@@ -0,0 +1,427 @@
+package fulltext
+
+import (
+	"html"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/skills"
+)
+
+// BM25 parameters: k1 limits how much repeating a term raises the score, and b how much
+// long documents are penalized
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Field weights: a term in the title counts three times as much as one in the description
+const (
+	titleWeight       = 3.0
+	skillsWeight      = 2.0
+	companyWeight     = 1.5
+	descriptionWeight = 1.0
+)
+
+// stopWords are too common to tell jobs apart. "it" is kept, as in "IT support".
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true,
+	"by": true, "for": true, "from": true, "in": true, "is": true, "of": true, "on": true,
+	"or": true, "our": true, "the": true, "this": true, "that": true, "to": true,
+	"we": true, "will": true, "with": true, "you": true, "your": true,
+}
+
+// Terms returns the stemmed words of text that are indexed, in order, without stop words
+func Terms(text string) []string {
+	var terms []string
+	for _, token := range tokenize(text) {
+		if term := termOf(token.word); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// markup matches HTML tags and comments, capturing the tag name. A "<" not followed by
+// a tag name, as in "salary < 100k", is text.
+var markup = regexp.MustCompile(`<!--[\s\S]*?-->|</?([a-zA-Z][a-zA-Z0-9]*)[^>]*>`)
+
+// inlineTags format words inside a line, so they are dropped without separating words
+var inlineTags = map[string]bool{
+	"a": true, "abbr": true, "b": true, "code": true, "em": true, "i": true, "small": true,
+	"span": true, "strong": true, "sub": true, "sup": true, "u": true,
+}
+
+// StripTags returns the text of an HTML fragment: tags are dropped, those breaking lines
+// for a space, and entities are decoded. Descriptions of some sources are HTML, and their
+// markup is not searchable text.
+func StripTags(text string) string {
+	if !strings.ContainsAny(text, "<&") {
+		return text
+	}
+	text = markup.ReplaceAllStringFunc(text, func(tag string) string {
+		if name := markup.FindStringSubmatch(tag)[1]; inlineTags[strings.ToLower(name)] {
+			return ""
+		}
+		return " "
+	})
+	return html.UnescapeString(text)
+}
+
+// termOf is the indexed form of a lower-case word, or "" for a stop word
+func termOf(word string) string {
+	if stopWords[word] {
+		return ""
+	}
+	return Stem(word)
+}
+
+// token is a word of a text and where it is, in bytes
+type token struct {
+	word       string // lower case
+	start, end int
+}
+
+// tokenize splits text into lower-case words of letters and digits. A word keeps inner
+// dots ("node.js") and trailing "+" and "#" ("c++", "c#").
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, token{word: strings.ToLower(text[start:end]), start: start, end: end})
+			start = -1
+		}
+	}
+
+	type position struct {
+		offset int
+		r      rune
+	}
+	var runes []position
+	for offset, r := range text {
+		runes = append(runes, position{offset, r})
+	}
+	isWord := func(i int) bool {
+		return i < len(runes) && (unicode.IsLetter(runes[i].r) || unicode.IsDigit(runes[i].r))
+	}
+
+	for i, p := range runes {
+		switch {
+		case isWord(i):
+			if start < 0 {
+				start = p.offset
+			}
+		case start >= 0 && p.r == '.' && isWord(i+1):
+			// inner dot
+		case start >= 0 && (p.r == '+' || p.r == '#') && !isWord(i+1):
+			// trailing mark
+		default:
+			flush(p.offset)
+		}
+	}
+	flush(len(text))
+
+	return tokens
+}
+
+// Query is a parsed search query: the distinct terms of its words
+type Query struct {
+	terms []string
+	set   map[string]bool
+}
+
+// ParseQuery parses the words of a search query
+func ParseQuery(text string) Query {
+	query := Query{set: make(map[string]bool)}
+	for _, term := range Terms(text) {
+		if !query.set[term] {
+			query.set[term] = true
+			query.terms = append(query.terms, term)
+		}
+	}
+	return query
+}
+
+// Empty reports whether the query has no terms to search for
+func (q Query) Empty() bool {
+	return len(q.terms) == 0
+}
+
+// jobFields returns the searchable text of a job with the weight of each field. Skills
+// are indexed with their aliases, so "golang" finds a job listing "Go".
+func jobFields(job models.Job) []struct {
+	text   string
+	weight float64
+} {
+	var skillText []string
+	for _, name := range job.Skills {
+		if skill, exists := skills.Lookup(name); exists {
+			skillText = append(skillText, skill.Name)
+			skillText = append(skillText, skill.Aliases...)
+		} else {
+			skillText = append(skillText, name)
+		}
+	}
+
+	return []struct {
+		text   string
+		weight float64
+	}{
+		{job.Title, titleWeight},
+		{strings.Join(skillText, ", "), skillsWeight},
+		{job.Company, companyWeight},
+		{StripTags(job.Description), descriptionWeight},
+	}
+}
+
+// jobTerms returns the weighted frequency of each term of a job and its weighted length
+func jobTerms(job models.Job) (map[string]float64, float64) {
+	frequencies := make(map[string]float64)
+	length := 0.0
+	for _, field := range jobFields(job) {
+		for _, term := range Terms(field.text) {
+			frequencies[term] += field.weight
+			length += field.weight
+		}
+	}
+	return frequencies, length
+}
+
+// MatchesKeywords reports whether a job contains any of the keywords, in any form:
+// "developers" finds "developer". A keyword of several words needs all of them.
+// Keywords made only of stop words are ignored.
+func MatchesKeywords(job models.Job, keywords []string) bool {
+	frequencies, _ := jobTerms(job)
+
+	searched := false
+	for _, keyword := range keywords {
+		terms := Terms(keyword)
+		if len(terms) == 0 {
+			continue
+		}
+		searched = true
+		if containsAll(terms, func(term string) bool { return frequencies[term] > 0 }) {
+			return true
+		}
+	}
+	return !searched
+}
+
+// containsAll reports whether every term is contained
+func containsAll(terms []string, contains func(string) bool) bool {
+	for _, term := range terms {
+		if !contains(term) {
+			return false
+		}
+	}
+	return true
+}
+
+// Index is an inverted index of jobs for ranked full-text search over their title,
+// skills, company and description. Jobs are identified by an integer chosen by the caller.
+// It is safe for concurrent use.
+type Index struct {
+	postings map[string]map[int]float64 // weighted term frequency by term and job
+	terms    map[int][]string           // distinct terms of each job, to remove it
+	lengths  map[int]float64            // weighted length of each job
+	total    float64                    // sum of lengths
+	mu       sync.RWMutex
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string]map[int]float64),
+		terms:    make(map[int][]string),
+		lengths:  make(map[int]float64),
+	}
+}
+
+// Add indexes a job, replacing what was indexed under its id before
+func (ix *Index) Add(id int, job models.Job) {
+	frequencies, length := jobTerms(job)
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.removeLocked(id)
+	terms := make([]string, 0, len(frequencies))
+	for term, frequency := range frequencies {
+		if ix.postings[term] == nil {
+			ix.postings[term] = make(map[int]float64)
+		}
+		ix.postings[term][id] = frequency
+		terms = append(terms, term)
+	}
+	ix.terms[id] = terms
+	ix.lengths[id] = length
+	ix.total += length
+}
+
+// removeLocked drops a job from the index while holding the lock
+func (ix *Index) removeLocked(id int) {
+	terms, exists := ix.terms[id]
+	if !exists {
+		return
+	}
+	for _, term := range terms {
+		delete(ix.postings[term], id)
+		if len(ix.postings[term]) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	ix.total -= ix.lengths[id]
+	delete(ix.terms, id)
+	delete(ix.lengths, id)
+}
+
+// Clear drops every job from the index
+func (ix *Index) Clear() {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.postings = make(map[string]map[int]float64)
+	ix.terms = make(map[int][]string)
+	ix.lengths = make(map[int]float64)
+	ix.total = 0
+}
+
+// MatchingKeywords returns the jobs that contain any of the keywords, as MatchesKeywords
+// does. It reports false when no keyword has a term to search for, meaning every job matches.
+func (ix *Index) MatchingKeywords(keywords []string) (map[int]bool, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	matching := make(map[int]bool)
+	searched := false
+	for _, keyword := range keywords {
+		terms := Terms(keyword)
+		if len(terms) == 0 {
+			continue
+		}
+		searched = true
+
+		// Check the jobs of the rarest term for the others
+		rarest := terms[0]
+		for _, term := range terms[1:] {
+			if len(ix.postings[term]) < len(ix.postings[rarest]) {
+				rarest = term
+			}
+		}
+		for id := range ix.postings[rarest] {
+			if containsAll(terms, func(term string) bool { return ix.postings[term][id] > 0 }) {
+				matching[id] = true
+			}
+		}
+	}
+	return matching, searched
+}
+
+// Scores ranks the jobs containing any query term with BM25
+func (ix *Index) Scores(query Query) map[int]float64 {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	scores := make(map[int]float64)
+	count := float64(len(ix.terms))
+	if count == 0 {
+		return scores
+	}
+	averageLength := ix.total / count
+
+	for _, term := range query.terms {
+		postings := ix.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		frequency := float64(len(postings))
+		idf := math.Log(1 + (count-frequency+0.5)/(frequency+0.5))
+		for id, tf := range postings {
+			norm := 1 - bm25B + bm25B*ix.lengths[id]/averageLength
+			scores[id] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+	}
+	return scores
+}
+
+// Highlight returns text escaped for HTML with the words matching the query wrapped in
+// <mark>, or "" when no word matches
+func Highlight(text string, query Query) string {
+	return highlight(text, query, 0, len(text))
+}
+
+// Snippet returns an excerpt of about length bytes of text around its first word matching
+// the query, escaped for HTML with matching words wrapped in <mark>. Markup is dropped
+// (see StripTags), whitespace is collapsed, and "…" marks where the excerpt cuts the
+// text. It returns "" when no word matches.
+func Snippet(text string, query Query, length int) string {
+	text = strings.Join(strings.Fields(StripTags(text)), " ")
+	tokens := tokenize(text)
+
+	first := -1
+	for i, token := range tokens {
+		if query.set[termOf(token.word)] {
+			first = i
+			break
+		}
+	}
+	if first < 0 {
+		return ""
+	}
+
+	// Start a few words before the match, and end on a word boundary
+	start := tokens[first].start
+	for i := first; i >= 0 && tokens[first].end-tokens[i].start <= length/3; i-- {
+		start = tokens[i].start
+	}
+	end := start
+	for i := first; i < len(tokens) && tokens[i].end-start <= length; i++ {
+		end = tokens[i].end
+	}
+	end = max(end, tokens[first].end)
+
+	// Nothing is cut before the first word or after the last one
+	if start == tokens[0].start {
+		start = 0
+	}
+	if end == tokens[len(tokens)-1].end {
+		end = len(text)
+	}
+
+	snippet := highlight(text, query, start, end)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// highlight escapes text[start:end] and marks the words matching the query, or returns
+// "" when none match
+func highlight(text string, query Query, start, end int) string {
+	var out strings.Builder
+	matched := false
+	last := start
+	for _, token := range tokenize(text) {
+		if token.start < start || token.end > end || !query.set[termOf(token.word)] {
+			continue
+		}
+		matched = true
+		out.WriteString(html.EscapeString(text[last:token.start]))
+		out.WriteString("<mark>")
+		out.WriteString(html.EscapeString(text[token.start:token.end]))
+		out.WriteString("</mark>")
+		last = token.end
+	}
+	if !matched {
+		return ""
+	}
+	out.WriteString(html.EscapeString(text[last:end]))
+	return out.String()
+}
@@ -0,0 +1,182 @@
+package fulltext
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+)
+
+// stemCase is one entry of testdata/stems.json
+type stemCase struct {
+	Word string `json:"word"`
+	Stem string `json:"stem"`
+}
+
+func TestStemCorpus(t *testing.T) {
+	data, err := os.ReadFile("testdata/stems.json")
+	if err != nil {
+		t.Fatalf("failed to read stem corpus: %v", err)
+	}
+	var cases []stemCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		t.Fatalf("failed to parse stem corpus: %v", err)
+	}
+
+	for _, c := range cases {
+		if stem := Stem(c.Word); stem != c.Stem {
+			t.Errorf("%q: got %q, want %q", c.Word, stem, c.Stem)
+		}
+	}
+}
+
+// sampleJobs are indexed under their position
+var sampleJobs = []models.Job{
+	{Title: "Golang Backend Engineer", Company: "Acme", Skills: []string{"Go", "PostgreSQL"},
+		Description: "Acme builds payment APIs. You will design backend services in Go & run them on PostgreSQL."},
+	{Title: "Backend Developer", Company: "Globex", Skills: []string{"Python"},
+		Description: "Globex maintains backend APIs in Python. Experience with golang is a plus."},
+	{Title: "Frontend Developer", Company: "Initech", Skills: []string{"React"},
+		Description: "Initech is looking for developers who love React and design systems."},
+	{Title: "Data Analyst", Company: "Umbrella", Skills: []string{"SQL"},
+		Description: "Umbrella needs an analyst for reporting."},
+}
+
+// newSampleIndex indexes sampleJobs
+func newSampleIndex() *Index {
+	index := NewIndex()
+	for i, job := range sampleJobs {
+		index.Add(i, job)
+	}
+	return index
+}
+
+func TestScores(t *testing.T) {
+	scores := newSampleIndex().Scores(ParseQuery("golang backend"))
+
+	// Both terms in the title outrank both in the description; jobs with neither are not scored
+	if len(scores) != 2 || scores[0] <= scores[1] {
+		t.Errorf("scores %v, want job 0 above job 1 and no others", scores)
+	}
+
+	// "developers" finds "developer" in either field
+	scores = newSampleIndex().Scores(ParseQuery("developers"))
+	if _, found := scores[1]; !found || len(scores) != 2 || scores[2] <= scores[1] {
+		t.Errorf("scores %v, want job 2 above job 1", scores)
+	}
+}
+
+func TestAddReplacesJob(t *testing.T) {
+	index := newSampleIndex()
+	updated := sampleJobs[3]
+	updated.Title = "Golang Platform Engineer"
+	index.Add(3, updated)
+
+	scores := index.Scores(ParseQuery("analyst"))
+	if _, found := scores[3]; !found {
+		t.Errorf("the description of job 3 is no longer indexed: %v", scores)
+	}
+	if matching, _ := index.MatchingKeywords([]string{"data"}); len(matching) != 0 {
+		t.Errorf("the old title still matches: %v", matching)
+	}
+	if scores := index.Scores(ParseQuery("golang")); scores[3] <= scores[1] {
+		t.Errorf("scores %v, want the new title of job 3 above a mention in a description", scores)
+	}
+
+	index.Clear()
+	if scores := index.Scores(ParseQuery("golang")); len(scores) != 0 {
+		t.Errorf("scores %v after clearing", scores)
+	}
+}
+
+func TestMatchesKeywords(t *testing.T) {
+	tests := []struct {
+		keywords []string
+		want     bool
+	}{
+		{[]string{"developers"}, true},
+		{[]string{"backend python"}, true},
+		{[]string{"backend react"}, false},
+		{[]string{"react", "python"}, true},
+		{[]string{"the"}, true},
+	}
+	for _, test := range tests {
+		if got := MatchesKeywords(sampleJobs[1], test.keywords); got != test.want {
+			t.Errorf("%q: got %v, want %v", test.keywords, got, test.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	query := ParseQuery("golang backend")
+	if got, want := Highlight("Golang Backend <Engineer>", query), "<mark>Golang</mark> <mark>Backend</mark> &lt;Engineer&gt;"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := Highlight("Frontend Developer", query); got != "" {
+		t.Errorf("got %q without a match, want nothing", got)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	query := ParseQuery("backend")
+
+	// Short text is shown whole, escaped
+	got := Snippet(sampleJobs[0].Description, query, 200)
+	want := "Acme builds payment APIs. You will design <mark>backend</mark> services in Go &amp; run them on PostgreSQL."
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// Long text is cut around the match
+	long := "Intro words that come first. " + sampleJobs[0].Description + " More words that come after the match and go on for a while."
+	got = Snippet(long, query, 40)
+	if got != "…<mark>backend</mark> services in Go &amp; run them on…" {
+		t.Errorf("got %q, want an excerpt cut on both sides", got)
+	}
+
+	if got := Snippet("Umbrella   needs\nan analyst.", ParseQuery("analyst"), 200); got != "Umbrella needs an <mark>analyst</mark>." {
+		t.Errorf("got %q, want collapsed whitespace", got)
+	}
+	if got := Snippet(sampleJobs[3].Description, query, 200); got != "" {
+		t.Errorf("got %q without a match, want nothing", got)
+	}
+}
+
+func TestStripTags(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Plain text", "Plain text"},
+		{"<p>Build <b>Go</b> services</p><p>Remote &amp; async</p>", " Build Go services  Remote & async "},
+		{"<!-- tracking --><div class=\"job\">Go</div>", "  Go "},
+		{"Salary < 100k & bonus > 10%", "Salary < 100k & bonus > 10%"},
+	}
+	for _, test := range tests {
+		if got := StripTags(test.text); got != test.want {
+			t.Errorf("StripTags(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestHTMLDescriptions(t *testing.T) {
+	job := models.Job{Title: "Engineer", Description: `<p class="intro">Build <strong>backend</strong> services &amp; APIs.</p><br/><a href="https://example.com/apply">Apply</a>`}
+
+	// Tag names and attributes are not indexed
+	index := NewIndex()
+	index.Add(0, job)
+	for _, markupWord := range []string{"strong", "class intro", "href", "example"} {
+		if matching, _ := index.MatchingKeywords([]string{markupWord}); len(matching) != 0 {
+			t.Errorf("%q matched the markup of the description", markupWord)
+		}
+	}
+	if !MatchesKeywords(job, []string{"backend services"}) {
+		t.Error("the text of the description is not searchable")
+	}
+
+	got := Snippet(job.Description, ParseQuery("backend"), 200)
+	if want := "Build <mark>backend</mark> services &amp; APIs. Apply"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,232 @@
+package fulltext
+
+// Stem reduces an English word in lower case to its stem with the Porter algorithm, so
+// "developers", "developing" and "development" all become "develop". Words of two
+// letters or less and words with anything but the letters a to z are returned unchanged.
+func Stem(word string) string {
+	if len(word) <= 2 {
+		return word
+	}
+	for i := 0; i < len(word); i++ {
+		if word[i] < 'a' || word[i] > 'z' {
+			return word
+		}
+	}
+
+	s := &stemmer{b: []byte(word)}
+	s.step1ab()
+	if len(s.b) > 1 {
+		s.step1c()
+		s.step2()
+		s.step3()
+		s.step4()
+		s.step5()
+	}
+	return string(s.b)
+}
+
+// stemmer holds a word being stemmed; j marks the end of the stem before a matched suffix
+type stemmer struct {
+	b []byte
+	j int
+}
+
+// consonant reports whether the letter at i is a consonant; y is one unless it follows a consonant
+func (s *stemmer) consonant(i int) bool {
+	switch s.b[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return false
+	case 'y':
+		return i == 0 || !s.consonant(i-1)
+	}
+	return true
+}
+
+// measure counts the vowel-consonant sequences in b[:j]
+func (s *stemmer) measure() int {
+	n, i := 0, 0
+	for i < s.j && s.consonant(i) {
+		i++
+	}
+	for i < s.j {
+		for i < s.j && !s.consonant(i) {
+			i++
+		}
+		if i >= s.j {
+			break
+		}
+		for i < s.j && s.consonant(i) {
+			i++
+		}
+		n++
+	}
+	return n
+}
+
+// vowelInStem reports whether b[:j] contains a vowel
+func (s *stemmer) vowelInStem() bool {
+	for i := 0; i < s.j; i++ {
+		if !s.consonant(i) {
+			return true
+		}
+	}
+	return false
+}
+
+// doubleConsonant reports whether b[:end] ends with a double consonant
+func (s *stemmer) doubleConsonant(end int) bool {
+	return end >= 2 && s.b[end-1] == s.b[end-2] && s.consonant(end-1)
+}
+
+// cvc reports whether b[:end] ends consonant-vowel-consonant with a last consonant other
+// than w, x or y, as in "hop" and "fil", where a removed e is restored
+func (s *stemmer) cvc(end int) bool {
+	if end < 3 || !s.consonant(end-1) || s.consonant(end-2) || !s.consonant(end-3) {
+		return false
+	}
+	switch s.b[end-1] {
+	case 'w', 'x', 'y':
+		return false
+	}
+	return true
+}
+
+// ends reports whether the word ends with suffix, setting j to the start of the suffix
+func (s *stemmer) ends(suffix string) bool {
+	if len(suffix) > len(s.b) || string(s.b[len(s.b)-len(suffix):]) != suffix {
+		return false
+	}
+	s.j = len(s.b) - len(suffix)
+	return true
+}
+
+// setTo replaces the suffix after j with replacement
+func (s *stemmer) setTo(replacement string) {
+	s.b = append(s.b[:s.j], replacement...)
+}
+
+// replace applies the first rule whose suffix the word ends with, when the stem before it
+// has a measure above minMeasure
+func (s *stemmer) replace(rules [][2]string, minMeasure int) {
+	for _, rule := range rules {
+		if s.ends(rule[0]) {
+			if s.measure() > minMeasure {
+				s.setTo(rule[1])
+			}
+			return
+		}
+	}
+}
+
+// step1ab removes plurals and -ed or -ing
+func (s *stemmer) step1ab() {
+	if s.b[len(s.b)-1] == 's' {
+		switch {
+		case s.ends("sses"):
+			s.setTo("ss")
+		case s.ends("ies"):
+			s.setTo("i")
+		case len(s.b) >= 2 && s.b[len(s.b)-2] != 's':
+			s.b = s.b[:len(s.b)-1]
+		}
+	}
+
+	if s.ends("eed") {
+		if s.measure() > 0 {
+			s.setTo("ee")
+		}
+		return
+	}
+	if !(s.ends("ed") || s.ends("ing")) || !s.vowelInStem() {
+		return
+	}
+	s.b = s.b[:s.j]
+
+	switch {
+	case s.ends("at"):
+		s.setTo("ate")
+	case s.ends("bl"):
+		s.setTo("ble")
+	case s.ends("iz"):
+		s.setTo("ize")
+	case s.doubleConsonant(len(s.b)):
+		switch s.b[len(s.b)-1] {
+		case 'l', 's', 'z':
+		default:
+			s.b = s.b[:len(s.b)-1]
+		}
+	default:
+		s.j = len(s.b)
+		if s.measure() == 1 && s.cvc(len(s.b)) {
+			s.b = append(s.b, 'e')
+		}
+	}
+}
+
+// step1c turns a final y into i when the stem has a vowel
+func (s *stemmer) step1c() {
+	if s.ends("y") && s.vowelInStem() {
+		s.b[len(s.b)-1] = 'i'
+	}
+}
+
+var step2Rules = [][2]string{
+	{"ational", "ate"}, {"tional", "tion"}, {"enci", "ence"}, {"anci", "ance"}, {"izer", "ize"},
+	{"abli", "able"}, {"alli", "al"}, {"entli", "ent"}, {"eli", "e"}, {"ousli", "ous"},
+	{"ization", "ize"}, {"ation", "ate"}, {"ator", "ate"}, {"alism", "al"}, {"iveness", "ive"},
+	{"fulness", "ful"}, {"ousness", "ous"}, {"aliti", "al"}, {"iviti", "ive"}, {"biliti", "ble"},
+}
+
+// step2 maps double suffixes to single ones
+func (s *stemmer) step2() {
+	s.replace(step2Rules, 0)
+}
+
+var step3Rules = [][2]string{
+	{"icate", "ic"}, {"ative", ""}, {"alize", "al"}, {"iciti", "ic"}, {"ical", "ic"}, {"ful", ""}, {"ness", ""},
+}
+
+// step3 removes -ful, -ness and the like
+func (s *stemmer) step3() {
+	s.replace(step3Rules, 0)
+}
+
+var step4Suffixes = []string{
+	"al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement", "ment", "ent",
+	"ion", "ou", "ism", "ate", "iti", "ous", "ive", "ize",
+}
+
+// step4 removes the last suffix from stems with a measure above 1
+func (s *stemmer) step4() {
+	// The longest suffix the word ends with decides; "ement" is tried before "ment" and "ent"
+	best := ""
+	for _, suffix := range step4Suffixes {
+		if len(suffix) > len(best) && s.ends(suffix) {
+			best = suffix
+		}
+	}
+	if best == "" || !s.ends(best) {
+		return
+	}
+	if best == "ion" && (s.j == 0 || (s.b[s.j-1] != 's' && s.b[s.j-1] != 't')) {
+		return
+	}
+	if s.measure() > 1 {
+		s.b = s.b[:s.j]
+	}
+}
+
+// step5 removes a final e and turns a final ll into l on long stems
+func (s *stemmer) step5() {
+	if s.ends("e") {
+		m := s.measure()
+		if m > 1 || (m == 1 && !s.cvc(s.j)) {
+			s.b = s.b[:s.j]
+		}
+	}
+
+	s.j = len(s.b)
+	if s.b[len(s.b)-1] == 'l' && s.doubleConsonant(len(s.b)) && s.measure() > 1 {
+		s.b = s.b[:len(s.b)-1]
+	}
+}
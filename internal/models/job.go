@@ -4,45 +4,124 @@ import "time"
 
 // Job represents a single job posting
 type Job struct {
-	ID              string    `json:"id"`
-	Title           string    `json:"title"`
-	Company         string    `json:"company"`
-	Location        string    `json:"location"`
-	Description     string    `json:"description"`
-	Requirements    []string  `json:"requirements"`
-	Skills          []string  `json:"skills"`
-	SalaryMin       int       `json:"salary_min,omitempty"`
-	SalaryMax       int       `json:"salary_max,omitempty"`
-	SalaryCurrency  string    `json:"salary_currency,omitempty"`
-	DegreeRequired  bool      `json:"degree_required"`
-	ExperienceLevel string    `json:"experience_level"` // entry, mid, senior, lead
-	RemoteOption    string    `json:"remote_option"`    // onsite, remote, hybrid
-	PostedDate      time.Time `json:"posted_date"`
-	URL             string    `json:"url"`
-	Source          string    `json:"source"` // indeed, linkedin, glassdoor
-	CompanySize     string    `json:"company_size,omitempty"`
-	Industry        string    `json:"industry,omitempty"`
-	Benefits        []string  `json:"benefits,omitempty"`
+	ID              string      `json:"id"`
+	Title           string      `json:"title"`
+	Company         string      `json:"company"`
+	Location        string      `json:"location"`
+	Description     string      `json:"description"`
+	Requirements    []string    `json:"requirements"`
+	Skills          []string    `json:"skills"`
+	SalaryMin       int         `json:"salary_min,omitempty"`
+	SalaryMax       int         `json:"salary_max,omitempty"`
+	SalaryCurrency  string      `json:"salary_currency,omitempty"`
+	DegreeRequired  bool        `json:"degree_required"`
+	ExperienceLevel string      `json:"experience_level"`          // entry, mid, senior, lead
+	RemoteOption    string      `json:"remote_option"`             // onsite, remote, hybrid
+	EmploymentType  string      `json:"employment_type,omitempty"` // e.g. FULL_TIME, CONTRACTOR
+	PostedDate      time.Time   `json:"posted_date"`
+	ValidThrough    *time.Time  `json:"valid_through,omitempty"` // when the posting expires, if known
+	URL             string      `json:"url"`
+	Source          string      `json:"source"` // indeed, linkedin, glassdoor
+	CompanySize     string      `json:"company_size,omitempty"`
+	Industry        string      `json:"industry,omitempty"`
+	Benefits        []string    `json:"benefits,omitempty"`
+	AlsoOn          []JobSource `json:"also_on,omitempty"`        // the same posting on other sources
+	FirstSeen       time.Time   `json:"first_seen,omitzero"`      // when a scrape first found the job; set by storage when empty
+	LastSeen        time.Time   `json:"last_seen,omitzero"`       // when a scrape last found the job; set by storage
+	Status          string      `json:"status,omitempty"`         // open, closed or expired; see JobStatusOpen
+	ClosedAt        *time.Time  `json:"closed_at,omitempty"`      // when the job was marked closed
+	Reposts         int         `json:"reposts,omitempty"`        // times the job came back after being closed
+	MissedScrapes   int         `json:"missed_scrapes,omitempty"` // consecutive scrapes of its sources that did not list it
+
+	// FieldsChanged holds when each field last changed in a scrape of a stored job, by the
+	// field names of FieldChange; set by storage
+	FieldsChanged map[string]time.Time `json:"fields_changed,omitempty"`
+
+	// Score and Highlight are set on search results for a text query
+	Score     float64       `json:"score,omitempty"`     // BM25 relevance to the query
+	Highlight *JobHighlight `json:"highlight,omitempty"` // where the query matched
+}
+
+// JobHighlight shows where a search query matched a job, as HTML-escaped text with the
+// matching words wrapped in <mark>. Fields without a match are empty.
+type JobHighlight struct {
+	Title   string `json:"title,omitempty"`
+	Snippet string `json:"snippet,omitempty"` // excerpt of the description around the first match
+}
+
+// Search result orders. Relevance ranks by how well jobs match the title and keywords,
+// and is the default when there are any; otherwise the newest jobs come first.
+const (
+	SortRelevance = "relevance"
+	SortDate      = "date"
+	SortSalary    = "salary"
+)
+
+// Job statuses. A job is closed once its sources stop listing it, and expired when it
+// is still listed past its ValidThrough date.
+const (
+	JobStatusOpen    = "open"
+	JobStatusClosed  = "closed"
+	JobStatusExpired = "expired"
+)
+
+// JobRevision is one update of a stored job that changed some of its fields
+type JobRevision struct {
+	ChangedAt time.Time     `json:"changed_at"`
+	Source    string        `json:"source,omitempty"` // source of the listing that brought the change
+	Changes   []FieldChange `json:"changes"`
+}
+
+// FieldChange is the old and new value of a changed field. Field is the field's JSON
+// name, except that salary_min, salary_max and salary_currency change together as "salary".
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// JobHistory lists the revisions of a stored job, oldest first
+type JobHistory struct {
+	JobID     string        `json:"job_id"`
+	Revisions []JobRevision `json:"revisions"`
+}
+
+// JobSource is another listing of a job, merged into it by duplicate detection
+type JobSource struct {
+	Source string `json:"source"`
+	URL    string `json:"url"`
+	ID     string `json:"id,omitempty"`
 }
 
 // SearchFilters represents the search criteria
 type SearchFilters struct {
-	JobTitle        string   `json:"job_title"`
-	Keywords        []string `json:"keywords"`
-	Location        string   `json:"location"`
-	Locations       []string `json:"locations"` // Multiple locations support
-	RemoteOnly      bool     `json:"remote_only"`
-	MinSalary       int      `json:"min_salary"`
-	MaxSalary       int      `json:"max_salary"`
-	ExperienceLevel string   `json:"experience_level"`
-	DegreeRequired  *bool    `json:"degree_required"` // nil = any, true = required, false = not required
-	Skills          []string `json:"skills"`
-	CompanySize     string   `json:"company_size"`
-	Industry        string   `json:"industry"`
-	JobCategory     string   `json:"job_category"` // healthcare, finance, retail, etc.
-	JobSites        []string `json:"job_sites"`    // Custom job sites URLs
-	Limit           int      `json:"limit"`
-	Offset          int      `json:"offset"`
+	JobTitle           string   `json:"job_title"`
+	Keywords           []string `json:"keywords"`
+	Location           string   `json:"location"`
+	Locations          []string `json:"locations"`          // Multiple locations support
+	Radius             int      `json:"radius"`             // Search radius around Location, in miles
+	PostedWithinDays   int      `json:"posted_within_days"` // Only jobs posted in the last N days
+	RemoteOnly         bool     `json:"remote_only"`
+	MinSalary          int      `json:"min_salary"`
+	MaxSalary          int      `json:"max_salary"`
+	SalaryCurrency     string   `json:"salary_currency"` // currency of MinSalary, MaxSalary and salary analytics; empty is the exchange-rate base (USD)
+	ExperienceLevel    string   `json:"experience_level"`
+	DegreeRequired     *bool    `json:"degree_required"` // nil = any, true = required, false = not required
+	Skills             []string `json:"skills"`
+	CompanySize        string   `json:"company_size"`
+	Industry           string   `json:"industry"`
+	JobCategory        string   `json:"job_category"`          // healthcare, finance, retail, etc.
+	JobSites           []string `json:"job_sites"`             // Custom job sites URLs
+	MaxPages           int      `json:"max_pages"`             // Result pages fetched per source; 0 uses each source's default
+	Status             string   `json:"status"`                // open, closed or expired; empty is any
+	ClosedWithinDays   int      `json:"closed_within_days"`    // Only jobs closed in the last N days
+	OpenLongerThanDays int      `json:"open_longer_than_days"` // Only open jobs posted or first seen more than N days ago
+	MinReposts         int      `json:"min_reposts"`           // Only jobs reposted at least N times
+	ChangedWithinDays  int      `json:"changed_within_days"`   // Only jobs changed in the last N days
+	ChangedFields      []string `json:"changed_fields"`        // Fields that must have changed, e.g. salary or title; empty is any
+	Sort               string   `json:"sort"`                  // relevance, date or salary; see SortRelevance
+	Limit              int      `json:"limit"`
+	Offset             int      `json:"offset"`
 }
 
 // SearchResponse represents the response from job search
@@ -58,6 +137,7 @@ type JobAnalytics struct {
 	TotalJobs            int            `json:"total_jobs"`
 	AverageSalary        float64        `json:"average_salary"`
 	SalaryRange          SalaryRange    `json:"salary_range"`
+	SalaryCurrency       string         `json:"salary_currency,omitempty"` // currency the salary figures were converted to
 	TopSkills            []SkillCount   `json:"top_skills"`
 	TopCompanies         []CompanyCount `json:"top_companies"`
 	ExperienceLevels     map[string]int `json:"experience_levels"`
@@ -65,6 +145,27 @@ type JobAnalytics struct {
 	DegreeRequirements   map[string]int `json:"degree_requirements"`
 	LocationDistribution map[string]int `json:"location_distribution"`
 	IndustryDistribution map[string]int `json:"industry_distribution"`
+	StatusDistribution   map[string]int `json:"status_distribution,omitempty"`
+	TimeToFill           *TimeToFill    `json:"time_to_fill,omitempty"`  // nil without closed jobs
+	TopReposters         []CompanyCount `json:"top_reposters,omitempty"` // companies by reposts of their jobs
+}
+
+// TimeToFill summarizes how long closed jobs stayed open, from when they were posted
+// (or first seen, if earlier) to when they were last seen
+type TimeToFill struct {
+	ClosedJobs  int             `json:"closed_jobs"`
+	AverageDays float64         `json:"average_days"`
+	MedianDays  float64         `json:"median_days"`
+	ByCompany   []GroupFillTime `json:"by_company"`
+	BySkill     []GroupFillTime `json:"by_skill"`
+}
+
+// GroupFillTime is the time to fill of the closed jobs of one company or skill
+type GroupFillTime struct {
+	Name        string  `json:"name"`
+	ClosedJobs  int     `json:"closed_jobs"`
+	AverageDays float64 `json:"average_days"`
+	MedianDays  float64 `json:"median_days"`
 }
 
 // SalaryRange represents salary statistics
@@ -78,8 +179,9 @@ type SalaryRange struct {
 
 // SkillCount represents skill frequency
 type SkillCount struct {
-	Skill string `json:"skill"`
-	Count int    `json:"count"`
+	Skill    string `json:"skill"`              // canonical name
+	Category string `json:"category,omitempty"` // taxonomy category; empty for skills outside the taxonomy
+	Count    int    `json:"count"`
 }
 
 // CompanyCount represents company hiring frequency
@@ -90,9 +192,15 @@ type CompanyCount struct {
 
 // ScrapingResult represents the result from a single scraping operation
 type ScrapingResult struct {
-	Jobs   []Job  `json:"jobs"`
-	Source string `json:"source"`
-	Error  error  `json:"error,omitempty"`
+	Jobs    []Job  `json:"jobs"`
+	Source  string `json:"source"`
+	Error   error  `json:"error,omitempty"`
+	Retries int    `json:"retries"` // requests retried after a temporary failure
+
+	// Complete is set when the scraper read everything the source lists for the search:
+	// no page failed and it did not stop early at a limit. Only then does a job missing
+	// from Jobs mean the source no longer lists it.
+	Complete bool `json:"complete"`
 }
 
 // JobSiteConfig represents configuration for a custom job site
@@ -108,3 +216,32 @@ type SearchRequest struct {
 	Filters  SearchFilters   `json:"filters"`
 	JobSites []JobSiteConfig `json:"job_sites"`
 }
+
+// SearchStatus represents the lifecycle state of an asynchronous search
+type SearchStatus string
+
+const (
+	SearchRunning   SearchStatus = "running"
+	SearchCompleted SearchStatus = "completed"
+	SearchCancelled SearchStatus = "cancelled"
+)
+
+// SourceProgress reports how far a single source has got within an asynchronous search
+type SourceProgress struct {
+	Source   string `json:"source"`
+	Status   string `json:"status"` // running, completed, failed, cancelled
+	JobCount int    `json:"job_count"`
+	Retries  int    `json:"retries"`
+	Error    string `json:"error,omitempty"`
+}
+
+// SearchProgress represents the state of an asynchronous search and its results so far
+type SearchProgress struct {
+	ID          string           `json:"id"`
+	Status      SearchStatus     `json:"status"`
+	CreatedAt   time.Time        `json:"created_at"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time       `json:"expires_at,omitempty"`
+	Sources     []SourceProgress `json:"sources"`
+	Results     *SearchResponse  `json:"results"`
+}
@@ -0,0 +1,370 @@
+// Package salary reads salaries written the many ways job postings write them, such as
+// "$120k - $150k", "€60–80k per year", "up to £45,000", "$52.50/hr" or
+// "Rp 15.000.000 - 25.000.000 per month", into an amount range, currency and pay period.
+package salary
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Period is how often an amount is paid
+type Period string
+
+// Pay periods. PeriodUnknown is used when a salary does not say and no period could be guessed.
+const (
+	PeriodUnknown Period = ""
+	PeriodHour    Period = "hour"
+	PeriodDay     Period = "day"
+	PeriodWeek    Period = "week"
+	PeriodMonth   Period = "month"
+	PeriodYear    Period = "year"
+)
+
+// PerYear returns how many periods make up a working year; unknown periods count as yearly
+func (p Period) PerYear() float64 {
+	switch p {
+	case PeriodHour:
+		return 2080
+	case PeriodDay:
+		return 260
+	case PeriodWeek:
+		return 52
+	case PeriodMonth:
+		return 12
+	default:
+		return 1
+	}
+}
+
+// Salary is a parsed salary. Min or Max is 0 for open ranges such as "up to $90k" or
+// "from $70k"; both are 0 when no salary was found.
+type Salary struct {
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+	Currency   string  `json:"currency"` // ISO 4217 code; empty when the text names none
+	Period     Period  `json:"period"`
+	Confidence float64 `json:"confidence"` // 0 to 1, how sure the parse is that this is the salary
+}
+
+// IsZero reports whether no salary was found
+func (s Salary) IsZero() bool {
+	return s.Min == 0 && s.Max == 0
+}
+
+// Annual returns the range paid per year, rounded to whole units of the currency
+func (s Salary) Annual() (int, int) {
+	perYear := s.Period.PerYear()
+	return int(math.Round(s.Min * perYear)), int(math.Round(s.Max * perYear))
+}
+
+// Parse reads a salary field. Bare numbers are accepted, since the field says what they are.
+func Parse(text string) Salary {
+	return parse(text, false)
+}
+
+// Find reads the first salary mentioned in free text such as a job description. Only
+// amounts with a currency count, so "5+ years" or "team of 40" are not taken for salaries.
+func Find(text string) Salary {
+	return parse(text, true)
+}
+
+// ParsePeriod reads a pay period name such as "HOUR", "per month", "yr" or "annually"
+func ParsePeriod(unit string) Period {
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	unit = strings.TrimPrefix(strings.TrimPrefix(strings.TrimPrefix(unit, "/"), "per "), "a ")
+	return periodNames[strings.TrimSpace(unit)]
+}
+
+var periodNames = map[string]Period{
+	"hour": PeriodHour, "stunde": PeriodHour, "hours": PeriodHour, "hr": PeriodHour, "hrs": PeriodHour, "h": PeriodHour, "hourly": PeriodHour, "an hour": PeriodHour,
+	"day": PeriodDay, "days": PeriodDay, "daily": PeriodDay, "diem": PeriodDay, "d": PeriodDay,
+	"week": PeriodWeek, "weeks": PeriodWeek, "wk": PeriodWeek, "weekly": PeriodWeek, "w": PeriodWeek,
+	"month": PeriodMonth, "months": PeriodMonth, "mo": PeriodMonth, "mth": PeriodMonth, "monthly": PeriodMonth, "pcm": PeriodMonth, "bulan": PeriodMonth, "monat": PeriodMonth, "mês": PeriodMonth, "mes": PeriodMonth, "m": PeriodMonth,
+	"year": PeriodYear, "years": PeriodYear, "yr": PeriodYear, "y": PeriodYear, "annum": PeriodYear, "annual": PeriodYear,
+	"annually": PeriodYear, "yearly": PeriodYear, "pa": PeriodYear, "p.a.": PeriodYear, "p.a": PeriodYear, "tahun": PeriodYear, "jahr": PeriodYear,
+}
+
+// currencyCodes maps symbols, codes and names to ISO 4217 codes
+var currencyCodes = map[string]string{
+	"$": "USD", "us$": "USD", "usd": "USD", "dollar": "USD", "dollars": "USD",
+	"ca$": "CAD", "c$": "CAD", "cad": "CAD",
+	"au$": "AUD", "a$": "AUD", "aud": "AUD",
+	"nz$": "NZD", "nzd": "NZD",
+	"s$": "SGD", "sgd": "SGD",
+	"hk$": "HKD", "hkd": "HKD",
+	"r$": "BRL", "brl": "BRL",
+	"mx$": "MXN", "mxn": "MXN",
+	"€": "EUR", "eur": "EUR", "euro": "EUR", "euros": "EUR",
+	"£": "GBP", "gbp": "GBP", "pound": "GBP", "pounds": "GBP",
+	"¥": "JPY", "jpy": "JPY", "yen": "JPY",
+	"₹": "INR", "inr": "INR", "rs": "INR", "rs.": "INR",
+	"rp": "IDR", "rp.": "IDR", "idr": "IDR", "rupiah": "IDR",
+	"chf": "CHF", "sek": "SEK", "nok": "NOK", "dkk": "DKK", "kr": "SEK",
+	"pln": "PLN", "zł": "PLN", "zl": "PLN",
+	"rm": "MYR", "myr": "MYR", "₱": "PHP", "php": "PHP",
+	"₩": "KRW", "krw": "KRW", "zar": "ZAR",
+}
+
+// largeUnitCurrencies are currencies whose yearly salaries run into the millions
+var largeUnitCurrencies = map[string]bool{"IDR": true, "JPY": true, "KRW": true, "INR": true, "VND": true}
+
+var (
+	// amountPattern matches a number with thousands separators (including Indian lakh
+	// grouping) or a decimal part
+	amountPattern = regexp.MustCompile(`(\d{1,2}(?:,\d{2})+,\d{3}|\d{1,3}(?:[,.'’\x{00a0}\x{202f} ]\d{3})+)([.,]\d{1,2})?|(\d+)([.,]\d+)?`)
+	// suffixPattern matches a multiplier, which must end the word so the "k" of "kotlin"
+	// is not a thousand
+	suffixPattern = regexp.MustCompile(`(?i)^\s?(k|thousand|mn|m|million|juta|jt|lpa|lakhs?|lacs?|crores?|cr)\b\.?`)
+
+	// The currency patterns list longer symbols first, so "CA$" is not read as "$"
+	currencyBefore = regexp.MustCompile(`(?i)(us\$|ca\$|c\$|au\$|a\$|nz\$|s\$|hk\$|r\$|mx\$|\$|€|£|¥|₹|₱|₩|zł|\b(?:usd|cad|aud|nzd|sgd|hkd|brl|mxn|eur|gbp|jpy|inr|idr|chf|sek|nok|dkk|pln|myr|php|krw|zar|rp\.?|rs\.?|rm|kr))\s*$`)
+	currencyAfter  = regexp.MustCompile(`(?i)^\s*(€|£|zł|\b(?:usd|cad|aud|nzd|sgd|hkd|brl|mxn|eur|gbp|jpy|inr|idr|chf|sek|nok|dkk|pln|myr|php|krw|zar|kr|dollars?|euros?|pounds?|yen|rupiah)\b)`)
+
+	rangeSeparator = regexp.MustCompile(`(?i)^\s*(?:-|–|—|~|to|and|bis|hingga|sampai)\s*$`)
+	upToBefore     = regexp.MustCompile(`(?i)\b(?:up\s*to|upto|max(?:imum)?|under|below|less than|not more than|hingga)\s*:?\s*$`)
+	fromBefore     = regexp.MustCompile(`(?i)\b(?:from|starting(?:\s+at|\s+from)?|at least|min(?:imum)?|over|above|more than|mulai(?:\s+dari)?|ab)\s*:?\s*$`)
+	fromAfter      = regexp.MustCompile(`(?i)^(?:\+|\s+(?:or more|and (?:above|up))\b)`)
+
+	// periodAfter matches "/hr", "per month", "a year", "annually" and the like. Single
+	// letters only count after a slash, so the "d" of "and" is not a day.
+	periodAfter  = regexp.MustCompile(`(?i)^\s*(?:/\s*|(?:per|por|an?|each|every|pro)\s+)?(hours?|hrs?|hourly|stunde|days?|daily|diem|weeks?|wk|weekly|months?|mo|mth|monthly|pcm|bulan|monat|mês|mes|years?|yr|annum|annual(?:ly)?|yearly|p\.?a\.?|tahun|jahr)(?:[^a-z]|$)|^\s*/\s*([hdwmy])(?:[^a-z]|$)`)
+	periodBefore = regexp.MustCompile(`(?i)\b(hourly|daily|weekly|monthly|annual(?:ly)?|yearly|per (?:hour|day|week|month|year|annum))\b[^\d]{0,20}$`)
+	noSalary     = regexp.MustCompile(`(?i)^\s*(?:competitive|negotiable|doe|depending on experience|tbd|n/?a|unpaid)\b`)
+)
+
+// amount is one number found in the text
+type amount struct {
+	start, end int // byte offsets of the number and its multiplier
+	value      float64
+	multiplier float64 // 1 when none was written
+	currency   string
+	explicit   bool   // the number had its own currency marker
+	period     Period // set by multipliers that imply one, like "LPA"
+}
+
+// parse finds the first salary in text; requireCurrency skips numbers without a currency
+func parse(text string, requireCurrency bool) Salary {
+	if noSalary.MatchString(text) {
+		return Salary{}
+	}
+
+	amounts := findAmounts(text)
+	for i := range amounts {
+		first := amounts[i]
+		if requireCurrency && first.currency == "" {
+			continue
+		}
+
+		// A second amount joined by a range separator completes the range
+		last := first
+		isRange := false
+		if i+1 < len(amounts) {
+			next := amounts[i+1]
+			if joined(text, first, next) && (next.currency == "" || first.currency == "" || next.currency == first.currency) {
+				last, isRange = next, true
+			}
+		}
+
+		return build(text, first, last, isRange)
+	}
+	return Salary{}
+}
+
+// findAmounts returns every number in text with its multiplier and currency
+func findAmounts(text string) []amount {
+	var amounts []amount
+	for _, loc := range amountPattern.FindAllStringSubmatchIndex(text, -1) {
+		// Skip digits that continue a word or a longer number, like "H1B" or "v2.3", unless
+		// a currency is written right before them, like "Rp10jt"
+		if loc[0] > 0 {
+			previous := text[loc[0]-1]
+			continuesWord := isLetter(previous) || previous == '.' || previous == ','
+			if continuesWord && !currencyBefore.MatchString(text[:loc[0]]) {
+				continue
+			}
+		}
+
+		var number string
+		if loc[2] >= 0 {
+			number = digitsOnly(text[loc[2]:loc[3]])
+			if loc[4] >= 0 {
+				number += "." + text[loc[4]+1:loc[5]]
+			}
+		} else {
+			number = text[loc[6]:loc[7]]
+			if loc[8] >= 0 {
+				number += "." + text[loc[8]+1:loc[9]]
+			}
+		}
+		value, err := strconv.ParseFloat(number, 64)
+		if err != nil {
+			continue
+		}
+
+		a := amount{start: loc[0], end: loc[1], value: value, multiplier: 1}
+		if suffix := suffixPattern.FindStringSubmatch(text[a.end:]); suffix != nil {
+			switch strings.ToLower(suffix[1]) {
+			case "k", "thousand":
+				a.multiplier = 1e3
+			case "lpa":
+				a.multiplier, a.period, a.currency = 1e5, PeriodYear, "INR"
+			case "lakh", "lakhs", "lac", "lacs":
+				a.multiplier, a.currency = 1e5, "INR"
+			case "crore", "crores", "cr":
+				a.multiplier, a.currency = 1e7, "INR"
+			case "juta", "jt":
+				a.multiplier, a.currency = 1e6, "IDR"
+			default:
+				a.multiplier = 1e6
+			}
+			a.end += len(suffix[0])
+		} else if a.end < len(text) && isLetter(text[a.end]) {
+			// "3rd", "24h" and the like are not amounts
+			if periodAfter.FindStringIndex(text[a.end:]) == nil {
+				continue
+			}
+		}
+
+		// A written currency overrides the one a multiplier implies
+		if match := currencyBefore.FindStringSubmatch(text[:a.start]); match != nil {
+			a.currency, a.explicit = currencyCodes[strings.ToLower(match[1])], true
+		} else if match := currencyAfter.FindStringSubmatch(text[a.end:]); match != nil {
+			a.currency, a.explicit = currencyCodes[strings.ToLower(match[1])], true
+		}
+		amounts = append(amounts, a)
+	}
+
+	// A currency written once covers a range: "$80 - 100k", "60-80k EUR"
+	for i := 0; i+1 < len(amounts); i++ {
+		if joined(text, amounts[i], amounts[i+1]) {
+			if amounts[i].currency == "" {
+				amounts[i].currency = amounts[i+1].currency
+			} else if amounts[i+1].currency == "" {
+				amounts[i+1].currency = amounts[i].currency
+			}
+		}
+	}
+	return amounts
+}
+
+// joined reports whether only a range separator and currencies stand between two amounts
+func joined(text string, first, next amount) bool {
+	between := text[first.end:next.start]
+	if loc := currencyAfter.FindStringIndex(between); loc != nil {
+		between = between[loc[1]:]
+	}
+	if loc := currencyBefore.FindStringIndex(between); loc != nil {
+		between = between[:loc[0]]
+	}
+	return rangeSeparator.MatchString(between)
+}
+
+// build turns the amounts of a salary into a Salary with a period and confidence
+func build(text string, first, last amount, isRange bool) Salary {
+	low, high := first.value*first.multiplier, last.value*last.multiplier
+	if isRange && first.multiplier == 1 && last.multiplier > 1 && first.value*last.multiplier <= high {
+		// "€60–80k": the multiplier written once covers both ends
+		low = first.value * last.multiplier
+	}
+	if isRange && low > high {
+		low, high = high, low
+	}
+
+	s := Salary{Currency: first.currency}
+	if s.Currency == "" {
+		s.Currency = last.currency
+	}
+
+	before := text[:first.start]
+	if loc := currencyBefore.FindStringIndex(before); loc != nil {
+		before = before[:loc[0]]
+	}
+	after := text[last.end:]
+	if loc := currencyAfter.FindStringIndex(after); loc != nil {
+		after = after[loc[1]:]
+	}
+
+	switch {
+	case isRange:
+		s.Min, s.Max = low, high
+	case upToBefore.MatchString(before):
+		s.Max = low
+	case fromBefore.MatchString(before) || fromAfter.MatchString(after):
+		s.Min = low
+		if match := fromAfter.FindStringIndex(after); match != nil {
+			after = after[match[1]:]
+		}
+	default:
+		s.Min, s.Max = low, low
+	}
+
+	// The period usually follows the amount; otherwise look for "Hourly rate:" and the like
+	explicitPeriod := true
+	if match := periodAfter.FindStringSubmatch(after); match != nil {
+		s.Period = ParsePeriod(match[1] + match[2])
+	} else if match := periodBefore.FindStringSubmatch(before); match != nil {
+		s.Period = ParsePeriod(match[1])
+	} else if last.period != PeriodUnknown {
+		s.Period = last.period
+	}
+	if s.Period == PeriodUnknown {
+		explicitPeriod = false
+		s.Period = guessPeriod(math.Max(s.Min, s.Max), s.Currency)
+	}
+
+	s.Confidence = confidence(s, first.explicit || last.explicit, explicitPeriod, isRange)
+	return s
+}
+
+// guessPeriod picks the period an amount without one most likely is: small amounts are
+// hourly rates, rupiah salaries are quoted per month and everything else is yearly
+func guessPeriod(value float64, currency string) Period {
+	switch {
+	case currency == "IDR" && value < 60_000_000:
+		return PeriodMonth
+	case value > 0 && value < 200 && !largeUnitCurrencies[currency]:
+		return PeriodHour
+	default:
+		return PeriodYear
+	}
+}
+
+// confidence scores a parse: a currency, a stated period and a range each make it more
+// likely that the numbers are a salary; an implausible yearly amount makes it less likely
+func confidence(s Salary, hasCurrency, explicitPeriod, isRange bool) float64 {
+	score := 0.4
+	if hasCurrency {
+		score += 0.3
+	}
+	if explicitPeriod {
+		score += 0.2
+	}
+	if isRange {
+		score += 0.1
+	}
+
+	low, high := s.Annual()
+	yearly := math.Max(float64(low), float64(high))
+	if yearly < 1000 || (yearly > 2_000_000 && !largeUnitCurrencies[s.Currency]) {
+		score /= 2
+	}
+	return math.Round(math.Min(score, 1)*100) / 100
+}
+
+// digitsOnly drops thousands separators
+func digitsOnly(number string) string {
+	var b strings.Builder
+	for _, r := range number {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
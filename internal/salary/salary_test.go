@@ -0,0 +1,61 @@
+package salary
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// corpusCase is one entry of testdata/salaries.json
+type corpusCase struct {
+	Text     string  `json:"text"`
+	FreeText bool    `json:"free_text"` // read with Find instead of Parse
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Currency string  `json:"currency"`
+	Period   string  `json:"period"`
+}
+
+func TestCorpus(t *testing.T) {
+	data, err := os.ReadFile("testdata/salaries.json")
+	if err != nil {
+		t.Fatalf("failed to read corpus: %v", err)
+	}
+	var cases []corpusCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		t.Fatalf("failed to parse corpus: %v", err)
+	}
+
+	for _, c := range cases {
+		parsed := Parse(c.Text)
+		if c.FreeText {
+			parsed = Find(c.Text)
+		}
+
+		period := string(parsed.Period)
+		if parsed.IsZero() {
+			period = ""
+		}
+		if parsed.Min != c.Min || parsed.Max != c.Max || parsed.Currency != c.Currency || period != c.Period {
+			t.Errorf("%q: got %v-%v %s/%s, want %v-%v %s/%s",
+				c.Text, parsed.Min, parsed.Max, parsed.Currency, period, c.Min, c.Max, c.Currency, c.Period)
+		}
+	}
+}
+
+func TestAnnual(t *testing.T) {
+	tests := []struct {
+		text             string
+		wantMin, wantMax int
+	}{
+		{"$120k - $150k", 120000, 150000},
+		{"$52.50/hr", 109200, 109200},
+		{"Rp 10 - 15 juta per bulan", 120000000, 180000000},
+		{"$100k+", 100000, 0},
+	}
+	for _, test := range tests {
+		if low, high := Parse(test.text).Annual(); low != test.wantMin || high != test.wantMax {
+			t.Errorf("%q: got %d-%d a year, want %d-%d", test.text, low, high, test.wantMin, test.wantMax)
+		}
+	}
+}
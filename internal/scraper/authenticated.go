@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/salary"
 	"github.com/PuerkitoBio/goquery"
 )
 
@@ -120,29 +121,36 @@ func (j *JobStreetScraper) Scrape(ctx context.Context, filters models.SearchFilt
 
 	searchURL := j.buildSearchURL(filters)
 
-	doc, err := j.FetchDocument(ctx, searchURL)
+	var jobs []models.Job
+	wanted := wantedJobs(filters)
+
+	err := j.FetchPages(ctx, searchURL, jobStreetPagination, filters, func(doc *goquery.Document, pageURL string) (int, bool) {
+		// Try to parse any publicly available job listings
+		listings := doc.Find("[data-automation='jobListing']")
+		listings.Each(func(i int, s *goquery.Selection) {
+			job := j.parseJobStreetListing(s)
+			if job.Title != "" {
+				jobs = append(jobs, job)
+			}
+		})
+		return listings.Length(), wanted > 0 && len(jobs) >= wanted
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch JobStreet: %w", err)
 	}
 
-	var jobs []models.Job
-
-	// Try to parse any publicly available job listings
-	doc.Find("[data-automation='jobListing']").Each(func(i int, s *goquery.Selection) {
-		job := j.parseJobStreetListing(s)
-		if job.Title != "" {
-			jobs = append(jobs, job)
-		}
-	})
-
-	// If no public data found, return demo data
+	// If no public data found, return demo data; it says nothing about what is listed
 	if len(jobs) == 0 {
+		markPartial(ctx)
 		return j.generateJobStreetDemoJobs(filters), nil
 	}
 
 	return jobs, nil
 }
 
+// jobStreetPagination numbers result pages with the "page" query parameter
+var jobStreetPagination = Pagination{PageParam: "page", StartPage: 1, MaxPages: 3}
+
 func (j *JobStreetScraper) buildSearchURL(filters models.SearchFilters) string {
 	baseURL := fmt.Sprintf("%s/jobs", j.baseURL)
 	params := url.Values{}
@@ -166,6 +174,7 @@ func (j *JobStreetScraper) parseJobStreetListing(s *goquery.Selection) models.Jo
 	title := j.CleanText(s.Find("[data-automation='jobTitle']").Text())
 	company := j.CleanText(s.Find("[data-automation='jobCompany']").Text())
 	location := j.CleanText(s.Find("[data-automation='jobLocation']").Text())
+	salaryMin, salaryMax, currency := annualSalary(salary.Parse(j.CleanText(s.Find("[data-automation='jobSalary']").Text())), "USD")
 
 	// Extract job URL
 	jobURL, _ := s.Find("a").Attr("href")
@@ -174,15 +183,15 @@ func (j *JobStreetScraper) parseJobStreetListing(s *goquery.Selection) models.Jo
 	}
 
 	return models.Job{
-		ID:              fmt.Sprintf("jobstreet-%s-%d", strings.ReplaceAll(title, " ", "-"), time.Now().Unix()),
+		ID:              jobID("jobstreet", lastPathSegment(jobURL), jobURL),
 		Title:           title,
 		Company:         company,
 		Location:        location,
 		Description:     fmt.Sprintf("Job at %s in %s", company, location),
 		Skills:          []string{},
-		SalaryMin:       0,
-		SalaryMax:       0,
-		SalaryCurrency:  "USD",
+		SalaryMin:       salaryMin,
+		SalaryMax:       salaryMax,
+		SalaryCurrency:  currency,
 		DegreeRequired:  false,
 		ExperienceLevel: "mid",
 		RemoteOption:    "onsite",
@@ -193,6 +202,11 @@ func (j *JobStreetScraper) parseJobStreetListing(s *goquery.Selection) models.Jo
 	}
 }
 
+// ParseDetail implements DetailScraper, reading the full job ad from its page
+func (j *JobStreetScraper) ParseDetail(doc *goquery.Document, job *models.Job) {
+	j.EnrichFromDetail(doc, job, "[data-automation='jobAdDetails']")
+}
+
 func (j *JobStreetScraper) generateJobStreetDemoJobs(filters models.SearchFilters) []models.Job {
 	return []models.Job{
 		{
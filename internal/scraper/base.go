@@ -2,14 +2,19 @@ package scraper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/Illuminateee/web-scrapper.git/internal/currency"
 	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/salary"
+	"github.com/Illuminateee/web-scrapper.git/internal/skills"
 	"github.com/PuerkitoBio/goquery"
 )
 
@@ -22,13 +27,17 @@ type JobScraper interface {
 
 // ScraperManager manages multiple scrapers and coordinates concurrent scraping
 type ScraperManager struct {
-	scrapers    []JobScraper
-	rateLimiter *RateLimiter
-	client      *http.Client
+	scrapers          []JobScraper
+	registry          *ScraperRegistry
+	client            *http.Client
+	detailConcurrency int // detail pages fetched at once per scraper; 0 skips detail pages
+	details           *detailCache
+	breakers          *circuitBreakers
 }
 
-// NewScraperManager creates a new scraper manager
-func NewScraperManager() *ScraperManager {
+// NewScraperManager creates a new scraper manager. When a registry is given, its enabled
+// scrapers are looked up on every search so enabling or disabling one takes effect immediately.
+func NewScraperManager(registry *ScraperRegistry) *ScraperManager {
 	client := &http.Client{
 		Timeout: 30 * time.Second,
 		Transport: &http.Transport{
@@ -39,35 +48,142 @@ func NewScraperManager() *ScraperManager {
 	}
 
 	return &ScraperManager{
-		scrapers:    make([]JobScraper, 0),
-		rateLimiter: NewRateLimiter(5, time.Second), // 5 requests per second
-		client:      client,
+		scrapers: make([]JobScraper, 0),
+		registry: registry,
+		client:   client,
+		details:  newDetailCache(),
+		breakers: newCircuitBreakers(DefaultCircuitFailures, DefaultCircuitCooldown),
 	}
 }
 
-// AddScraper adds a scraper to the manager
+// SetCircuitBreaker changes how many consecutive failures skip a source and for how long.
+// Zero failures disables the circuit breaker.
+func (sm *ScraperManager) SetCircuitBreaker(failures int, cooldown time.Duration) {
+	sm.breakers.configure(failures, cooldown)
+}
+
+// CircuitStatus reports the circuit breaker of a source, by registry key or scraper name
+func (sm *ScraperManager) CircuitStatus(key string) CircuitStatus {
+	return sm.breakers.status(key)
+}
+
+// SetDetailConcurrency enables fetching detail pages for scrapers that implement DetailScraper,
+// with at most n pages fetched at once per scraper. Zero disables detail pages.
+func (sm *ScraperManager) SetDetailConcurrency(n int) {
+	sm.detailConcurrency = n
+}
+
+// AddScraper adds a scraper to the manager in addition to those from the registry
 func (sm *ScraperManager) AddScraper(scraper JobScraper) {
 	sm.scrapers = append(sm.scrapers, scraper)
 }
 
+// activeScrapers returns the scrapers to use for a search
+func (sm *ScraperManager) activeScrapers() []registeredScraper {
+	var scrapers []registeredScraper
+	if sm.registry != nil {
+		scrapers = sm.registry.enabledScrapers()
+	}
+	for _, scraper := range sm.scrapers {
+		scrapers = append(scrapers, registeredScraper{scraper: scraper})
+	}
+	return scrapers
+}
+
+// Names returns the names of all scrapers a search would currently use
+func (sm *ScraperManager) Names() []string {
+	active := sm.activeScrapers()
+	names := make([]string, 0, len(active))
+	for _, registered := range active {
+		names = append(names, registered.scraper.Name())
+	}
+	return names
+}
+
 // ScrapeAll scrapes jobs from all registered scrapers concurrently
 func (sm *ScraperManager) ScrapeAll(ctx context.Context, filters models.SearchFilters) []models.ScrapingResult {
+	return sm.ScrapeAllWithProgress(ctx, filters, nil)
+}
+
+// ScrapeAllWithProgress scrapes like ScrapeAll, calling onResult as soon as each scraper finishes.
+// onResult may be called concurrently from several goroutines.
+func (sm *ScraperManager) ScrapeAllWithProgress(ctx context.Context, filters models.SearchFilters, onResult func(models.ScrapingResult)) []models.ScrapingResult {
+	return sm.scrape(ctx, filters, sm.activeScrapers(), nil, onResult)
+}
+
+// ScrapeSites scrapes only the given job sites, resolved through the registry.
+// Sites that cannot be resolved are reported as failed results.
+func (sm *ScraperManager) ScrapeSites(ctx context.Context, filters models.SearchFilters, sites []models.JobSiteConfig, onResult func(models.ScrapingResult)) []models.ScrapingResult {
+	var active []registeredScraper
+	var failed []models.ScrapingResult
+
+	for _, site := range sites {
+		if sm.registry == nil {
+			failed = append(failed, models.ScrapingResult{Source: site.Name, Error: fmt.Errorf("no scraper registry configured")})
+			continue
+		}
+
+		scraper, key, err := sm.registry.ScraperForSite(site)
+		if err != nil {
+			failed = append(failed, models.ScrapingResult{Source: site.Name, Error: err})
+			continue
+		}
+		active = append(active, registeredScraper{key: key, scraper: scraper})
+	}
+
+	return sm.scrape(ctx, filters, active, failed, onResult)
+}
+
+// scrape runs the given scrapers concurrently; failed holds results known before scraping starts
+func (sm *ScraperManager) scrape(ctx context.Context, filters models.SearchFilters, active []registeredScraper, failed []models.ScrapingResult, onResult func(models.ScrapingResult)) []models.ScrapingResult {
+	for _, result := range failed {
+		log.Printf("Error scraping %s: %v", result.Source, result.Error)
+		if onResult != nil {
+			onResult(result)
+		}
+	}
+
 	var wg sync.WaitGroup
-	results := make([]models.ScrapingResult, len(sm.scrapers))
+	results := make([]models.ScrapingResult, len(active))
 
-	for i, scraper := range sm.scrapers {
+	for i, registered := range active {
 		wg.Add(1)
-		go func(index int, s JobScraper) {
+		go func(index int, key string, s JobScraper) {
 			defer wg.Done()
 
-			// Rate limiting
-			sm.rateLimiter.Wait(ctx)
+			// Skip sources that keep failing until their cooldown has passed
+			breakerKey := key
+			if breakerKey == "" {
+				breakerKey = s.Name()
+			}
+			if err := sm.breakers.allow(breakerKey); err != nil {
+				results[index] = models.ScrapingResult{Source: s.Name(), Error: err}
+				log.Printf("Skipping %s: %v", s.Name(), err)
+				if onResult != nil {
+					onResult(results[index])
+				}
+				return
+			}
+
+			// Count the retries of every request this scraper makes, and whether it read
+			// everything the source lists
+			scrapeCtx, retries := withRetryCounter(ctx)
+			scrapeCtx, partial := withPartialFlag(scrapeCtx)
 
-			jobs, err := s.Scrape(ctx, filters)
+			jobs, err := s.Scrape(scrapeCtx, filters)
+			if detailScraper, ok := s.(DetailScraper); ok && err == nil {
+				jobs = sm.enrichDetails(scrapeCtx, detailScraper, jobs)
+			}
+			// Skills listed by the source use its own spelling
+			for j := range jobs {
+				jobs[j].Skills = skills.Canonicalize(jobs[j].Skills)
+			}
 			results[index] = models.ScrapingResult{
-				Jobs:   jobs,
-				Source: s.Name(),
-				Error:  err,
+				Jobs:     jobs,
+				Source:   s.Name(),
+				Error:    err,
+				Retries:  int(atomic.LoadInt64(retries)),
+				Complete: err == nil && atomic.LoadInt32(partial) == 0,
 			}
 
 			if err != nil {
@@ -75,11 +191,20 @@ func (sm *ScraperManager) ScrapeAll(ctx context.Context, filters models.SearchFi
 			} else {
 				log.Printf("Successfully scraped %d jobs from %s", len(jobs), s.Name())
 			}
-		}(i, scraper)
+
+			sm.breakers.record(breakerKey, err, ctx.Err() != nil)
+			if key != "" {
+				sm.registry.RecordResult(key, results[index])
+			}
+
+			if onResult != nil {
+				onResult(results[index])
+			}
+		}(i, registered.key, registered.scraper)
 	}
 
 	wg.Wait()
-	return results
+	return append(failed, results...)
 }
 
 // GetAllJobs aggregates jobs from all scraping results
@@ -127,6 +252,60 @@ func (bs *BaseScraper) GetBaseURL() string {
 	return bs.baseURL
 }
 
+// SetBaseURL points the scraper at another host serving the same pages, such as a local
+// fake board
+func (bs *BaseScraper) SetBaseURL(baseURL string) {
+	bs.baseURL = strings.TrimSuffix(baseURL, "/")
+}
+
+// annualSalary returns the yearly range of a parsed salary and its currency, or fallback
+// when the salary names none
+func annualSalary(parsed salary.Salary, fallback string) (int, int, string) {
+	salaryMin, salaryMax := parsed.Annual()
+	if parsed.Currency == "" {
+		return salaryMin, salaryMax, fallback
+	}
+	return salaryMin, salaryMax, parsed.Currency
+}
+
+// matchesSkills checks that a job has every skill of the filters, comparing canonical names
+func matchesSkills(job models.Job, filters models.SearchFilters) bool {
+	if len(filters.Skills) == 0 {
+		return true
+	}
+
+	jobSkills := make(map[string]bool, len(job.Skills))
+	for _, skill := range job.Skills {
+		jobSkills[strings.ToLower(skills.Canonical(skill))] = true
+	}
+	for _, requiredSkill := range filters.Skills {
+		if !jobSkills[strings.ToLower(skills.Canonical(requiredSkill))] {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesSalary checks a job against the salary filters, converting its salary to the
+// filters' currency first
+func matchesSalary(job models.Job, filters models.SearchFilters) bool {
+	if filters.MinSalary == 0 && filters.MaxSalary == 0 {
+		return true
+	}
+
+	salaryMin, salaryMax, ok := currency.ConvertRange(job.SalaryMin, job.SalaryMax, job.SalaryCurrency, filters.SalaryCurrency)
+	if !ok {
+		return false
+	}
+	if filters.MinSalary > 0 && (salaryMin == 0 || salaryMin < filters.MinSalary) {
+		return false
+	}
+	if filters.MaxSalary > 0 && (salaryMax == 0 || salaryMax > filters.MaxSalary) {
+		return false
+	}
+	return true
+}
+
 // FetchDocument fetches and parses an HTML document from the given URL
 func (bs *BaseScraper) FetchDocument(ctx context.Context, url string) (*goquery.Document, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
@@ -139,11 +318,14 @@ func (bs *BaseScraper) FetchDocument(ctx context.Context, url string) (*goquery.
 	req.Header.Set("User-Agent", userAgent)
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
 	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-	req.Header.Set("Accept-Encoding", "gzip, deflate")
 	req.Header.Set("Connection", "keep-alive")
 
 	resp, err := bs.client.Do(req)
 	if err != nil {
+		var robotsErr *RobotsError
+		if errors.As(err, &robotsErr) {
+			return nil, robotsErr
+		}
 		return nil, fmt.Errorf("failed to fetch URL %s: %w", url, err)
 	}
 	defer resp.Body.Close()
@@ -175,35 +357,20 @@ func (bs *BaseScraper) CleanText(text string) string {
 	return text
 }
 
-// ExtractSkills extracts skills from job description using common IT skills
-func (bs *BaseScraper) ExtractSkills(description string) []string {
-	commonSkills := []string{
-		// Programming Languages
-		"go", "golang", "python", "javascript", "typescript", "java", "c++", "c#", "rust", "php", "ruby", "swift", "kotlin",
-		// Frameworks
-		"react", "angular", "vue", "node.js", "express", "django", "flask", "spring", "gin", "fiber", "echo",
-		// Databases
-		"mysql", "postgresql", "mongodb", "redis", "elasticsearch", "cassandra", "sqlite",
-		// Cloud & DevOps
-		"aws", "azure", "gcp", "docker", "kubernetes", "jenkins", "gitlab", "github", "terraform", "ansible",
-		// Other Technologies
-		"git", "linux", "unix", "sql", "nosql", "rest", "graphql", "microservices", "api", "json", "xml",
-		// Methodologies
-		"agile", "scrum", "devops", "ci/cd", "tdd", "bdd",
-	}
-
-	description = strings.ToLower(description)
-	var foundSkills []string
-	skillSet := make(map[string]bool)
-
-	for _, skill := range commonSkills {
-		if strings.Contains(description, strings.ToLower(skill)) && !skillSet[skill] {
-			foundSkills = append(foundSkills, skill)
-			skillSet[skill] = true
-		}
+// HTMLToText converts an HTML fragment into clean plain text
+func (bs *BaseScraper) HTMLToText(html string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return bs.CleanText(html)
 	}
+	doc.Find("br, p, li").AppendHtml(" ")
+	return bs.CleanText(doc.Text())
+}
 
-	return foundSkills
+// ExtractSkills finds the skills of the taxonomy mentioned in a job description and
+// returns their canonical names
+func (bs *BaseScraper) ExtractSkills(description string) []string {
+	return skills.Extract(description)
 }
 
 // DetermineExperienceLevel determines experience level from job title and description
@@ -0,0 +1,139 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned for sources skipped because they keep failing
+var ErrCircuitOpen = errors.New("circuit open")
+
+// Circuit breaker defaults
+const (
+	DefaultCircuitFailures = 5           // consecutive failures that open the circuit
+	DefaultCircuitCooldown = time.Minute // how long an open circuit skips the source before a probe
+)
+
+// CircuitState is the state of a source's circuit breaker
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // the source is used normally
+	CircuitOpen     CircuitState = "open"      // the source is skipped until the cooldown ends
+	CircuitHalfOpen CircuitState = "half_open" // one probe search is allowed through
+)
+
+// CircuitStatus reports a source's circuit breaker
+type CircuitStatus struct {
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenUntil           *time.Time   `json:"open_until,omitempty"`
+}
+
+// circuitBreaker tracks consecutive failures of a single source
+type circuitBreaker struct {
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// circuitBreakers holds a breaker per source key
+type circuitBreakers struct {
+	failures int
+	cooldown time.Duration
+	breakers map[string]*circuitBreaker
+	mu       sync.Mutex
+}
+
+func newCircuitBreakers(failures int, cooldown time.Duration) *circuitBreakers {
+	return &circuitBreakers{
+		failures: failures,
+		cooldown: cooldown,
+		breakers: make(map[string]*circuitBreaker),
+	}
+}
+
+// configure changes the failure threshold and cooldown
+func (cb *circuitBreakers) configure(failures int, cooldown time.Duration) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.failures = failures
+	cb.cooldown = cooldown
+}
+
+// allow reports whether a source may be scraped. Once the cooldown of an open circuit
+// has passed, a single caller is let through as a probe.
+func (cb *circuitBreakers) allow(key string) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	breaker := cb.breakers[key]
+	if cb.failures <= 0 || breaker == nil || breaker.failures < cb.failures {
+		return nil
+	}
+
+	if wait := time.Until(breaker.openedAt.Add(cb.cooldown)); wait > 0 {
+		return fmt.Errorf("%w after %d consecutive failures, next attempt in %s",
+			ErrCircuitOpen, breaker.failures, wait.Round(100*time.Millisecond))
+	}
+	if breaker.probing {
+		return fmt.Errorf("%w after %d consecutive failures, probe in progress", ErrCircuitOpen, breaker.failures)
+	}
+
+	breaker.probing = true
+	return nil
+}
+
+// record updates a source's breaker with the outcome of a scrape. Cancelled scrapes
+// say nothing about the source and only release a probe.
+func (cb *circuitBreakers) record(key string, err error, cancelled bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	breaker := cb.breakers[key]
+	if breaker == nil {
+		breaker = &circuitBreaker{}
+		cb.breakers[key] = breaker
+	}
+
+	switch {
+	case cancelled:
+		breaker.probing = false
+	case err == nil:
+		*breaker = circuitBreaker{}
+	default:
+		breaker.failures++
+		breaker.probing = false
+		if cb.failures > 0 && breaker.failures >= cb.failures {
+			breaker.openedAt = time.Now()
+		}
+	}
+}
+
+// status returns a source's breaker state
+func (cb *circuitBreakers) status(key string) CircuitStatus {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	breaker := cb.breakers[key]
+	if breaker == nil {
+		return CircuitStatus{State: CircuitClosed}
+	}
+
+	status := CircuitStatus{State: CircuitClosed, ConsecutiveFailures: breaker.failures}
+	if cb.failures <= 0 || breaker.failures < cb.failures {
+		return status
+	}
+
+	openUntil := breaker.openedAt.Add(cb.cooldown)
+	if breaker.probing || time.Now().After(openUntil) {
+		status.State = CircuitHalfOpen
+	} else {
+		status.State = CircuitOpen
+		status.OpenUntil = &openUntil
+	}
+	return status
+}
@@ -0,0 +1,224 @@
+package scraper
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/salary"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// DetailScraper is implemented by scrapers whose listings only summarize each job.
+// ParseDetail fills in a job from its detail page after the listing has been scraped.
+type DetailScraper interface {
+	JobScraper
+	FetchDocument(ctx context.Context, url string) (*goquery.Document, error)
+	ParseDetail(doc *goquery.Document, job *models.Job)
+}
+
+// detailCacheTTL is how long fetched detail pages are reused across searches
+const detailCacheTTL = time.Hour
+
+// detailCacheSize bounds the number of cached detail pages
+const detailCacheSize = 1000
+
+// detailCache keeps recently fetched detail pages by URL
+type detailCache struct {
+	entries map[string]detailCacheEntry
+	mu      sync.Mutex
+}
+
+type detailCacheEntry struct {
+	doc       *goquery.Document
+	fetchedAt time.Time
+}
+
+func newDetailCache() *detailCache {
+	return &detailCache{entries: make(map[string]detailCacheEntry)}
+}
+
+// get returns a cached page that has not expired
+func (c *detailCache) get(url string) (*goquery.Document, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[url]
+	if !exists || time.Since(entry.fetchedAt) > detailCacheTTL {
+		return nil, false
+	}
+	return entry.doc, true
+}
+
+// put caches a page, dropping expired pages (or the oldest one) when the cache is full
+func (c *detailCache) put(url string, doc *goquery.Document) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= detailCacheSize {
+		oldestURL, oldest := "", time.Now()
+		for key, entry := range c.entries {
+			if time.Since(entry.fetchedAt) > detailCacheTTL {
+				delete(c.entries, key)
+			} else if entry.fetchedAt.Before(oldest) {
+				oldestURL, oldest = key, entry.fetchedAt
+			}
+		}
+		if len(c.entries) >= detailCacheSize {
+			delete(c.entries, oldestURL)
+		}
+	}
+
+	c.entries[url] = detailCacheEntry{doc: doc, fetchedAt: time.Now()}
+}
+
+// enrichDetails fetches the detail page of each job, at most sm.detailConcurrency at a time,
+// and lets the scraper fill in the job from it. Jobs whose page cannot be fetched keep
+// their listing data.
+func (sm *ScraperManager) enrichDetails(ctx context.Context, s DetailScraper, jobs []models.Job) []models.Job {
+	if sm.detailConcurrency <= 0 || len(jobs) == 0 {
+		return jobs
+	}
+
+	enriched := make([]models.Job, len(jobs))
+	copy(enriched, jobs)
+
+	slots := make(chan struct{}, sm.detailConcurrency)
+	var wg sync.WaitGroup
+
+	for i := range enriched {
+		if enriched[i].URL == "" {
+			continue
+		}
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return enriched
+		}
+
+		wg.Add(1)
+		go func(job *models.Job) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			doc, cached := sm.details.get(job.URL)
+			if !cached {
+				var err error
+				doc, err = s.FetchDocument(ctx, job.URL)
+				if err != nil {
+					return
+				}
+				sm.details.put(job.URL, doc)
+			}
+
+			s.ParseDetail(doc, job)
+		}(&enriched[i])
+	}
+
+	wg.Wait()
+	return enriched
+}
+
+// EnrichFromDetail fills in a job from its detail page. schema.org JobPosting data is used
+// when the page has it; otherwise the description is read from descriptionSelector and
+// requirements, benefits and salary are taken from it.
+func (bs *BaseScraper) EnrichFromDetail(doc *goquery.Document, job *models.Job, descriptionSelector string) {
+	if postings := bs.ExtractJobPostings(doc, job.URL); len(postings) > 0 {
+		mergeJobPosting(job, postings[0])
+	} else if description := doc.Find(descriptionSelector).First(); description.Length() > 0 {
+		if html, err := description.Html(); err == nil {
+			job.Description = bs.HTMLToText(html)
+		}
+		if requirements := sectionItems(description, "requirement", "qualification", "you have", "what we"); len(requirements) > 0 {
+			job.Requirements = requirements
+		}
+		if benefits := sectionItems(description, "benefit", "perk", "we offer"); len(benefits) > 0 {
+			job.Benefits = benefits
+		}
+	}
+
+	if job.SalaryMin == 0 && job.SalaryMax == 0 {
+		if pay := salary.Find(job.Description); !pay.IsZero() {
+			job.SalaryMin, job.SalaryMax, job.SalaryCurrency = annualSalary(pay, job.SalaryCurrency)
+		}
+	}
+
+	text := job.Description + " " + strings.Join(job.Requirements, " ")
+	job.Skills = mergeSkills(job.Skills, bs.ExtractSkills(job.Title+" "+text))
+	job.DegreeRequired = bs.CheckDegreeRequirement(text)
+	job.ExperienceLevel = bs.DetermineExperienceLevel(job.Title, text)
+}
+
+// mergeJobPosting copies the fields a JobPosting provides over the listing data
+func mergeJobPosting(job *models.Job, posting models.Job) {
+	if posting.Description != "" {
+		job.Description = posting.Description
+	}
+	if len(posting.Requirements) > 0 {
+		job.Requirements = posting.Requirements
+	}
+	if len(posting.Benefits) > 0 {
+		job.Benefits = posting.Benefits
+	}
+	if posting.SalaryMin > 0 || posting.SalaryMax > 0 {
+		job.SalaryMin, job.SalaryMax, job.SalaryCurrency = posting.SalaryMin, posting.SalaryMax, posting.SalaryCurrency
+	}
+	if job.Company == "" {
+		job.Company = posting.Company
+	}
+	if posting.EmploymentType != "" {
+		job.EmploymentType = posting.EmploymentType
+	}
+	if posting.ValidThrough != nil {
+		job.ValidThrough = posting.ValidThrough
+	}
+	if job.Industry == "" {
+		job.Industry = posting.Industry
+	}
+}
+
+// sectionItems returns the list items that follow a heading containing one of the keywords
+func sectionItems(content *goquery.Selection, keywords ...string) []string {
+	var items []string
+	content.Find("h1, h2, h3, h4, h5, strong, b").EachWithBreak(func(i int, heading *goquery.Selection) bool {
+		text := strings.ToLower(heading.Text())
+		for _, keyword := range keywords {
+			if !strings.Contains(text, keyword) {
+				continue
+			}
+
+			// The list is usually the next sibling of the heading or of its paragraph
+			list := heading.NextAllFiltered("ul, ol").First()
+			if list.Length() == 0 {
+				list = heading.Parent().NextAllFiltered("ul, ol").First()
+			}
+			list.Find("li").Each(func(j int, item *goquery.Selection) {
+				if text := strings.Join(strings.Fields(item.Text()), " "); text != "" {
+					items = append(items, text)
+				}
+			})
+			return len(items) == 0
+		}
+		return true
+	})
+	return items
+}
+
+// mergeSkills appends skills not already present, ignoring case
+func mergeSkills(skills, extra []string) []string {
+	seen := make(map[string]bool, len(skills))
+	for _, skill := range skills {
+		seen[strings.ToLower(skill)] = true
+	}
+	for _, skill := range extra {
+		if !seen[strings.ToLower(skill)] {
+			skills = append(skills, skill)
+			seen[strings.ToLower(skill)] = true
+		}
+	}
+	return skills
+}
@@ -0,0 +1,258 @@
+package scraper
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/salary"
+)
+
+// FeedFieldRule describes where a job field comes from in a feed entry.
+// Each path is an element name relative to the entry, such as "title", "author/name"
+// or "link@href" for an attribute; paths are tried in order until one has a value.
+// When Pattern is set, its first capture group (or the whole match) is used.
+type FeedFieldRule struct {
+	Paths   []string `json:"paths,omitempty"`
+	Pattern string   `json:"pattern,omitempty"`
+}
+
+// FeedMapping maps feed entry fields into job fields. Empty rules use the defaults.
+type FeedMapping struct {
+	ID          FeedFieldRule `json:"id"`
+	Title       FeedFieldRule `json:"title"`
+	Company     FeedFieldRule `json:"company"`
+	Location    FeedFieldRule `json:"location"`
+	Description FeedFieldRule `json:"description"`
+	URL         FeedFieldRule `json:"url"`
+	PostedDate  FeedFieldRule `json:"posted_date"`
+	Salary      FeedFieldRule `json:"salary"`
+}
+
+// DefaultFeedMapping covers the standard RSS 2.0 and Atom elements
+func DefaultFeedMapping() FeedMapping {
+	return FeedMapping{
+		ID:          FeedFieldRule{Paths: []string{"guid", "id", "link", "link@href"}},
+		Title:       FeedFieldRule{Paths: []string{"title"}},
+		Company:     FeedFieldRule{Paths: []string{"creator", "author/name", "author", "source"}},
+		Location:    FeedFieldRule{Paths: []string{"location", "region"}},
+		Description: FeedFieldRule{Paths: []string{"encoded", "content", "description", "summary"}},
+		URL:         FeedFieldRule{Paths: []string{"link", "link@href", "guid"}},
+		PostedDate:  FeedFieldRule{Paths: []string{"pubDate", "published", "updated", "date"}},
+		Salary:      FeedFieldRule{Paths: []string{"salary"}},
+	}
+}
+
+// withDefaults fills empty rules from DefaultFeedMapping
+func (m FeedMapping) withDefaults() FeedMapping {
+	defaults := DefaultFeedMapping()
+	fill := func(rule *FeedFieldRule, fallback FeedFieldRule) {
+		if len(rule.Paths) == 0 {
+			rule.Paths = fallback.Paths
+		}
+	}
+
+	fill(&m.ID, defaults.ID)
+	fill(&m.Title, defaults.Title)
+	fill(&m.Company, defaults.Company)
+	fill(&m.Location, defaults.Location)
+	fill(&m.Description, defaults.Description)
+	fill(&m.URL, defaults.URL)
+	fill(&m.PostedDate, defaults.PostedDate)
+	fill(&m.Salary, defaults.Salary)
+	return m
+}
+
+// FeedScraper scrapes jobs from any RSS or Atom feed
+type FeedScraper struct {
+	*BaseScraper
+	feedURL string
+	mapping FeedMapping
+}
+
+// NewFeedScraper creates a scraper for the feed at feedURL
+func NewFeedScraper(name, feedURL string, client *http.Client, mapping FeedMapping) *FeedScraper {
+	baseURL := feedURL
+	if parsed, err := url.Parse(feedURL); err == nil && parsed.Host != "" {
+		baseURL = parsed.Scheme + "://" + parsed.Host
+	}
+
+	return &FeedScraper{
+		BaseScraper: NewBaseScraper(name, baseURL, client),
+		feedURL:     feedURL,
+		mapping:     mapping.withDefaults(),
+	}
+}
+
+// feedNode is a generic XML element, used so RSS, Atom and extension elements can all be mapped
+type feedNode struct {
+	XMLName xml.Name
+	Attrs   []xml.Attr `xml:",any,attr"`
+	Text    string     `xml:",chardata"`
+	Nodes   []feedNode `xml:",any"`
+}
+
+// Scrape implements the JobScraper interface
+func (f *FeedScraper) Scrape(ctx context.Context, filters models.SearchFilters) ([]models.Job, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", f.feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", f.userAgents[time.Now().Unix()%int64(len(f.userAgents))])
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch feed %s: %w", f.feedURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed %s returned status: %d", f.feedURL, resp.StatusCode)
+	}
+
+	var root feedNode
+	if err := xml.NewDecoder(resp.Body).Decode(&root); err != nil {
+		return nil, fmt.Errorf("failed to decode feed %s: %w", f.feedURL, err)
+	}
+
+	var jobs []models.Job
+	for _, entry := range findEntries(root) {
+		job := f.convertEntry(entry)
+		if job.Title != "" {
+			jobs = append(jobs, job)
+		}
+	}
+
+	// A feed carries only the newest items, so jobs that rolled off it may still be open
+	markPartial(ctx)
+	return jobs, nil
+}
+
+// findEntries returns all RSS <item> and Atom <entry> elements
+func findEntries(node feedNode) []feedNode {
+	if node.XMLName.Local == "item" || node.XMLName.Local == "entry" {
+		return []feedNode{node}
+	}
+
+	var entries []feedNode
+	for _, child := range node.Nodes {
+		entries = append(entries, findEntries(child)...)
+	}
+	return entries
+}
+
+func (f *FeedScraper) convertEntry(entry feedNode) models.Job {
+	title := f.CleanText(f.field(entry, f.mapping.Title))
+	description := f.HTMLToText(f.field(entry, f.mapping.Description))
+	location := f.CleanText(f.field(entry, f.mapping.Location))
+
+	pay := salary.Parse(f.field(entry, f.mapping.Salary))
+	if pay.IsZero() {
+		pay = salary.Find(description)
+	}
+	salaryMin, salaryMax, currency := annualSalary(pay, "USD")
+
+	postedDate := time.Now()
+	if parsed, ok := parseFeedDate(f.field(entry, f.mapping.PostedDate)); ok {
+		postedDate = parsed
+	}
+
+	jobURL := strings.TrimSpace(f.field(entry, f.mapping.URL))
+	return models.Job{
+		ID:              jobID(idPrefix(f.Name()), f.field(entry, f.mapping.ID), jobURL),
+		Title:           title,
+		Company:         f.CleanText(f.field(entry, f.mapping.Company)),
+		Location:        location,
+		Description:     description,
+		Skills:          f.ExtractSkills(title + " " + description),
+		SalaryMin:       salaryMin,
+		SalaryMax:       salaryMax,
+		SalaryCurrency:  currency,
+		DegreeRequired:  f.CheckDegreeRequirement(description),
+		ExperienceLevel: f.DetermineExperienceLevel(title, description),
+		RemoteOption:    detectRemoteOption(location + " " + title + " " + description),
+		PostedDate:      postedDate,
+		URL:             jobURL,
+		Source:          f.Name(),
+	}
+}
+
+// field resolves a mapping rule against an entry
+func (f *FeedScraper) field(entry feedNode, rule FeedFieldRule) string {
+	var value string
+	for _, path := range rule.Paths {
+		if value = strings.TrimSpace(lookupPath(entry, path)); value != "" {
+			break
+		}
+	}
+
+	if rule.Pattern == "" || value == "" {
+		return value
+	}
+
+	re, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		return value
+	}
+	match := re.FindStringSubmatch(value)
+	switch {
+	case len(match) >= 2:
+		return match[1]
+	case len(match) == 1:
+		return match[0]
+	default:
+		return ""
+	}
+}
+
+// lookupPath finds "a/b" element text or "a/b@attr" attribute values below node
+func lookupPath(node feedNode, path string) string {
+	attr := ""
+	if at := strings.LastIndex(path, "@"); at >= 0 {
+		path, attr = path[:at], path[at+1:]
+	}
+
+	current := node
+	for _, name := range strings.Split(path, "/") {
+		found := false
+		for _, child := range current.Nodes {
+			if strings.EqualFold(child.XMLName.Local, name) {
+				current = child
+				found = true
+				break
+			}
+		}
+		if !found {
+			return ""
+		}
+	}
+
+	if attr == "" {
+		return current.Text
+	}
+	for _, a := range current.Attrs {
+		if strings.EqualFold(a.Name.Local, attr) {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// parseFeedDate parses the date formats commonly used by RSS and Atom feeds
+func parseFeedDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	layouts := []string{time.RFC1123Z, time.RFC1123, time.RFC3339, "Mon, 2 Jan 2006 15:04:05 -0700", "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"}
+	for _, layout := range layouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,72 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+)
+
+func TestFeedScraper(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer server.Close()
+
+	// Map the salary and location from the feed's own extension elements
+	mapping := DefaultFeedMapping()
+	mapping.Title = FeedFieldRule{Paths: []string{"title"}, Pattern: `^(.+?) at `}
+	mapping.Location = FeedFieldRule{Paths: []string{"location"}}
+	mapping.Salary = FeedFieldRule{Paths: []string{"salary"}}
+
+	feed := NewFeedScraper("GopherJobs", server.URL+"/jobs.atom", server.Client(), mapping)
+	jobs, err := feed.Scrape(context.Background(), models.SearchFilters{})
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("got %d jobs, want 2", len(jobs))
+	}
+
+	staff, backend := jobs[0], jobs[1]
+	if staff.Title != "Staff Go Engineer" || staff.Company != "Quillstone" || staff.Location != "Remote - Europe" ||
+		staff.RemoteOption != "remote" || staff.SalaryMin != 150000 || staff.SalaryMax != 190000 || staff.SalaryCurrency != "USD" {
+		t.Errorf("got %s at %s [%s, %s] %s %d-%d", staff.Title, staff.Company, staff.Location, staff.RemoteOption,
+			staff.SalaryCurrency, staff.SalaryMin, staff.SalaryMax)
+	}
+	if staff.URL != "https://gopherjobs.example.com/jobs/1042" || staff.PostedDate.Format("2006-01-02") != "2025-09-15" ||
+		strings.Contains(staff.Description, "<") {
+		t.Errorf("got URL %s, posted %s, description %q", staff.URL, staff.PostedDate, staff.Description)
+	}
+
+	// Skills, experience level and degree come from the enrichment every scraper runs
+	if skills := strings.Join(staff.Skills, ","); !strings.Contains(skills, "Kubernetes") || !strings.Contains(skills, "PostgreSQL") {
+		t.Errorf("skills %v, want Kubernetes and PostgreSQL among them", staff.Skills)
+	}
+	if backend.Title != "Backend Developer" || backend.Company != "Ferngrove Health" || backend.RemoteOption != "hybrid" ||
+		!backend.DegreeRequired || backend.PostedDate.Format("2006-01-02") != "2025-09-14" {
+		t.Errorf("got %s at %s [%s], degree %v, posted %s", backend.Title, backend.Company, backend.RemoteOption,
+			backend.DegreeRequired, backend.PostedDate)
+	}
+}
+
+func TestFeedsFromRegistry(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer server.Close()
+
+	registry := NewScraperRegistry()
+	registry.AddCustomScraper("gopherjobs", ScraperConfig{Name: "GopherJobs", Enabled: true, Type: "rss", URL: server.URL + "/jobs.atom"})
+	feed, err := registry.CreateScraper("gopherjobs")
+	if err != nil {
+		t.Fatalf("CreateScraper: %v", err)
+	}
+
+	jobs, err := feed.Scrape(context.Background(), models.SearchFilters{})
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if len(jobs) != 2 || jobs[0].Company != "Quillstone" {
+		t.Errorf("got %d jobs from the registered feed, want the 2 of the feed", len(jobs))
+	}
+}
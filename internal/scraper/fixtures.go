@@ -0,0 +1,216 @@
+package scraper
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ErrNoFixture is returned in replay mode for requests that were never recorded
+var ErrNoFixture = errors.New("no recorded fixture")
+
+// Fixture is a recorded request and the response it got
+type Fixture struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty"` // used instead of Body for binary responses
+	RecordedAt time.Time   `json:"recorded_at"`
+}
+
+// body returns the recorded response body
+func (f Fixture) body() ([]byte, error) {
+	if f.BodyBase64 != "" {
+		return base64.StdEncoding.DecodeString(f.BodyBase64)
+	}
+	return []byte(f.Body), nil
+}
+
+// fixtureKey identifies the request a fixture answers
+func fixtureKey(method, rawURL string) string {
+	return method + " " + rawURL
+}
+
+var fixtureSlug = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// fixtureFileName names a fixture after its URL so directories stay readable, with a
+// hash to keep similar URLs apart
+func fixtureFileName(method, rawURL string) string {
+	slug := rawURL
+	if parsed, err := url.Parse(rawURL); err == nil {
+		slug = parsed.Host + parsed.RequestURI()
+	}
+	slug = strings.Trim(fixtureSlug.ReplaceAllString(slug, "-"), "-")
+	if len(slug) > 80 {
+		slug = slug[:80]
+	}
+
+	sum := sha256.Sum256([]byte(fixtureKey(method, rawURL)))
+	return fmt.Sprintf("%s-%s-%s.json", strings.ToLower(method), slug, hex.EncodeToString(sum[:4]))
+}
+
+// NewRecordingTransport returns a RoundTripper that sends requests to base and saves each
+// request and response to dir as a Fixture
+func NewRecordingTransport(dir string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &recordingTransport{dir: dir, base: base}
+}
+
+// recordingTransport saves every exchange it makes as a fixture
+type recordingTransport struct {
+	dir  string
+	base http.RoundTripper
+	mu   sync.Mutex
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Cookies are specific to the recording session
+	header := resp.Header.Clone()
+	header.Del("Set-Cookie")
+
+	fixture := Fixture{
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     header,
+		RecordedAt: time.Now().UTC(),
+	}
+	if utf8.Valid(body) {
+		fixture.Body = string(body)
+	} else {
+		fixture.BodyBase64 = base64.StdEncoding.EncodeToString(body)
+	}
+	if err := t.save(fixture); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// save writes a fixture as indented JSON so recordings diff well
+func (t *recordingTransport) save(fixture Fixture) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create fixtures directory: %w", err)
+	}
+
+	// Keep markup readable rather than escaping < and >
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(fixture); err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+
+	path := filepath.Join(t.dir, fixtureFileName(fixture.Method, fixture.URL))
+	if err := os.WriteFile(path, data.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture: %w", err)
+	}
+	return nil
+}
+
+// NewReplayTransport returns a RoundTripper that answers requests from the fixtures
+// recorded under dir, without using the network. Requests that were not recorded fail
+// with ErrNoFixture.
+func NewReplayTransport(dir string) (http.RoundTripper, error) {
+	fixtures := make(map[string]Fixture)
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var fixture Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return fmt.Errorf("failed to parse fixture %s: %w", path, err)
+		}
+		fixtures[fixtureKey(fixture.Method, fixture.URL)] = fixture
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fixtures: %w", err)
+	}
+
+	return &replayTransport{fixtures: fixtures}, nil
+}
+
+// replayTransport serves recorded fixtures
+type replayTransport struct {
+	fixtures map[string]Fixture
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+
+	fixture, exists := t.fixtures[fixtureKey(req.Method, req.URL.String())]
+	if !exists {
+		return nil, fmt.Errorf("%w for %s %s", ErrNoFixture, req.Method, req.URL)
+	}
+
+	body, err := fixture.body()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode fixture for %s %s: %w", req.Method, req.URL, err)
+	}
+
+	header := fixture.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.StatusCode, http.StatusText(fixture.StatusCode)),
+		StatusCode:    fixture.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,399 @@
+package scraper
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HTTP cache defaults
+const (
+	DefaultHTTPCacheTTL      = 5 * time.Minute // responses younger than this are used without a request
+	DefaultHTTPCacheMaxBytes = 64 << 20        // total size of the cache on disk
+)
+
+// HTTPCache stores successful GET responses on disk, keyed by URL and the credentials the
+// request carried, so clients signed in as different users never share a response. A
+// response is only reused for requests matching it on the headers it names in Vary.
+// Fresh responses are served without a request; stale ones are revalidated with
+// If-None-Match and If-Modified-Since, and a 304 reuses the stored body. The least
+// recently used entries are evicted once the cache grows past its size cap.
+type HTTPCache struct {
+	dir      string
+	ttl      time.Duration
+	maxBytes int64
+	entries  map[string]*httpCacheEntry
+	size     int64
+	stats    HTTPCacheStats
+	mu       sync.Mutex
+}
+
+// httpCacheEntry tracks a stored response for eviction
+type httpCacheEntry struct {
+	size int64
+	used time.Time
+}
+
+// cachedResponse is the stored form of a response
+type cachedResponse struct {
+	URL        string      `json:"url"`
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Vary       http.Header `json:"vary,omitempty"` // request headers the response varies on
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"stored_at"`
+}
+
+// HTTPCacheStats reports how well the cache is doing. Revalidated responses count as hits.
+type HTTPCacheStats struct {
+	Hits        int64   `json:"hits"`
+	Revalidated int64   `json:"revalidated"`
+	Misses      int64   `json:"misses"`
+	Entries     int     `json:"entries"`
+	Bytes       int64   `json:"bytes"`
+	MaxBytes    int64   `json:"max_bytes"`
+	TTLSeconds  float64 `json:"ttl_seconds"`
+}
+
+// NewHTTPCache opens the cache stored in dir, creating the directory when needed. A zero
+// ttl revalidates every response.
+func NewHTTPCache(dir string, ttl time.Duration, maxBytes int64) (*HTTPCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create HTTP cache directory: %w", err)
+	}
+
+	cache := &HTTPCache{
+		dir:      dir,
+		ttl:      ttl,
+		maxBytes: maxBytes,
+		entries:  make(map[string]*httpCacheEntry),
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read HTTP cache directory: %w", err)
+	}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		key := strings.TrimSuffix(file.Name(), ".json")
+		cache.entries[key] = &httpCacheEntry{size: info.Size(), used: info.ModTime()}
+		cache.size += info.Size()
+	}
+
+	cache.mu.Lock()
+	cache.evictLocked()
+	cache.mu.Unlock()
+
+	return cache, nil
+}
+
+// Transport returns a RoundTripper that answers from the cache and sends the rest to base
+func (c *HTTPCache) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &cachingTransport{base: base, cache: c}
+}
+
+// Stats returns the hit and miss counters and the current size
+func (c *HTTPCache) Stats() HTTPCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Entries = len(c.entries)
+	stats.Bytes = c.size
+	stats.MaxBytes = c.maxBytes
+	stats.TTLSeconds = c.ttl.Seconds()
+	return stats
+}
+
+// credentialHeaders identify who a request is made for
+var credentialHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization"}
+
+// cacheKey names the file of a request's response from its URL and credentials
+func cacheKey(req *http.Request) string {
+	key := req.URL.String()
+	for _, name := range credentialHeaders {
+		for _, value := range req.Header.Values(name) {
+			key += "\n" + name + ": " + value
+		}
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// varyHeaders returns the request headers a response names in Vary, with their values in
+// req; ok is false for "Vary: *", which no later request can be shown to match
+func varyHeaders(resp *http.Response, req *http.Request) (vary http.Header, ok bool) {
+	for _, field := range resp.Header.Values("Vary") {
+		for _, name := range strings.Split(field, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			switch name {
+			case "":
+				continue
+			case "*":
+				return nil, false
+			}
+			if vary == nil {
+				vary = make(http.Header)
+			}
+			vary[name] = req.Header.Values(name)
+		}
+	}
+	return vary, true
+}
+
+// matches reports whether req sends the same values as the stored request for every
+// header the response varies on
+func (r *cachedResponse) matches(req *http.Request) bool {
+	for name, values := range r.Vary {
+		if strings.Join(req.Header.Values(name), ",") != strings.Join(values, ",") {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *HTTPCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// load reads a stored response, forgetting entries that cannot be read
+func (c *HTTPCache) load(key string) *cachedResponse {
+	c.mu.Lock()
+	_, exists := c.entries[key]
+	c.mu.Unlock()
+	if !exists {
+		return nil
+	}
+
+	var cached cachedResponse
+	data, err := os.ReadFile(c.path(key))
+	if err == nil {
+		err = json.Unmarshal(data, &cached)
+	}
+	if err != nil {
+		c.mu.Lock()
+		c.removeLocked(key)
+		c.mu.Unlock()
+		return nil
+	}
+	return &cached
+}
+
+// store writes a response atomically and evicts old entries when over the size cap
+func (c *HTTPCache) store(key string, cached *cachedResponse) {
+	data, err := json.Marshal(cached)
+	if err != nil || int64(len(data)) > c.maxBytes {
+		return
+	}
+
+	tmp := c.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, c.path(key)); err != nil {
+		os.Remove(tmp)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if previous, exists := c.entries[key]; exists {
+		c.size -= previous.size
+	}
+	c.entries[key] = &httpCacheEntry{size: int64(len(data)), used: time.Now()}
+	c.size += int64(len(data))
+	c.evictLocked()
+}
+
+// touch marks an entry as recently used
+func (c *HTTPCache) touch(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, exists := c.entries[key]; exists {
+		entry.used = time.Now()
+		os.Chtimes(c.path(key), entry.used, entry.used)
+	}
+}
+
+// evictLocked removes the least recently used entries until the cache fits. c.mu must be held.
+func (c *HTTPCache) evictLocked() {
+	if c.size <= c.maxBytes {
+		return
+	}
+
+	keys := make([]string, 0, len(c.entries))
+	for key := range c.entries {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return c.entries[keys[i]].used.Before(c.entries[keys[j]].used)
+	})
+
+	for _, key := range keys {
+		if c.size <= c.maxBytes {
+			break
+		}
+		c.removeLocked(key)
+	}
+}
+
+// removeLocked deletes an entry and its file. c.mu must be held.
+func (c *HTTPCache) removeLocked(key string) {
+	if entry, exists := c.entries[key]; exists {
+		c.size -= entry.size
+		delete(c.entries, key)
+	}
+	os.Remove(c.path(key))
+}
+
+// count records a lookup outcome
+func (c *HTTPCache) count(hit, revalidated bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case revalidated:
+		c.stats.Hits++
+		c.stats.Revalidated++
+	case hit:
+		c.stats.Hits++
+	default:
+		c.stats.Misses++
+	}
+}
+
+// response rebuilds an http.Response for req from a stored response
+func (r *cachedResponse) response(req *http.Request, status string) *http.Response {
+	header := r.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set("X-Cache", status)
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// cachingTransport answers GET requests from an HTTPCache
+type cachingTransport struct {
+	base  http.RoundTripper
+	cache *HTTPCache
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Leave requests the caller made conditional or partial alone
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" ||
+		req.Header.Get("If-None-Match") != "" || req.Header.Get("If-Modified-Since") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	key := cacheKey(req)
+	cached := t.cache.load(key)
+	if cached != nil && !cached.matches(req) {
+		cached = nil
+	}
+	if cached != nil && time.Since(cached.StoredAt) < t.cache.ttl {
+		t.cache.touch(key)
+		t.cache.count(true, false)
+		return cached.response(req, "HIT"), nil
+	}
+
+	outReq := req
+	if cached != nil {
+		etag, modified := cached.Header.Get("ETag"), cached.Header.Get("Last-Modified")
+		if etag != "" || modified != "" {
+			outReq = req.Clone(req.Context())
+			if etag != "" {
+				outReq.Header.Set("If-None-Match", etag)
+			}
+			if modified != "" {
+				outReq.Header.Set("If-Modified-Since", modified)
+			}
+		}
+	}
+
+	resp, err := t.base.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		// The 304 may carry updated validators
+		for _, name := range []string{"ETag", "Last-Modified", "Cache-Control", "Expires", "Date"} {
+			if value := resp.Header.Get(name); value != "" {
+				cached.Header.Set(name, value)
+			}
+		}
+		cached.StoredAt = time.Now()
+		t.cache.store(key, cached)
+		t.cache.count(true, true)
+		return cached.response(req, "REVALIDATED"), nil
+	}
+
+	t.cache.count(false, false)
+	vary, cacheable := varyHeaders(resp, req)
+	if !cacheable || resp.StatusCode != http.StatusOK || strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		return resp, nil
+	}
+
+	resp.Header.Set("X-Cache", "MISS")
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.cache.maxBytes+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > t.cache.maxBytes {
+		// Too large to cache; hand back what was read followed by the rest of the stream
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.cache.store(key, &cachedResponse{
+		URL:        req.URL.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+		Vary:       vary,
+		Body:       body,
+		StoredAt:   time.Now(),
+	})
+	return resp, nil
+}
@@ -0,0 +1,111 @@
+package scraper
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newCountingServer answers every request with the user it was made for and counts them
+func newCountingServer(t *testing.T, vary string) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if vary != "" {
+			w.Header().Set("Vary", vary)
+		}
+		io.WriteString(w, "user="+r.Header.Get("Authorization")+" lang="+r.Header.Get("Accept-Language"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// cachedGet sends a GET with the given headers through the cache and returns the body
+func cachedGet(t *testing.T, client *http.Client, url string, header map[string]string) string {
+	t.Helper()
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	for name, value := range header {
+		req.Header.Set(name, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("GET %s: failed to read body: %v", url, err)
+	}
+	return string(body)
+}
+
+func newTestCache(t *testing.T) *http.Client {
+	t.Helper()
+
+	cache, err := NewHTTPCache(t.TempDir(), time.Minute, DefaultHTTPCacheMaxBytes)
+	if err != nil {
+		t.Fatalf("NewHTTPCache: %v", err)
+	}
+	return &http.Client{Transport: cache.Transport(nil)}
+}
+
+func TestHTTPCacheSeparatesCredentials(t *testing.T) {
+	server, requests := newCountingServer(t, "")
+	client := newTestCache(t)
+
+	alice := map[string]string{"Authorization": "Bearer alice"}
+	bob := map[string]string{"Authorization": "Bearer bob"}
+
+	if body := cachedGet(t, client, server.URL, alice); body != "user=Bearer alice lang=" {
+		t.Fatalf("alice: got %q", body)
+	}
+	if body := cachedGet(t, client, server.URL, bob); body != "user=Bearer bob lang=" {
+		t.Errorf("bob got %q; a response cached for another user was reused", body)
+	}
+	if body := cachedGet(t, client, server.URL, alice); body != "user=Bearer alice lang=" {
+		t.Errorf("alice again: got %q", body)
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("%d server requests, want one per user", got)
+	}
+}
+
+func TestHTTPCacheHonoursVary(t *testing.T) {
+	server, requests := newCountingServer(t, "Accept-Language")
+	client := newTestCache(t)
+
+	english := map[string]string{"Accept-Language": "en"}
+	german := map[string]string{"Accept-Language": "de"}
+
+	cachedGet(t, client, server.URL, english)
+	if body := cachedGet(t, client, server.URL, german); body != "user= lang=de" {
+		t.Errorf("de got %q; a response varying on Accept-Language was reused", body)
+	}
+	if body := cachedGet(t, client, server.URL, german); body != "user= lang=de" {
+		t.Errorf("de again: got %q", body)
+	}
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("%d server requests, want the repeated de request answered from cache", got)
+	}
+}
+
+func TestHTTPCacheSkipsVaryStar(t *testing.T) {
+	server, requests := newCountingServer(t, "*")
+	client := newTestCache(t)
+
+	cachedGet(t, client, server.URL, nil)
+	cachedGet(t, client, server.URL, nil)
+	if got := atomic.LoadInt32(requests); got != 2 {
+		t.Errorf("%d server requests, want every Vary: * response fetched again", got)
+	}
+}
@@ -0,0 +1,83 @@
+package scraper
+
+import (
+	"net/url"
+	"path"
+	"strings"
+)
+
+// trackingParams are query parameters that only record how a visitor reached a page
+var trackingParams = map[string]bool{
+	"ref": true, "referrer": true, "source": true, "src": true, "from": true,
+	"fbclid": true, "gclid": true, "msclkid": true, "mc_cid": true, "mc_eid": true,
+	"trk": true, "trackingid": true, "refid": true, "sessionid": true,
+}
+
+// CanonicalURL normalizes a job URL so that every link to the same page gives the same
+// string: the scheme and host are lower-cased, and default ports, fragments, tracking
+// parameters and trailing slashes are removed. The remaining parameters are sorted.
+func CanonicalURL(raw string) string {
+	raw = strings.TrimSpace(raw)
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return raw
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	parsed.Host = strings.ToLower(parsed.Host)
+	if port := parsed.Port(); (port == "80" && parsed.Scheme == "http") || (port == "443" && parsed.Scheme == "https") {
+		parsed.Host = parsed.Hostname()
+	}
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+	if parsed.Path != "/" {
+		parsed.Path = strings.TrimSuffix(parsed.Path, "/")
+		parsed.RawPath = strings.TrimSuffix(parsed.RawPath, "/")
+	}
+
+	query := parsed.Query()
+	for key := range query {
+		if trackingParams[strings.ToLower(key)] || strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
+
+// idPrefix is the start of the IDs of a source's jobs: its name in lower case with
+// hyphens for spaces
+func idPrefix(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "-"))
+}
+
+// jobID returns a stable ID for a job: the source's own identifier when it has one, and
+// otherwise a hash of the canonical URL. The same posting keeps its ID between scrapes.
+// Identifiers that are URLs or URNs, as Atom entry IDs usually are, are hashed so the ID
+// fits in a URL path segment.
+func jobID(prefix, nativeID, jobURL string) string {
+	nativeID = strings.TrimSpace(nativeID)
+	switch {
+	case nativeID == "":
+		return prefix + "-" + shortHash(CanonicalURL(jobURL))
+	case strings.ContainsAny(nativeID, "/:"):
+		return prefix + "-" + shortHash(CanonicalURL(nativeID))
+	default:
+		return prefix + "-" + nativeID
+	}
+}
+
+// lastPathSegment returns the last segment of a URL's path, which boards such as
+// WeWorkRemotely and JobStreet use as the job's slug or number
+func lastPathSegment(jobURL string) string {
+	parsed, err := url.Parse(strings.TrimSpace(jobURL))
+	if err != nil {
+		return ""
+	}
+	segment := path.Base(strings.TrimSuffix(parsed.Path, "/"))
+	if segment == "." || segment == "/" {
+		return ""
+	}
+	return segment
+}
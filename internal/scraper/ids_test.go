@@ -0,0 +1,70 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+)
+
+func TestJobID(t *testing.T) {
+	tests := []struct {
+		name     string
+		nativeID string
+		url      string
+		want     string
+	}{
+		{"native ID", "1093221", "https://remoteok.com/remote-jobs/1093221", "remoteok-1093221"},
+		{"no native ID", "", "https://example.com/jobs/7?utm_source=feed", "remoteok-" + shortHash("https://example.com/jobs/7")},
+		{"URL as ID", "https://example.com/jobs/7/", "", "remoteok-" + shortHash("https://example.com/jobs/7")},
+		{"URN as ID", "urn:uuid:6c2f5e0a", "", "remoteok-" + shortHash("urn:uuid:6c2f5e0a")},
+	}
+	for _, test := range tests {
+		if got := jobID("remoteok", test.nativeID, test.url); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFeedIDsFitInAPath(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer server.Close()
+
+	// Atom entry IDs are URLs
+	feed := NewFeedScraper("Gopher Jobs", server.URL+"/jobs.atom", server.Client(), DefaultFeedMapping())
+	jobs, err := feed.Scrape(context.Background(), models.SearchFilters{})
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if len(jobs) == 0 {
+		t.Fatal("no jobs in the feed")
+	}
+	for _, job := range jobs {
+		if strings.ContainsAny(job.ID, "/:") || !strings.HasPrefix(job.ID, "gopher-jobs-") {
+			t.Errorf("ID %q of %s cannot be used in /jobs/{id}", job.ID, job.Title)
+		}
+	}
+}
+
+func TestMockIDsFollowContent(t *testing.T) {
+	jobs, err := NewMockJobScraper("MockJobSite").Scrape(context.Background(), models.SearchFilters{})
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+
+	// Templates number their postings from zero, so only the content tells them apart
+	byID := make(map[string]models.Job)
+	for _, job := range jobs {
+		if want := jobID("mockjobsite", "", job.URL); job.ID != want {
+			t.Errorf("ID %q of %s, want %q from its URL", job.ID, job.URL, want)
+		}
+		if other, exists := byID[job.ID]; exists && (other.Title != job.Title || other.Company != job.Company ||
+			other.Location != job.Location || other.ExperienceLevel != job.ExperienceLevel) {
+			t.Errorf("%s and %s at %s share ID %q", other.Title, job.Title, job.Company, job.ID)
+		}
+		byID[job.ID] = job
+	}
+}
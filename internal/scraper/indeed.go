@@ -0,0 +1,226 @@
+package scraper
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/salary"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// IndeedScraper scrapes jobs from Indeed's public RSS feeds
+type IndeedScraper struct {
+	*BaseScraper
+}
+
+// NewIndeedScraper creates a new Indeed RSS scraper
+func NewIndeedScraper(client *http.Client) *IndeedScraper {
+	return &IndeedScraper{
+		BaseScraper: NewBaseScraper("Indeed", "https://rss.indeed.com", client),
+	}
+}
+
+// indeedFeed represents an Indeed RSS document
+type indeedFeed struct {
+	Channel struct {
+		Items []indeedItem `xml:"item"`
+	} `xml:"channel"`
+}
+
+// indeedItem represents a single job in an Indeed RSS feed
+type indeedItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Source      string `xml:"source"`
+	GUID        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+	Description string `xml:"description"`
+}
+
+// Scrape implements the JobScraper interface
+func (i *IndeedScraper) Scrape(ctx context.Context, filters models.SearchFilters) ([]models.Job, error) {
+	feedURL := i.buildFeedURL(filters)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", i.userAgents[time.Now().Unix()%int64(len(i.userAgents))])
+	req.Header.Set("Accept", "application/rss+xml, application/xml;q=0.9")
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Indeed feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Indeed feed returned status: %d", resp.StatusCode)
+	}
+
+	var feed indeedFeed
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("failed to decode Indeed feed: %w", err)
+	}
+
+	var jobs []models.Job
+	for _, item := range feed.Channel.Items {
+		job := i.convertIndeedItem(item)
+		if job.Title != "" {
+			jobs = append(jobs, job)
+		}
+	}
+
+	// The feed carries only the newest results, so jobs missing from it may still be open
+	markPartial(ctx)
+	return jobs, nil
+}
+
+// buildFeedURL builds the RSS URL for the query, location, radius and recency filters
+func (i *IndeedScraper) buildFeedURL(filters models.SearchFilters) string {
+	params := url.Values{}
+
+	query := filters.JobTitle
+	if len(filters.Keywords) > 0 {
+		query = strings.TrimSpace(query + " " + strings.Join(filters.Keywords, " "))
+	}
+	params.Set("q", query)
+
+	location := filters.Location
+	if location == "" && len(filters.Locations) > 0 {
+		location = filters.Locations[0]
+	}
+	if location == "" && filters.RemoteOnly {
+		location = "Remote"
+	}
+	if location != "" {
+		params.Set("l", location)
+	}
+
+	if filters.Radius > 0 {
+		params.Set("radius", strconv.Itoa(filters.Radius))
+	}
+
+	if filters.PostedWithinDays > 0 {
+		params.Set("fromage", strconv.Itoa(filters.PostedWithinDays))
+	}
+
+	params.Set("sort", "date")
+
+	return fmt.Sprintf("%s/rss?%s", i.baseURL, params.Encode())
+}
+
+func (i *IndeedScraper) convertIndeedItem(item indeedItem) models.Job {
+	// Titles look like "Golang Developer - Acme Corp - Austin, TX"
+	title, titleCompany, location := i.splitTitle(item.Title)
+
+	description := i.descriptionText(item.Description)
+
+	company := i.CleanText(item.Source)
+	if company == "" {
+		company = titleCompany
+	}
+	if company == "" {
+		company = i.extractCompany(description)
+	}
+
+	salaryMin, salaryMax, currency := annualSalary(salary.Find(description), "USD")
+
+	postedDate := time.Now()
+	if parsed, err := time.Parse(time.RFC1123Z, strings.TrimSpace(item.PubDate)); err == nil {
+		postedDate = parsed
+	} else if parsed, err := time.Parse(time.RFC1123, strings.TrimSpace(item.PubDate)); err == nil {
+		postedDate = parsed
+	}
+
+	return models.Job{
+		ID:              jobID("indeed", i.jobKey(item), item.Link),
+		Title:           title,
+		Company:         company,
+		Location:        location,
+		Description:     description,
+		Skills:          i.ExtractSkills(title + " " + description),
+		SalaryMin:       salaryMin,
+		SalaryMax:       salaryMax,
+		SalaryCurrency:  currency,
+		DegreeRequired:  i.CheckDegreeRequirement(description),
+		ExperienceLevel: i.DetermineExperienceLevel(title, description),
+		RemoteOption:    i.remoteOption(location + " " + title + " " + description),
+		PostedDate:      postedDate,
+		URL:             strings.TrimSpace(item.Link),
+		Source:          i.Name(),
+	}
+}
+
+// splitTitle splits an Indeed item title into job title, company and location
+func (i *IndeedScraper) splitTitle(raw string) (string, string, string) {
+	parts := strings.Split(i.CleanText(raw), " - ")
+	switch {
+	case len(parts) >= 3:
+		location := parts[len(parts)-1]
+		company := parts[len(parts)-2]
+		title := strings.Join(parts[:len(parts)-2], " - ")
+		return title, company, location
+	case len(parts) == 2:
+		// Postings without a company name are "Title - Location"
+		return parts[0], "", parts[1]
+	default:
+		return parts[0], "", ""
+	}
+}
+
+// descriptionText converts the HTML item description into plain text
+func (i *IndeedScraper) descriptionText(html string) string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return i.CleanText(html)
+	}
+
+	// Keep line breaks as separators so "Company:" and "Salary:" lines stay apart
+	doc.Find("br").ReplaceWithHtml(" | ")
+	return i.CleanText(doc.Text())
+}
+
+// extractCompany finds a "Company: X" mention in the description
+func (i *IndeedScraper) extractCompany(description string) string {
+	re := regexp.MustCompile(`(?i)company:\s*([^|]+)`)
+	if match := re.FindStringSubmatch(description); len(match) >= 2 {
+		return strings.TrimSpace(match[1])
+	}
+	return ""
+}
+
+// jobKey returns Indeed's job key from the link, falling back to the GUID
+func (i *IndeedScraper) jobKey(item indeedItem) string {
+	if parsed, err := url.Parse(strings.TrimSpace(item.Link)); err == nil {
+		if jk := parsed.Query().Get("jk"); jk != "" {
+			return jk
+		}
+	}
+	return strings.TrimSpace(item.GUID)
+}
+
+func (i *IndeedScraper) remoteOption(text string) string {
+	return detectRemoteOption(text)
+}
+
+// detectRemoteOption guesses the remote option from free text
+func detectRemoteOption(text string) string {
+	textLower := strings.ToLower(text)
+	switch {
+	case strings.Contains(textLower, "remote"):
+		return "remote"
+	case strings.Contains(textLower, "hybrid"):
+		return "hybrid"
+	default:
+		return "onsite"
+	}
+}
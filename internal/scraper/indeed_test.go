@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+)
+
+func TestIndeedFeedURL(t *testing.T) {
+	indeed := NewIndeedScraper(nil)
+	tests := []struct {
+		name    string
+		filters models.SearchFilters
+		want    url.Values
+	}{
+		{"title and keywords", models.SearchFilters{JobTitle: "golang", Keywords: []string{"backend", "api"}},
+			url.Values{"q": {"golang backend api"}, "sort": {"date"}}},
+		{"location, radius and recency", models.SearchFilters{JobTitle: "go", Location: "Austin, TX", Radius: 25, PostedWithinDays: 7},
+			url.Values{"q": {"go"}, "l": {"Austin, TX"}, "radius": {"25"}, "fromage": {"7"}, "sort": {"date"}}},
+		{"first of several locations", models.SearchFilters{Locations: []string{"Denver", "Boston"}},
+			url.Values{"q": {""}, "l": {"Denver"}, "sort": {"date"}}},
+		{"remote only", models.SearchFilters{RemoteOnly: true},
+			url.Values{"q": {""}, "l": {"Remote"}, "sort": {"date"}}},
+	}
+	for _, test := range tests {
+		parsed, err := url.Parse(indeed.buildFeedURL(test.filters))
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if parsed.Path != "/rss" || parsed.Query().Encode() != test.want.Encode() {
+			t.Errorf("%s: got %s, want /rss?%s", test.name, parsed, test.want.Encode())
+		}
+	}
+}
+
+func TestIndeedScraper(t *testing.T) {
+	var requested url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Query()
+		http.ServeFile(w, r, "testdata/indeed.rss")
+	}))
+	defer server.Close()
+
+	indeed := NewIndeedScraper(server.Client())
+	indeed.baseURL = server.URL
+	jobs, err := indeed.Scrape(context.Background(), models.SearchFilters{JobTitle: "golang developer", Location: "Remote"})
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if requested.Get("q") != "golang developer" || requested.Get("l") != "Remote" {
+		t.Errorf("requested %v, want the search's query and location", requested)
+	}
+
+	want := []struct {
+		title, company, location, remote string
+		salaryMin, salaryMax             int
+	}{
+		{"Senior Golang Developer", "Northwind Cloud", "Remote", "remote", 140000, 175000},
+		{"Backend Engineer (Go)", "Lumen Payments", "Austin, TX", "hybrid", 120000, 150000},
+		{"Junior Go Developer", "Brightline Labs", "Remote", "remote", 45 * 2080, 55 * 2080},
+		{"Platform Engineer", "Kestrel Data", "Denver, CO", "onsite", 0, 0},
+	}
+	if len(jobs) != len(want) {
+		t.Fatalf("got %d jobs, want %d", len(jobs), len(want))
+	}
+	for i, job := range jobs {
+		w := want[i]
+		if job.Title != w.title || job.Company != w.company || job.Location != w.location || job.RemoteOption != w.remote ||
+			job.SalaryMin != w.salaryMin || job.SalaryMax != w.salaryMax {
+			t.Errorf("job %d: got %s at %s [%s, %s] %d-%d, want %s at %s [%s, %s] %d-%d", i,
+				job.Title, job.Company, job.Location, job.RemoteOption, job.SalaryMin, job.SalaryMax,
+				w.title, w.company, w.location, w.remote, w.salaryMin, w.salaryMax)
+		}
+		if job.Source != "Indeed" || job.ID == "" || job.URL == "" || job.PostedDate.Year() != 2025 {
+			t.Errorf("job %d: source %q, ID %q, URL %q, posted %s", i, job.Source, job.ID, job.URL, job.PostedDate)
+		}
+	}
+	if len(jobs) > 1 && !jobs[1].DegreeRequired {
+		t.Errorf("%s requires a degree", jobs[1].Title)
+	}
+}
@@ -0,0 +1,517 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/salary"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// FetchJobPostings fetches a page and extracts the schema.org JobPostings embedded in it
+func (bs *BaseScraper) FetchJobPostings(ctx context.Context, pageURL string) ([]models.Job, error) {
+	doc, err := bs.FetchDocument(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	return bs.ExtractJobPostings(doc, pageURL), nil
+}
+
+// ExtractJobPostings reads schema.org JobPosting data from a page, preferring JSON-LD
+// and falling back to microdata. pageURL is used to resolve relative links and as the
+// job URL when the posting does not name one.
+func (bs *BaseScraper) ExtractJobPostings(doc *goquery.Document, pageURL string) []models.Job {
+	postings := jsonLDPostings(doc)
+	if len(postings) == 0 {
+		postings = microdataPostings(doc)
+	}
+
+	var jobs []models.Job
+	for _, posting := range postings {
+		job := bs.convertJobPosting(posting, pageURL)
+		if job.Title != "" {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// jsonLDPostings finds JobPosting objects in all ld+json scripts, including @graph and list wrappers
+func jsonLDPostings(doc *goquery.Document) []map[string]interface{} {
+	var postings []map[string]interface{}
+	doc.Find(`script[type="application/ld+json"]`).Each(func(i int, script *goquery.Selection) {
+		var data interface{}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(script.Text())), &data); err != nil {
+			return
+		}
+		postings = append(postings, findJobPostings(data)...)
+	})
+	return postings
+}
+
+// findJobPostings walks decoded JSON-LD looking for objects typed JobPosting
+func findJobPostings(data interface{}) []map[string]interface{} {
+	switch value := data.(type) {
+	case []interface{}:
+		var postings []map[string]interface{}
+		for _, item := range value {
+			postings = append(postings, findJobPostings(item)...)
+		}
+		return postings
+	case map[string]interface{}:
+		if hasSchemaType(value, "JobPosting") {
+			return []map[string]interface{}{value}
+		}
+		var postings []map[string]interface{}
+		for _, key := range []string{"@graph", "itemListElement", "item", "mainEntity"} {
+			if nested, ok := value[key]; ok {
+				postings = append(postings, findJobPostings(nested)...)
+			}
+		}
+		return postings
+	default:
+		return nil
+	}
+}
+
+// hasSchemaType reports whether a JSON-LD object has the given @type
+func hasSchemaType(object map[string]interface{}, schemaType string) bool {
+	for _, t := range ldStrings(object["@type"]) {
+		if t == schemaType || strings.HasSuffix(t, "/"+schemaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// microdataPostings converts top-level JobPosting microdata items into JSON-LD shaped objects
+func microdataPostings(doc *goquery.Document) []map[string]interface{} {
+	var postings []map[string]interface{}
+	doc.Find(`[itemscope][itemtype*="JobPosting"]`).Each(func(i int, item *goquery.Selection) {
+		if item.ParentsFiltered(`[itemscope][itemtype*="JobPosting"]`).Length() == 0 {
+			postings = append(postings, microdataItem(item))
+		}
+	})
+	return postings
+}
+
+// microdataItem reads the properties that belong directly to an itemscope element
+func microdataItem(item *goquery.Selection) map[string]interface{} {
+	object := make(map[string]interface{})
+	if itemType, ok := item.Attr("itemtype"); ok {
+		object["@type"] = itemType[strings.LastIndex(itemType, "/")+1:]
+	}
+
+	root := item.Get(0)
+	item.Find("[itemprop]").Each(func(i int, prop *goquery.Selection) {
+		// Skip properties of nested items; they are read with their own item
+		if owner := prop.Parent().Closest("[itemscope]"); owner.Length() == 0 || owner.Get(0) != root {
+			return
+		}
+
+		var value interface{}
+		if _, nested := prop.Attr("itemscope"); nested {
+			value = microdataItem(prop)
+		} else {
+			value = microdataValue(prop)
+		}
+
+		for _, name := range strings.Fields(prop.AttrOr("itemprop", "")) {
+			switch existing := object[name].(type) {
+			case nil:
+				object[name] = value
+			case []interface{}:
+				object[name] = append(existing, value)
+			default:
+				object[name] = []interface{}{existing, value}
+			}
+		}
+	})
+
+	return object
+}
+
+// microdataValue returns the value of a non-item property following the microdata rules
+func microdataValue(prop *goquery.Selection) string {
+	if content, ok := prop.Attr("content"); ok {
+		return strings.TrimSpace(content)
+	}
+
+	attr := ""
+	switch goquery.NodeName(prop) {
+	case "a", "area", "link":
+		attr = "href"
+	case "img", "audio", "video", "source", "embed", "iframe":
+		attr = "src"
+	case "time":
+		attr = "datetime"
+	case "data", "meter":
+		attr = "value"
+	}
+	if value, ok := prop.Attr(attr); ok && attr != "" {
+		return strings.TrimSpace(value)
+	}
+
+	// Keep the markup of descriptions so it is converted like JSON-LD HTML
+	if prop.Children().Length() > 0 {
+		if inner, err := prop.Html(); err == nil {
+			return inner
+		}
+	}
+	return strings.TrimSpace(prop.Text())
+}
+
+// convertJobPosting maps a JobPosting object onto a job
+func (bs *BaseScraper) convertJobPosting(posting map[string]interface{}, pageURL string) models.Job {
+	title := bs.CleanText(ldString(posting["title"]))
+	if title == "" {
+		title = bs.CleanText(ldString(posting["name"]))
+	}
+	description := bs.HTMLToText(ldString(posting["description"]))
+
+	jobURL := ldString(posting["url"])
+	if jobURL == "" {
+		jobURL = pageURL
+	} else if pageURL != "" {
+		jobURL = resolveURL(pageURL, jobURL)
+	}
+
+	location := postingLocation(posting["jobLocation"])
+	remoteOption := detectRemoteOption(location + " " + title)
+	for _, locationType := range ldStrings(posting["jobLocationType"]) {
+		if strings.EqualFold(locationType, "TELECOMMUTE") {
+			remoteOption = "remote"
+		}
+	}
+	if remoteOption == "remote" && location == "" {
+		location = "Remote"
+		if regions := ldStrings(posting["applicantLocationRequirements"]); len(regions) > 0 {
+			location = fmt.Sprintf("Remote (%s)", strings.Join(regions, ", "))
+		}
+	}
+
+	pay := postingSalary(posting["baseSalary"])
+	if pay.IsZero() {
+		pay = postingSalary(posting["estimatedSalary"])
+	}
+	if pay.IsZero() {
+		pay = salary.Find(description)
+	}
+	if pay.Currency == "" {
+		pay.Currency = strings.ToUpper(ldString(posting["salaryCurrency"]))
+	}
+	salaryMin, salaryMax, currency := annualSalary(pay, "USD")
+
+	postedDate := time.Now()
+	if parsed, ok := parseFeedDate(ldString(posting["datePosted"])); ok {
+		postedDate = parsed
+	}
+
+	var validThrough *time.Time
+	if parsed, ok := parseFeedDate(ldString(posting["validThrough"])); ok {
+		validThrough = &parsed
+	}
+
+	education := ldEducation(posting["educationRequirements"])
+	degreeRequired := bs.CheckDegreeRequirement(description)
+	if len(education) > 0 {
+		degreeRequired = requiresDegree(education)
+	}
+	if waived, ok := posting["experienceInPlaceOfEducation"].(bool); ok && waived {
+		degreeRequired = false
+	}
+
+	var requirements []string
+	for _, requirement := range education {
+		requirements = append(requirements, "Education: "+requirement)
+	}
+	for _, experience := range ldList(posting["experienceRequirements"]) {
+		if object, ok := experience.(map[string]interface{}); ok {
+			if months := ldNumber(object["monthsOfExperience"]); months > 0 {
+				requirements = append(requirements, fmt.Sprintf("%d+ years of experience", months/12))
+				continue
+			}
+		}
+		if text := ldString(experience); text != "" {
+			requirements = append(requirements, bs.HTMLToText(text))
+		}
+	}
+	for _, qualification := range ldStrings(posting["qualifications"]) {
+		requirements = append(requirements, bs.HTMLToText(qualification))
+	}
+
+	skillText := strings.Join(ldStrings(posting["skills"]), " ")
+	experienceText := description + " " + strings.Join(requirements, " ")
+
+	// Identifiers are usually a PropertyValue whose name is the organization
+	id := ldString(posting["identifier"])
+	if identifier, ok := posting["identifier"].(map[string]interface{}); ok {
+		id = ldString(identifier["value"])
+	}
+
+	return models.Job{
+		ID:              jobID(idPrefix(bs.Name()), id, jobURL),
+		Title:           title,
+		Company:         bs.CleanText(ldString(posting["hiringOrganization"])),
+		Location:        location,
+		Description:     description,
+		Requirements:    requirements,
+		Skills:          bs.ExtractSkills(title + " " + skillText + " " + description),
+		SalaryMin:       salaryMin,
+		SalaryMax:       salaryMax,
+		SalaryCurrency:  currency,
+		DegreeRequired:  degreeRequired,
+		ExperienceLevel: bs.DetermineExperienceLevel(title, experienceText),
+		RemoteOption:    remoteOption,
+		EmploymentType:  strings.Join(ldStrings(posting["employmentType"]), ", "),
+		PostedDate:      postedDate,
+		ValidThrough:    validThrough,
+		URL:             jobURL,
+		Source:          bs.Name(),
+		Industry:        bs.CleanText(ldString(posting["industry"])),
+		Benefits:        ldStrings(posting["jobBenefits"]),
+	}
+}
+
+// postingLocation formats one or more Place objects as "City, Region, Country"
+func postingLocation(value interface{}) string {
+	var locations []string
+	for _, place := range ldList(value) {
+		address := place
+		if object, ok := place.(map[string]interface{}); ok {
+			if nested, ok := object["address"]; ok {
+				address = nested
+			}
+		}
+
+		object, ok := address.(map[string]interface{})
+		if !ok {
+			if text := ldString(address); text != "" {
+				locations = append(locations, text)
+			}
+			continue
+		}
+
+		var parts []string
+		for _, key := range []string{"addressLocality", "addressRegion", "addressCountry"} {
+			if part := ldString(object[key]); part != "" {
+				parts = append(parts, part)
+			}
+		}
+		if len(parts) > 0 {
+			locations = append(locations, strings.Join(parts, ", "))
+		}
+	}
+	return strings.Join(locations, "; ")
+}
+
+// postingSalary reads a MonetaryAmount, or a salary written as text
+func postingSalary(value interface{}) salary.Salary {
+	amount, ok := value.(map[string]interface{})
+	if !ok {
+		// A bare number or text such as "$120,000 - $150,000 a year"
+		if text := ldString(value); text != "" {
+			return salary.Parse(text)
+		}
+		return salary.Salary{}
+	}
+
+	currency := ldString(amount["currency"])
+	unit := ldString(amount["unitText"])
+
+	var min, max float64
+	switch quantity := amount["value"].(type) {
+	case map[string]interface{}:
+		min = ldAmount(quantity["minValue"])
+		max = ldAmount(quantity["maxValue"])
+		if single := ldAmount(quantity["value"]); single > 0 {
+			if min == 0 {
+				min = single
+			}
+			if max == 0 {
+				max = single
+			}
+		}
+		if quantityUnit := ldString(quantity["unitText"]); quantityUnit != "" {
+			unit = quantityUnit
+		}
+	default:
+		min = ldAmount(quantity)
+		max = min
+	}
+	if min == 0 {
+		min = ldAmount(amount["minValue"])
+	}
+	if max == 0 {
+		max = ldAmount(amount["maxValue"])
+	}
+	if min == 0 {
+		min = max
+	}
+	if max == 0 {
+		max = min
+	}
+
+	// Structured data states its amounts, so only a missing unit leaves doubt
+	parsed := salary.Salary{Min: min, Max: max, Currency: strings.ToUpper(currency), Period: salary.ParsePeriod(unit), Confidence: 1}
+	if parsed.Period == salary.PeriodUnknown {
+		parsed.Confidence = 0.8
+	}
+	return parsed
+}
+
+// ldEducation returns the education requirements as text, reading credential categories
+func ldEducation(value interface{}) []string {
+	var education []string
+	for _, item := range ldList(value) {
+		if credential, ok := item.(map[string]interface{}); ok {
+			if category := ldString(credential["credentialCategory"]); category != "" {
+				education = append(education, category)
+				continue
+			}
+		}
+		if text := ldString(item); text != "" {
+			education = append(education, text)
+		}
+	}
+	return education
+}
+
+// requiresDegree reports whether education requirements ask for a college degree
+func requiresDegree(education []string) bool {
+	for _, requirement := range education {
+		requirement = strings.ToLower(requirement)
+		for _, degree := range []string{"degree", "bachelor", "master", "postgraduate", "doctor"} {
+			if strings.Contains(requirement, degree) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ldList wraps a single JSON-LD value in a list so single and repeated values read alike
+func ldList(value interface{}) []interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	default:
+		return []interface{}{v}
+	}
+}
+
+// ldString returns the text of a JSON-LD value; objects yield their name or value
+func ldString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case map[string]interface{}:
+		for _, key := range []string{"name", "value", "@value", "@id"} {
+			if text := ldString(v[key]); text != "" {
+				return text
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if text := ldString(item); text != "" {
+				return text
+			}
+		}
+	}
+	return ""
+}
+
+// ldStrings returns the text of every item of a JSON-LD value
+func ldStrings(value interface{}) []string {
+	var values []string
+	for _, item := range ldList(value) {
+		if text := ldString(item); text != "" {
+			values = append(values, text)
+		}
+	}
+	return values
+}
+
+// ldNumber reads a JSON-LD number as a whole number
+func ldNumber(value interface{}) int {
+	return int(ldAmount(value))
+}
+
+// ldAmount reads a JSON-LD number, which may also be written as text like "120,000"
+func ldAmount(value interface{}) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimLeft(strings.ReplaceAll(strings.TrimSpace(v), ",", ""), "$€£"), 64)
+		if err != nil {
+			return 0
+		}
+		return number
+	default:
+		return 0
+	}
+}
+
+// JobPostingScraper reads schema.org JobPosting data from a fixed list of job pages
+type JobPostingScraper struct {
+	*BaseScraper
+	pages []string
+}
+
+// NewJobPostingScraper creates a scraper for pages that embed JobPosting JSON-LD or microdata
+func NewJobPostingScraper(name string, pages []string, client *http.Client) *JobPostingScraper {
+	baseURL := ""
+	if len(pages) > 0 {
+		if parsed, err := url.Parse(pages[0]); err == nil && parsed.Host != "" {
+			baseURL = parsed.Scheme + "://" + parsed.Host
+		}
+	}
+
+	return &JobPostingScraper{
+		BaseScraper: NewBaseScraper(name, baseURL, client),
+		pages:       pages,
+	}
+}
+
+// Scrape implements the JobScraper interface. Pages that fail are skipped unless all of them fail.
+func (j *JobPostingScraper) Scrape(ctx context.Context, filters models.SearchFilters) ([]models.Job, error) {
+	var jobs []models.Job
+	var lastErr error
+
+	for _, page := range j.pages {
+		found, err := j.FetchJobPostings(ctx, page)
+		if err != nil {
+			if ctx.Err() != nil {
+				return jobs, ctx.Err()
+			}
+			lastErr = err
+			markPartial(ctx)
+			continue
+		}
+		jobs = append(jobs, found...)
+
+		if filters.Limit > 0 && len(jobs) >= filters.Limit {
+			markPartial(ctx)
+			break
+		}
+	}
+
+	if len(jobs) == 0 && lastErr != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", j.Name(), lastErr)
+	}
+	return jobs, nil
+}
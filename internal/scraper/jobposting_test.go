@@ -0,0 +1,48 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+)
+
+func TestJobPostingScraper(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer server.Close()
+
+	pages := []string{server.URL + "/posting-jsonld.html", server.URL + "/posting-microdata.html"}
+	jobs, err := NewJobPostingScraper("Careers", pages, server.Client()).Scrape(context.Background(), models.SearchFilters{})
+	if err != nil {
+		t.Fatalf("Scrape: %v", err)
+	}
+	if len(jobs) != 2 {
+		t.Fatalf("got %d jobs, want 2", len(jobs))
+	}
+
+	// JSON-LD: a TELECOMMUTE job with a yearly IDR salary and a degree requirement
+	jsonLD := jobs[0]
+	if jsonLD.ID != "careers-TW-2291" || jsonLD.Company != "Tidewater Logistics" || jsonLD.URL != server.URL+"/careers/tw-2291" {
+		t.Errorf("JSON-LD: got %s at %s, %s", jsonLD.ID, jsonLD.Company, jsonLD.URL)
+	}
+	if jsonLD.Location != "Remote (Indonesia, Malaysia)" || jsonLD.RemoteOption != "remote" {
+		t.Errorf("JSON-LD: got location %q [%s]", jsonLD.Location, jsonLD.RemoteOption)
+	}
+	if jsonLD.SalaryMin != 300000000 || jsonLD.SalaryCurrency != "IDR" {
+		t.Errorf("JSON-LD: got salary %s %d-%d", jsonLD.SalaryCurrency, jsonLD.SalaryMin, jsonLD.SalaryMax)
+	}
+	if !jsonLD.DegreeRequired || jsonLD.ExperienceLevel != "senior" || jsonLD.EmploymentType != "FULL_TIME, CONTRACTOR" || jsonLD.ValidThrough == nil {
+		t.Errorf("JSON-LD: got degree %v, level %q, employment %q, valid through %v",
+			jsonLD.DegreeRequired, jsonLD.ExperienceLevel, jsonLD.EmploymentType, jsonLD.ValidThrough)
+	}
+
+	// Microdata: an hourly salary, an address and no URL of its own
+	microdata := jobs[1]
+	if microdata.Company != "Copperleaf Retail" || microdata.Location != "Austin, TX, US" || microdata.SalaryMax != 38*2080 ||
+		microdata.DegreeRequired || microdata.PostedDate.Format("2006-01-02") != "2026-10-02" || microdata.URL != pages[1] {
+		t.Errorf("microdata: got %s in %s, salary max %d, degree %v, posted %s, URL %s", microdata.Company, microdata.Location,
+			microdata.SalaryMax, microdata.DegreeRequired, microdata.PostedDate, microdata.URL)
+	}
+}
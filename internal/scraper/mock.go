@@ -297,8 +297,13 @@ func (m *MockJobScraper) generateJob(template jobTemplate, filters models.Search
 	daysAgo := rng.Intn(30)
 	postedDate := time.Now().AddDate(0, 0, -daysAgo)
 
+	// The posting's URL is derived from its content, so the same posting generated again
+	// keeps its ID and different postings never share one
+	jobURL := fmt.Sprintf("https://%s.com/jobs/%s", strings.ToLower(m.Name()),
+		shortHash(strings.Join([]string{title, company, location, expLevel, remoteOption}, "|")))
+
 	return models.Job{
-		ID:              fmt.Sprintf("%s-%d-%d", m.Name(), time.Now().Unix(), index),
+		ID:              jobID(idPrefix(m.Name()), "", jobURL),
 		Title:           title,
 		Company:         company,
 		Location:        location,
@@ -312,7 +317,7 @@ func (m *MockJobScraper) generateJob(template jobTemplate, filters models.Search
 		ExperienceLevel: expLevel,
 		RemoteOption:    remoteOption,
 		PostedDate:      postedDate,
-		URL:             fmt.Sprintf("https://%s.com/jobs/%d", strings.ToLower(m.Name()), index),
+		URL:             jobURL,
 		Source:          m.Name(),
 		Industry:        industry,
 		Benefits:        m.generateBenefits(),
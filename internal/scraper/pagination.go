@@ -0,0 +1,152 @@
+package scraper
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Pagination describes how to reach further result pages. Either follow a "next" link,
+// or count a page number up from StartPage by PageStep (use a step of the page size for
+// offset-based sites). The number goes into PageParam when set, otherwise it replaces
+// {page} in the URL.
+type Pagination struct {
+	NextSelector string `json:"next_selector,omitempty"`
+	PageParam    string `json:"page_param,omitempty"`
+	StartPage    int    `json:"start_page,omitempty"`
+	PageStep     int    `json:"page_step,omitempty"`
+	MaxPages     int    `json:"max_pages,omitempty"` // pages fetched when the search does not set max_pages
+}
+
+// PageFunc parses one result page. It returns how many listings the page had, where
+// zero means the results have run out, and whether enough jobs have been collected.
+type PageFunc func(doc *goquery.Document, pageURL string) (found int, done bool)
+
+// pageURL returns the URL of a zero-based page index
+func (p Pagination) pageURL(baseURL string, page int) string {
+	step := p.PageStep
+	if step <= 0 {
+		step = 1
+	}
+	number := strconv.Itoa(p.StartPage + page*step)
+
+	if p.PageParam != "" {
+		parsed, err := url.Parse(baseURL)
+		if err != nil {
+			return baseURL
+		}
+		query := parsed.Query()
+		query.Set(p.PageParam, number)
+		parsed.RawQuery = query.Encode()
+		return parsed.String()
+	}
+
+	return strings.ReplaceAll(baseURL, "{page}", number)
+}
+
+// counted reports whether pages are reached by number rather than by following links
+func (p Pagination) counted(baseURL string) bool {
+	return p.PageParam != "" || strings.Contains(baseURL, "{page}")
+}
+
+// maxPages returns the page limit for a search; the search's max_pages wins over the default
+func (p Pagination) maxPages(filters models.SearchFilters) int {
+	switch {
+	case filters.MaxPages > 0:
+		return filters.MaxPages
+	case p.MaxPages > 0:
+		return p.MaxPages
+	default:
+		return 1
+	}
+}
+
+// FetchPages fetches the result pages starting at baseURL and passes each to parse.
+// It stops at the page limit, at a page without listings, when there is no next page
+// or when parse reports it has enough jobs. A failure on the first page is returned;
+// later failures end pagination and keep what earlier pages found. Stopping before the
+// results run out marks the scrape partial.
+func (bs *BaseScraper) FetchPages(ctx context.Context, baseURL string, pagination Pagination, filters models.SearchFilters, parse PageFunc) error {
+	maxPages := pagination.maxPages(filters)
+	pageURL := baseURL
+	if pagination.counted(baseURL) {
+		pageURL = pagination.pageURL(baseURL, 0)
+	}
+
+	for page := 0; pageURL != ""; page++ {
+		if page >= maxPages {
+			markPartial(ctx)
+			return nil
+		}
+
+		doc, err := bs.FetchDocument(ctx, pageURL)
+		if err != nil {
+			if page > 0 {
+				markPartial(ctx)
+				return nil
+			}
+			return err
+		}
+
+		found, done := parse(doc, pageURL)
+		if found == 0 {
+			return nil
+		}
+		if done {
+			markPartial(ctx)
+			return nil
+		}
+
+		pageURL = pagination.nextPageURL(doc, baseURL, pageURL, page+1)
+	}
+
+	return nil
+}
+
+// nextPageURL finds the URL of the next page, or "" when there is none
+func (p Pagination) nextPageURL(doc *goquery.Document, baseURL, current string, page int) string {
+	if p.NextSelector != "" {
+		href, ok := doc.Find(p.NextSelector).First().Attr("href")
+		if !ok || strings.TrimSpace(href) == "" {
+			return ""
+		}
+		return resolveURL(current, href)
+	}
+
+	if p.counted(baseURL) {
+		return p.pageURL(baseURL, page)
+	}
+
+	return ""
+}
+
+// wantedJobs returns how many jobs a search can show, counting the offset; 0 means no limit
+func wantedJobs(filters models.SearchFilters) int {
+	if filters.Limit <= 0 {
+		return 0
+	}
+	return filters.Offset + filters.Limit
+}
+
+// partialKey is the context key of the per-scrape partial flag
+type partialKey struct{}
+
+// withPartialFlag returns a context on which scrapers report that they did not read
+// everything the source lists
+func withPartialFlag(ctx context.Context) (context.Context, *int32) {
+	partial := new(int32)
+	return context.WithValue(ctx, partialKey{}, partial), partial
+}
+
+// markPartial records that a scrape skipped part of the source's listings, so jobs it did
+// not find may still be listed
+func markPartial(ctx context.Context) {
+	if partial, ok := ctx.Value(partialKey{}).(*int32); ok {
+		atomic.StoreInt32(partial, 1)
+	}
+}
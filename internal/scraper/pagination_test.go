@@ -0,0 +1,54 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+)
+
+func TestScrapeReportsCompleteness(t *testing.T) {
+	server := httptest.NewServer(http.FileServer(http.Dir("testdata")))
+	defer server.Close()
+
+	// board-1.html links to board-2.html, the last page
+	site := func(maxPages int) SiteDefinition {
+		return SiteDefinition{
+			ID:              "gopherboard",
+			Name:            "GopherBoard",
+			SearchURL:       server.URL + "/board-1.html",
+			ListingSelector: "li.job-card",
+			Fields: SiteFields{
+				Title: SelectorRule{Selector: ".job-title"},
+				Link:  SelectorRule{Selector: "a.job-link"},
+			},
+			Pagination: Pagination{NextSelector: ".pager a.next", MaxPages: maxPages},
+			Enabled:    true,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		maxPages int
+		filters  models.SearchFilters
+		complete bool
+	}{
+		{"every page read", 3, models.SearchFilters{}, true},
+		{"page limit reached", 1, models.SearchFilters{}, false},
+		{"enough jobs for the limit", 3, models.SearchFilters{Limit: 1}, false},
+	}
+	for _, test := range tests {
+		manager := NewScraperManager(nil)
+		manager.AddScraper(NewSelectorScraper(site(test.maxPages), server.Client()))
+
+		results := manager.ScrapeAll(context.Background(), test.filters)
+		if len(results) != 1 || results[0].Error != nil {
+			t.Fatalf("%s: results %+v", test.name, results)
+		}
+		if results[0].Complete != test.complete {
+			t.Errorf("%s: complete %v with %d jobs, want %v", test.name, results[0].Complete, len(results[0].Jobs), test.complete)
+		}
+	}
+}
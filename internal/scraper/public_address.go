@@ -0,0 +1,72 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrNonPublicAddress is returned for feed URLs from search requests that point into a
+// private network, such as localhost, 10.0.0.0/8 or the 169.254.169.254 metadata service
+var ErrNonPublicAddress = errors.New("address is not public")
+
+// sharedAddressSpace is 100.64.0.0/10, used by carrier-grade NAT and some cloud networks
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// isPublicIP reports whether ip is reachable on the public internet
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || sharedAddressSpace.Contains(ip))
+}
+
+// checkPublicURL rejects URLs that are not http(s) or name a host that is plainly not
+// public. Host names are checked again for every connection (see newPublicTransport).
+func checkPublicURL(raw string) (*url.URL, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Hostname() == "" {
+		return nil, fmt.Errorf("invalid job site URL: %s", raw)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("job site URL must be http or https: %s", raw)
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return nil, fmt.Errorf("%w: %s", ErrNonPublicAddress, parsed.Hostname())
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublicIP(ip) {
+		return nil, fmt.Errorf("%w: %s", ErrNonPublicAddress, parsed.Hostname())
+	}
+	return parsed, nil
+}
+
+// newPublicTransport returns a transport that only connects to public addresses. The
+// address is checked after the host name is resolved, so neither DNS records pointing
+// inside nor redirects can reach the server's own network.
+func newPublicTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrNonPublicAddress, host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Transport{
+		DialContext:        dialer.DialContext,
+		MaxIdleConns:       10,
+		IdleConnTimeout:    30 * time.Second,
+		DisableCompression: true,
+	}
+}
@@ -2,58 +2,325 @@ package scraper
 
 import (
 	"context"
+	"net/http"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
 
-// RateLimiter controls the rate of requests
+// Default limits for hosts no scraper configures a rate for
+const (
+	DefaultHostRateLimit = 60 // requests per minute
+	DefaultHostBurst     = 5
+)
+
+// RateLimiter is a token bucket: up to burst requests go through at once and tokens
+// refill continuously at rate per interval. Waiting callers are served in arrival order,
+// so a busy caller cannot starve the others.
 type RateLimiter struct {
 	rate     int
 	interval time.Duration
-	tokens   int
+	burst    int
+	tokens   float64
 	lastTime time.Time
+	stats    RateLimiterStats
 	mutex    sync.Mutex
 }
 
-// NewRateLimiter creates a new rate limiter
+// RateLimiterStats reports how often and how long callers waited for a token
+type RateLimiterStats struct {
+	Requests  int64         `json:"requests"`
+	Delayed   int64         `json:"delayed"`
+	TotalWait time.Duration `json:"total_wait"`
+	MaxWait   time.Duration `json:"max_wait"`
+}
+
+// NewRateLimiter creates a rate limiter allowing rate requests per interval, all of which
+// may be used at once
 func NewRateLimiter(rate int, interval time.Duration) *RateLimiter {
+	return NewBurstRateLimiter(rate, interval, rate)
+}
+
+// NewBurstRateLimiter creates a rate limiter allowing rate requests per interval, with at
+// most burst requests at once
+func NewBurstRateLimiter(rate int, interval time.Duration, burst int) *RateLimiter {
+	if burst <= 0 {
+		burst = 1
+	}
 	return &RateLimiter{
 		rate:     rate,
 		interval: interval,
-		tokens:   rate,
+		burst:    burst,
+		tokens:   float64(burst),
 		lastTime: time.Now(),
 	}
 }
 
-// Wait waits for the next available token
+// refillLocked adds the tokens earned since the last refill. rl.mutex must be held.
+func (rl *RateLimiter) refillLocked(now time.Time) {
+	elapsed := now.Sub(rl.lastTime)
+	rl.lastTime = now
+	if elapsed <= 0 || rl.rate <= 0 || rl.interval <= 0 {
+		return
+	}
+
+	// Fractional tokens so sub-interval gaps still count
+	rl.tokens += float64(elapsed) / float64(rl.interval) * float64(rl.rate)
+	if rl.tokens > float64(rl.burst) {
+		rl.tokens = float64(rl.burst)
+	}
+}
+
+// Wait waits for the next available token. Each caller reserves its token up front and
+// then sleeps without holding the lock, so callers are served in the order they arrived.
 func (rl *RateLimiter) Wait(ctx context.Context) error {
 	rl.mutex.Lock()
-	defer rl.mutex.Unlock()
+	if rl.rate <= 0 || rl.interval <= 0 {
+		rl.stats.Requests++
+		rl.mutex.Unlock()
+		return nil
+	}
 
-	now := time.Now()
-	elapsed := now.Sub(rl.lastTime)
+	rl.refillLocked(time.Now())
+	rl.tokens--
 
-	// Add tokens based on elapsed time
-	tokensToAdd := int(elapsed / rl.interval * time.Duration(rl.rate))
-	rl.tokens += tokensToAdd
-	if rl.tokens > rl.rate {
-		rl.tokens = rl.rate
+	var wait time.Duration
+	if rl.tokens < 0 {
+		wait = time.Duration(-rl.tokens / float64(rl.rate) * float64(rl.interval))
 	}
-	rl.lastTime = now
+	rl.stats.Requests++
+	rl.mutex.Unlock()
 
-	if rl.tokens > 0 {
-		rl.tokens--
+	if wait <= 0 {
 		return nil
 	}
 
-	// Calculate wait time
-	waitTime := rl.interval / time.Duration(rl.rate)
+	start := time.Now()
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
 
+	var err error
 	select {
-	case <-time.After(waitTime):
-		rl.tokens = rl.rate - 1
-		return nil
+	case <-timer.C:
 	case <-ctx.Done():
-		return ctx.Err()
+		err = ctx.Err()
+	}
+
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	waited := time.Since(start)
+	rl.stats.Delayed++
+	rl.stats.TotalWait += waited
+	if waited > rl.stats.MaxWait {
+		rl.stats.MaxWait = waited
+	}
+	if err != nil {
+		// Hand the reservation back to the callers queued behind us
+		rl.tokens++
+	}
+	return err
+}
+
+// setLimit changes the rate and burst, keeping the tokens already earned
+func (rl *RateLimiter) setLimit(rate int, interval time.Duration, burst int) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	rl.refillLocked(time.Now())
+	if burst <= 0 {
+		burst = 1
+	}
+	rl.rate = rate
+	rl.interval = interval
+	rl.burst = burst
+	if rl.tokens > float64(burst) {
+		rl.tokens = float64(burst)
+	}
+}
+
+// Stats returns how often and how long callers have waited
+func (rl *RateLimiter) Stats() RateLimiterStats {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	return rl.stats
+}
+
+// limit returns the current rate and burst
+func (rl *RateLimiter) limit() (int, int) {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+
+	return rl.rate, rl.burst
+}
+
+// HostRateLimit is the limit applied to one domain
+type HostRateLimit struct {
+	RateLimit int `json:"rate_limit"` // requests per minute
+	Burst     int `json:"burst"`
+}
+
+// HostRateLimitStats reports a domain's limit and how long its requests waited
+type HostRateLimitStats struct {
+	Domain string `json:"domain"`
+	HostRateLimit
+	Requests    int64 `json:"requests"`
+	Delayed     int64 `json:"delayed"`
+	TotalWaitMs int64 `json:"total_wait_ms"`
+	MaxWaitMs   int64 `json:"max_wait_ms"`
+}
+
+// HostRateLimiter keeps a token bucket per domain. A host uses the limit of the closest
+// configured domain (jobs.example.com falls under example.com), otherwise the default.
+// A host whose robots.txt asks for a crawl delay gets its own, slower bucket.
+type HostRateLimiter struct {
+	defaults HostRateLimit
+	limits   map[string]HostRateLimit
+	delays   map[string]time.Duration
+	limiters map[string]*RateLimiter
+	mu       sync.Mutex
+}
+
+// NewHostRateLimiter creates a limiter that applies defaults to unconfigured hosts
+func NewHostRateLimiter(defaults HostRateLimit) *HostRateLimiter {
+	return &HostRateLimiter{
+		defaults: defaults,
+		limits:   make(map[string]HostRateLimit),
+		delays:   make(map[string]time.Duration),
+		limiters: make(map[string]*RateLimiter),
+	}
+}
+
+// normalizeHost lowercases a host name and drops a leading "www."
+func normalizeHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
+
+// SetLimit configures the limit of a domain and its subdomains. A zero burst allows a
+// tenth of the per-minute rate at once.
+func (hl *HostRateLimiter) SetLimit(domain string, limit HostRateLimit) {
+	domain = normalizeHost(domain)
+	if domain == "" || limit.RateLimit <= 0 {
+		return
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = max(1, limit.RateLimit/10)
+	}
+
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+
+	hl.limits[domain] = limit
+}
+
+// SetCrawlDelay spaces requests to a host at least delay apart, unless its configured
+// limit is already slower. Zero removes the delay.
+func (hl *HostRateLimiter) SetCrawlDelay(host string, delay time.Duration) {
+	host = normalizeHost(host)
+
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+
+	if delay <= 0 {
+		delete(hl.delays, host)
+		return
+	}
+	hl.delays[host] = delay
+}
+
+// limiterFor returns the bucket a host's requests draw from, applying any change to its limit
+func (hl *HostRateLimiter) limiterFor(host string) *RateLimiter {
+	host = normalizeHost(host)
+
+	hl.mu.Lock()
+	defer hl.mu.Unlock()
+
+	// Walk up the domain labels to the closest configured domain
+	key, limit := host, hl.defaults
+	for candidate := host; candidate != ""; {
+		if configured, exists := hl.limits[candidate]; exists {
+			key, limit = candidate, configured
+			break
+		}
+		dot := strings.Index(candidate, ".")
+		if dot < 0 {
+			break
+		}
+		candidate = candidate[dot+1:]
+	}
+
+	if delay, exists := hl.delays[host]; exists {
+		perMinute := max(1, int(time.Minute/delay))
+		if limit.RateLimit <= 0 || perMinute < limit.RateLimit {
+			key, limit = host, HostRateLimit{RateLimit: perMinute, Burst: 1}
+		}
+	}
+
+	limiter, exists := hl.limiters[key]
+	if !exists {
+		limiter = NewBurstRateLimiter(limit.RateLimit, time.Minute, limit.Burst)
+		hl.limiters[key] = limiter
+	} else if rate, burst := limiter.limit(); rate != limit.RateLimit || burst != limit.Burst {
+		limiter.setLimit(limit.RateLimit, time.Minute, limit.Burst)
+	}
+	return limiter
+}
+
+// Wait waits until a request to host may be sent
+func (hl *HostRateLimiter) Wait(ctx context.Context, host string) error {
+	return hl.limiterFor(host).Wait(ctx)
+}
+
+// Stats returns the limit and waiting time of every domain requested so far, ordered by domain
+func (hl *HostRateLimiter) Stats() []HostRateLimitStats {
+	hl.mu.Lock()
+	domains := make([]string, 0, len(hl.limiters))
+	for domain := range hl.limiters {
+		domains = append(domains, domain)
+	}
+	limiters := make(map[string]*RateLimiter, len(hl.limiters))
+	for domain, limiter := range hl.limiters {
+		limiters[domain] = limiter
+	}
+	hl.mu.Unlock()
+
+	sort.Strings(domains)
+
+	stats := make([]HostRateLimitStats, 0, len(domains))
+	for _, domain := range domains {
+		limiter := limiters[domain]
+		waited := limiter.Stats()
+		rate, burst := limiter.limit()
+
+		stats = append(stats, HostRateLimitStats{
+			Domain:        domain,
+			HostRateLimit: HostRateLimit{RateLimit: rate, Burst: burst},
+			Requests:      waited.Requests,
+			Delayed:       waited.Delayed,
+			TotalWaitMs:   waited.TotalWait.Milliseconds(),
+			MaxWaitMs:     waited.MaxWait.Milliseconds(),
+		})
+	}
+	return stats
+}
+
+// rateLimitedTransport makes every request wait for its host's rate limit
+type rateLimitedTransport struct {
+	base    http.RoundTripper
+	limiter *HostRateLimiter
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context(), req.URL.Hostname()); err != nil {
+		return nil, err
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
 	}
+	return base.RoundTrip(req)
 }
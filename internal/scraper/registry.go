@@ -3,7 +3,13 @@ package scraper
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
 )
 
 // ScraperConfig represents configuration for a job scraper
@@ -11,34 +17,83 @@ type ScraperConfig struct {
 	Name         string            `json:"name"`
 	Enabled      bool              `json:"enabled"`
 	URL          string            `json:"url"`
-	Type         string            `json:"type"`       // "public", "authenticated", "api"
-	RateLimit    int               `json:"rate_limit"` // requests per minute
+	Type         string            `json:"type"`            // "public", "authenticated", "api", "rss", "css", "jsonld"
+	RateLimit    int               `json:"rate_limit"`      // requests per minute to the scraper's domains
+	Burst        int               `json:"burst,omitempty"` // requests allowed at once; 0 allows a tenth of the rate
 	Timeout      time.Duration     `json:"timeout"`
 	Headers      map[string]string `json:"headers"`
 	RequiresAuth bool              `json:"requires_auth"`
 	Credentials  map[string]string `json:"credentials"`
+	Feed         *FeedMapping      `json:"feed,omitempty"`  // field rules for "rss" scrapers
+	Site         *SiteDefinition   `json:"site,omitempty"`  // selector definition for "css" scrapers
+	Pages        []string          `json:"pages,omitempty"` // job detail pages for "jsonld" scrapers
+	Retry        *RetryPolicy      `json:"retry,omitempty"` // nil uses DefaultRetryPolicy
+	// IgnoreRobots skips robots.txt. It is only honoured for "api" scrapers of endpoints
+	// we have permission to use.
+	IgnoreRobots bool `json:"ignore_robots,omitempty"`
+	// BaseURL points a built-in scraper at another host serving the same pages, such as
+	// cmd/fakeboard. Requests to it use that host's own rate limit.
+	BaseURL string `json:"base_url,omitempty"`
 }
 
 // ScraperRegistry manages available scrapers
 type ScraperRegistry struct {
 	configs    map[string]ScraperConfig
 	httpClient *http.Client
+	hosts      *HostRateLimiter
+	robots     *RobotsCache
+	feeds      *http.Client // for feed URLs from search requests; reaches public addresses only
+	feedRobots *RobotsCache
+	cache      *HTTPCache // nil when responses are not cached
+	stats      map[string]ScraperStats
+	mu         sync.RWMutex
+}
+
+// ScraperStats records what happened the last time a scraper ran
+type ScraperStats struct {
+	LastUsed     time.Time
+	LastError    string
+	LastJobCount int
+	LastRetries  int
+}
+
+// registeredScraper pairs a scraper with the registry key it was created from
+type registeredScraper struct {
+	key     string
+	scraper JobScraper
 }
 
-// NewScraperRegistry creates a new scraper registry
+// NewScraperRegistry creates a new scraper registry. All of its scrapers share one
+// transport that rate limits every request by domain, and check robots.txt first. Feeds
+// named in search requests get a transport of their own that only connects to public
+// addresses.
 func NewScraperRegistry() *ScraperRegistry {
+	hosts := NewHostRateLimiter(HostRateLimit{RateLimit: DefaultHostRateLimit, Burst: DefaultHostBurst})
 	client := &http.Client{
 		Timeout: 30 * time.Second,
-		Transport: &http.Transport{
-			MaxIdleConns:       10,
-			IdleConnTimeout:    30 * time.Second,
-			DisableCompression: true,
+		Transport: &rateLimitedTransport{
+			base: &http.Transport{
+				MaxIdleConns:       10,
+				IdleConnTimeout:    30 * time.Second,
+				DisableCompression: true,
+			},
+			limiter: hosts,
 		},
 	}
 
+	feeds := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &rateLimitedTransport{base: newPublicTransport(), limiter: hosts},
+	}
+
 	registry := &ScraperRegistry{
 		configs:    make(map[string]ScraperConfig),
 		httpClient: client,
+		hosts:      hosts,
+		robots:     NewRobotsCache(client, hosts),
+		feeds:      feeds,
+		feedRobots: NewRobotsCache(feeds, hosts),
+		stats:      make(map[string]ScraperStats),
 	}
 
 	registry.loadDefaultConfigs()
@@ -59,6 +114,7 @@ func (sr *ScraperRegistry) loadDefaultConfigs() {
 		Headers: map[string]string{
 			"Accept": "application/json",
 		},
+		IgnoreRobots: true, // public JSON API offered for reuse with attribution
 	}
 
 	// WeWorkRemotely configuration
@@ -103,6 +159,17 @@ func (sr *ScraperRegistry) loadDefaultConfigs() {
 		},
 	}
 
+	// Mock configuration (generated data for development)
+	sr.configs["mock"] = ScraperConfig{
+		Name:         "MockJobSite",
+		Enabled:      true,
+		URL:          "https://mock-job-site.com",
+		Type:         "mock",
+		RateLimit:    0, // no outbound requests
+		Timeout:      30 * time.Second,
+		RequiresAuth: false,
+	}
+
 	// Indeed configuration (public RSS feeds only)
 	sr.configs["indeed"] = ScraperConfig{
 		Name:         "Indeed",
@@ -120,6 +187,15 @@ func (sr *ScraperRegistry) loadDefaultConfigs() {
 
 // CreateScraper creates a scraper instance based on configuration
 func (sr *ScraperRegistry) CreateScraper(name string) (JobScraper, error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	return sr.createScraperLocked(name)
+}
+
+// createScraperLocked creates a scraper whose HTTP client honours the configured
+// timeout, headers and rate limit. sr.mu must be held.
+func (sr *ScraperRegistry) createScraperLocked(name string) (JobScraper, error) {
 	config, exists := sr.configs[name]
 	if !exists {
 		return nil, fmt.Errorf("scraper configuration not found: %s", name)
@@ -129,37 +205,265 @@ func (sr *ScraperRegistry) CreateScraper(name string) (JobScraper, error) {
 		return nil, fmt.Errorf("scraper is disabled: %s", name)
 	}
 
+	client := sr.clientForLocked(config, sr.httpClient, sr.robots)
+
+	var builtin interface {
+		JobScraper
+		SetBaseURL(string)
+	}
 	switch name {
 	case "remoteok":
-		return NewRemoteOKScraper(sr.httpClient), nil
+		builtin = NewRemoteOKScraper(client)
 	case "weworkremotely":
-		return NewWeWorkRemotelyScraper(sr.httpClient), nil
+		builtin = NewWeWorkRemotelyScraper(client)
 	case "linkedin":
-		return NewLinkedInScraper(sr.httpClient), nil
+		builtin = NewLinkedInScraper(client)
 	case "jobstreet":
-		return NewJobStreetScraper(sr.httpClient, "id"), nil
+		builtin = NewJobStreetScraper(client, "id")
+	case "indeed":
+		builtin = NewIndeedScraper(client)
+	case "mock":
+		return NewMockJobScraper(config.Name), nil
+	}
+	if builtin != nil {
+		if config.BaseURL != "" {
+			builtin.SetBaseURL(config.BaseURL)
+		}
+		return builtin, nil
+	}
+
+	// Custom scrapers are built from their type
+	switch config.Type {
+	case "rss":
+		return newFeedScraperFromConfig(config, client)
+	case "css":
+		if config.Site == nil {
+			return nil, fmt.Errorf("css scraper %s has no site definition", config.Name)
+		}
+		return NewSelectorScraper(*config.Site, client), nil
+	case "jsonld":
+		pages := config.Pages
+		if len(pages) == 0 && config.URL != "" {
+			pages = []string{config.URL}
+		}
+		if len(pages) == 0 {
+			return nil, fmt.Errorf("jsonld scraper %s has no pages", config.Name)
+		}
+		return NewJobPostingScraper(config.Name, pages, client), nil
 	default:
 		return nil, fmt.Errorf("scraper implementation not found: %s", name)
 	}
 }
 
+// newFeedScraperFromConfig creates a feed scraper for an "rss" configuration
+func newFeedScraperFromConfig(config ScraperConfig, client *http.Client) (JobScraper, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("rss scraper %s has no feed URL", config.Name)
+	}
+
+	mapping := DefaultFeedMapping()
+	if config.Feed != nil {
+		mapping = *config.Feed
+	}
+	return NewFeedScraper(config.Name, config.URL, client, mapping), nil
+}
+
+// ScraperForSite resolves a job site from a search request to a scraper. Sites naming a
+// registered scraper use it, matched by key, then by name, then by URL, each in key
+// order; any other URL is read as an RSS/Atom feed, which may only be on a public
+// address. The returned key is empty for scrapers that are not in the registry.
+func (sr *ScraperRegistry) ScraperForSite(site models.JobSiteConfig) (JobScraper, string, error) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	keys := make([]string, 0, len(sr.configs))
+	for key := range sr.configs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	matches := []func(key string, config ScraperConfig) bool{
+		func(key string, _ ScraperConfig) bool { return site.Name != "" && strings.EqualFold(key, site.Name) },
+		func(_ string, config ScraperConfig) bool {
+			return site.Name != "" && strings.EqualFold(config.Name, site.Name)
+		},
+		func(_ string, config ScraperConfig) bool {
+			return site.URL != "" && strings.EqualFold(config.URL, site.URL)
+		},
+	}
+	for _, match := range matches {
+		for _, key := range keys {
+			if match(key, sr.configs[key]) {
+				scraper, err := sr.createScraperLocked(key)
+				return scraper, key, err
+			}
+		}
+	}
+
+	if site.URL == "" {
+		return nil, "", fmt.Errorf("unknown job site: %s", site.Name)
+	}
+
+	parsed, err := checkPublicURL(site.URL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	name := site.Name
+	if name == "" || name == site.URL {
+		name = parsed.Host
+	}
+
+	config := ScraperConfig{Name: name, Enabled: true, URL: site.URL, Type: "rss"}
+	scraper, err := newFeedScraperFromConfig(config, sr.clientForLocked(config, sr.feeds, sr.feedRobots))
+	return scraper, "", err
+}
+
+// clientForLocked builds an HTTP client for a scraper from its configuration on top of
+// client, checking robots.txt with robots. sr.mu must be held.
+func (sr *ScraperRegistry) clientForLocked(config ScraperConfig, client *http.Client, robots *RobotsCache) *http.Client {
+	// The budget belongs to the domain, so scrapers and searches sharing a site share it
+	for _, domain := range configDomains(config) {
+		sr.hosts.SetLimit(domain, HostRateLimit{RateLimit: config.RateLimit, Burst: config.Burst})
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = client.Timeout
+	}
+
+	retry := DefaultRetryPolicy()
+	if config.Retry != nil {
+		retry = *config.Retry
+	}
+
+	// Cached responses are answered before they take a token from the rate limiter
+	base := client.Transport
+	if sr.cache != nil {
+		base = sr.cache.Transport(base)
+	}
+	if !config.IgnoreRobots || config.Type != "api" {
+		base = &robotsTransport{base: base, robots: robots}
+	}
+
+	// Retries go through the limiter again so they count against the domain's budget
+	return WithRetry(&http.Client{
+		Timeout: timeout,
+		Transport: &configuredTransport{
+			base:    base,
+			headers: config.Headers,
+		},
+	}, retry)
+}
+
+// configDomains returns the hosts a scraper configuration requests
+func configDomains(config ScraperConfig) []string {
+	urls := append([]string{config.URL}, config.Pages...)
+	if config.Site != nil {
+		urls = append(urls, config.Site.SearchURL)
+	}
+
+	var domains []string
+	for _, raw := range urls {
+		if parsed, err := url.Parse(raw); err == nil && parsed.Hostname() != "" {
+			domains = append(domains, parsed.Hostname())
+		}
+	}
+	return domains
+}
+
+// SetHTTPCache makes every scraper created afterwards answer repeated requests from cache
+func (sr *ScraperRegistry) SetHTTPCache(cache *HTTPCache) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	sr.cache = cache
+}
+
+// HTTPCacheStats reports the response cache's hits and size; false when there is no cache
+func (sr *ScraperRegistry) HTTPCacheStats() (HTTPCacheStats, bool) {
+	sr.mu.RLock()
+	cache := sr.cache
+	sr.mu.RUnlock()
+
+	if cache == nil {
+		return HTTPCacheStats{}, false
+	}
+	return cache.Stats(), true
+}
+
+// RobotsStats reports the robots.txt of every site checked so far and how many URLs it blocked
+func (sr *ScraperRegistry) RobotsStats() []RobotsStats {
+	return sr.robots.Stats()
+}
+
+// RateLimitStats reports the limit of every domain requested so far and how long requests waited for it
+func (sr *ScraperRegistry) RateLimitStats() []HostRateLimitStats {
+	return sr.hosts.Stats()
+}
+
 // GetEnabledScrapers returns all enabled scrapers
 func (sr *ScraperRegistry) GetEnabledScrapers() []JobScraper {
 	var scrapers []JobScraper
+	for _, registered := range sr.enabledScrapers() {
+		scrapers = append(scrapers, registered.scraper)
+	}
+	return scrapers
+}
 
+// enabledScrapers creates all enabled scrapers, ordered by registry key
+func (sr *ScraperRegistry) enabledScrapers() []registeredScraper {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	names := make([]string, 0, len(sr.configs))
 	for name, config := range sr.configs {
 		if config.Enabled {
-			if scraper, err := sr.CreateScraper(name); err == nil {
-				scrapers = append(scrapers, scraper)
-			}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var scrapers []registeredScraper
+	for _, name := range names {
+		if scraper, err := sr.createScraperLocked(name); err == nil {
+			scrapers = append(scrapers, registeredScraper{key: name, scraper: scraper})
 		}
 	}
 
 	return scrapers
 }
 
+// RecordResult stores the outcome of a scraper run for status reporting
+func (sr *ScraperRegistry) RecordResult(name string, result models.ScrapingResult) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	stats := ScraperStats{
+		LastUsed:     time.Now(),
+		LastJobCount: len(result.Jobs),
+		LastRetries:  result.Retries,
+	}
+	if result.Error != nil {
+		stats.LastError = result.Error.Error()
+	}
+	sr.stats[name] = stats
+}
+
+// GetScraperStats returns what happened the last time a scraper ran
+func (sr *ScraperRegistry) GetScraperStats(name string) (ScraperStats, bool) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	stats, exists := sr.stats[name]
+	return stats, exists
+}
+
 // EnableScraper enables a scraper
 func (sr *ScraperRegistry) EnableScraper(name string) error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
 	config, exists := sr.configs[name]
 	if !exists {
 		return fmt.Errorf("scraper not found: %s", name)
@@ -170,8 +474,32 @@ func (sr *ScraperRegistry) EnableScraper(name string) error {
 	return nil
 }
 
+// SetBaseURL points a built-in scraper at another host; an empty baseURL restores its real site
+func (sr *ScraperRegistry) SetBaseURL(name, baseURL string) error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	config, exists := sr.configs[name]
+	if !exists {
+		return fmt.Errorf("scraper not found: %s", name)
+	}
+
+	config.BaseURL = baseURL
+	sr.configs[name] = config
+	return nil
+}
+
+// SetHostRateLimit configures the request budget of a domain no scraper configuration
+// names, such as a local host the built-in scrapers were pointed at with SetBaseURL
+func (sr *ScraperRegistry) SetHostRateLimit(domain string, limit HostRateLimit) {
+	sr.hosts.SetLimit(domain, limit)
+}
+
 // DisableScraper disables a scraper
 func (sr *ScraperRegistry) DisableScraper(name string) error {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
 	config, exists := sr.configs[name]
 	if !exists {
 		return fmt.Errorf("scraper not found: %s", name)
@@ -184,6 +512,9 @@ func (sr *ScraperRegistry) DisableScraper(name string) error {
 
 // GetScraperConfig returns configuration for a scraper
 func (sr *ScraperRegistry) GetScraperConfig(name string) (ScraperConfig, error) {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
 	config, exists := sr.configs[name]
 	if !exists {
 		return ScraperConfig{}, fmt.Errorf("scraper not found: %s", name)
@@ -193,20 +524,58 @@ func (sr *ScraperRegistry) GetScraperConfig(name string) (ScraperConfig, error)
 
 // ListScrapers returns all available scraper configurations
 func (sr *ScraperRegistry) ListScrapers() map[string]ScraperConfig {
-	return sr.configs
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+
+	configs := make(map[string]ScraperConfig, len(sr.configs))
+	for name, config := range sr.configs {
+		configs[name] = config
+	}
+	return configs
 }
 
 // AddCustomScraper adds a custom scraper configuration
 func (sr *ScraperRegistry) AddCustomScraper(name string, config ScraperConfig) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
 	sr.configs[name] = config
 }
 
 // ScraperStatus represents the status of a scraper
 type ScraperStatus struct {
-	Name         string `json:"name"`
-	Enabled      bool   `json:"enabled"`
-	Type         string `json:"type"`
-	RequiresAuth bool   `json:"requires_auth"`
-	LastUsed     string `json:"last_used"`
-	Status       string `json:"status"` // "active", "error", "disabled"
+	ID           string        `json:"id"` // registry key used by the enable/disable endpoints
+	Name         string        `json:"name"`
+	Enabled      bool          `json:"enabled"`
+	Type         string        `json:"type"`
+	RequiresAuth bool          `json:"requires_auth"`
+	LastUsed     string        `json:"last_used"`
+	LastError    string        `json:"last_error,omitempty"`
+	LastJobCount int           `json:"last_job_count"`
+	LastRetries  int           `json:"last_retries"`
+	Status       string        `json:"status"` // "active", "error", "requires_auth", "circuit_open", "disabled"
+	Circuit      CircuitStatus `json:"circuit"`
+}
+
+// configuredTransport applies a scraper's configured headers to every request
+type configuredTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *configuredTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(t.headers) > 0 {
+		// RoundTrippers must not modify the caller's request
+		req = req.Clone(req.Context())
+		for key, value := range t.headers {
+			req.Header.Set(key, value)
+		}
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
 }
@@ -0,0 +1,78 @@
+package scraper
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+)
+
+func TestScraperForSiteRejectsPrivateFeeds(t *testing.T) {
+	registry := NewScraperRegistry()
+	for _, feedURL := range []string{
+		"http://127.0.0.1:8080/feed.rss",
+		"http://localhost/feed.rss",
+		"http://jobs.localhost./feed.rss",
+		"http://10.0.0.5/rss",
+		"http://192.168.1.1/rss",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]/feed.rss",
+		"http://[fd00::1]/feed.rss",
+		"http://100.64.0.1/feed.rss",
+		"http://0.0.0.0/feed.rss",
+		"file:///etc/passwd",
+		"gopher://jobs.example.com/feed",
+	} {
+		if scraper, _, err := registry.ScraperForSite(models.JobSiteConfig{URL: feedURL}); err == nil {
+			t.Errorf("%s: got scraper %s, want an error", feedURL, scraper.Name())
+		}
+	}
+
+	scraper, key, err := registry.ScraperForSite(models.JobSiteConfig{URL: "https://jobs.example.com/feed.rss"})
+	if err != nil || key != "" || scraper.Name() != "jobs.example.com" {
+		t.Errorf("public feed: got %v, %q, %v", scraper, key, err)
+	}
+}
+
+func TestPublicTransportRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("request for %s reached the server", r.URL)
+	}))
+	defer server.Close()
+
+	// A public host name may still resolve to, or redirect to, a private address
+	client := &http.Client{Transport: newPublicTransport()}
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, ErrNonPublicAddress) {
+		t.Errorf("got %v, want ErrNonPublicAddress", err)
+	}
+}
+
+func TestScraperForSiteMatchesInOrder(t *testing.T) {
+	registry := NewScraperRegistry()
+	feedURL := "https://jobs.example.com/feed.rss"
+	registry.AddCustomScraper("b-feed", ScraperConfig{Name: "Jobs", Enabled: true, Type: "rss", URL: feedURL})
+	registry.AddCustomScraper("a-feed", ScraperConfig{Name: "Jobs Mirror", Enabled: true, Type: "rss", URL: feedURL})
+	registry.AddCustomScraper("jobs", ScraperConfig{Name: "Other Jobs", Enabled: true, Type: "rss", URL: "https://other.example.com/feed.rss"})
+
+	tests := []struct {
+		site models.JobSiteConfig
+		want string
+	}{
+		{models.JobSiteConfig{URL: feedURL}, "a-feed"},
+		{models.JobSiteConfig{Name: "Jobs", URL: feedURL}, "jobs"},
+		{models.JobSiteConfig{Name: "jobs mirror"}, "a-feed"},
+		{models.JobSiteConfig{Name: "b-feed", URL: "https://other.example.com/feed.rss"}, "b-feed"},
+	}
+	for _, test := range tests {
+		// Map order varies between runs; the match must not
+		for i := 0; i < 20; i++ {
+			if _, key, err := registry.ScraperForSite(test.site); err != nil || key != test.want {
+				t.Errorf("%+v: got %q, %v; want %q", test.site, key, err, test.want)
+				break
+			}
+		}
+	}
+}
@@ -5,11 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/salary"
+	"github.com/Illuminateee/web-scrapper.git/internal/skills"
 )
 
 // RemoteOKScraper scrapes jobs from RemoteOK.io
@@ -37,12 +38,14 @@ type RemoteOKJob struct {
 	Description string   `json:"description"`
 	Apply       string   `json:"apply"`
 	Salary      string   `json:"salary"`
+	SalaryMin   int      `json:"salary_min"` // yearly USD; the API's numeric salary fields
+	SalaryMax   int      `json:"salary_max"`
 }
 
 // Scrape implements the JobScraper interface
 func (r *RemoteOKScraper) Scrape(ctx context.Context, filters models.SearchFilters) ([]models.Job, error) {
 	// RemoteOK has a public API
-	apiURL := "https://remoteok.io/api"
+	apiURL := r.baseURL + "/api"
 
 	// Add query parameters if available
 	if filters.JobTitle != "" {
@@ -92,17 +95,18 @@ func (r *RemoteOKScraper) Scrape(ctx context.Context, filters models.SearchFilte
 }
 
 func (r *RemoteOKScraper) convertRemoteOKJob(rJob RemoteOKJob) models.Job {
-	// Parse salary from string
-	var salaryMin, salaryMax int
-	if rJob.Salary != "" {
-		salaryMin, salaryMax = r.parseSalary(rJob.Salary)
+	// The numeric salary fields are preferred over the salary text
+	pay := salary.Salary{Min: float64(rJob.SalaryMin), Max: float64(rJob.SalaryMax), Currency: "USD", Period: salary.PeriodYear}
+	if pay.IsZero() {
+		pay = salary.Parse(rJob.Salary)
 	}
+	salaryMin, salaryMax, currency := annualSalary(pay, "USD")
 
 	// Determine experience level from tags and position
 	expLevel := r.DetermineExperienceLevel(rJob.Position, strings.Join(rJob.Tags, " "))
 
 	// Extract skills from tags
-	skills := r.extractSkillsFromTags(rJob.Tags)
+	tagSkills := r.extractSkillsFromTags(rJob.Tags)
 
 	// Parse date
 	postedDate := time.Now() // Default to now if parsing fails
@@ -112,84 +116,36 @@ func (r *RemoteOKScraper) convertRemoteOKJob(rJob RemoteOKJob) models.Job {
 		}
 	}
 
+	jobURL := fmt.Sprintf("%s/remote-jobs/%s", r.baseURL, rJob.ID)
 	return models.Job{
-		ID:              fmt.Sprintf("remoteok-%s", rJob.ID),
+		ID:              jobID("remoteok", rJob.ID, jobURL),
 		Title:           rJob.Position,
 		Company:         rJob.Company,
 		Location:        "Remote", // RemoteOK is all remote jobs
 		Description:     rJob.Description,
-		Skills:          skills,
+		Skills:          tagSkills,
 		SalaryMin:       salaryMin,
 		SalaryMax:       salaryMax,
-		SalaryCurrency:  "USD",
+		SalaryCurrency:  currency,
 		DegreeRequired:  r.CheckDegreeRequirement(rJob.Description),
 		ExperienceLevel: expLevel,
 		RemoteOption:    "remote",
 		PostedDate:      postedDate,
-		URL:             fmt.Sprintf("https://remoteok.io/remote-jobs/%s", rJob.ID),
+		URL:             jobURL,
 		Source:          r.Name(),
 		Industry:        "Technology",
 	}
 }
 
-func (r *RemoteOKScraper) parseSalary(salaryStr string) (int, int) {
-	// Remove common currency symbols and formatting
-	cleaned := strings.ReplaceAll(salaryStr, "$", "")
-	cleaned = strings.ReplaceAll(cleaned, ",", "")
-	cleaned = strings.ReplaceAll(cleaned, "k", "000")
-	cleaned = strings.ReplaceAll(cleaned, "K", "000")
-
-	// Look for patterns like "80000-120000" or "80k-120k"
-	if strings.Contains(cleaned, "-") {
-		parts := strings.Split(cleaned, "-")
-		if len(parts) == 2 {
-			if min, err := strconv.Atoi(strings.TrimSpace(parts[0])); err == nil {
-				if max, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil {
-					return min, max
-				}
-			}
-		}
-	}
-
-	// Single salary value
-	if val, err := strconv.Atoi(strings.TrimSpace(cleaned)); err == nil {
-		return val, val
-	}
-
-	return 0, 0
-}
-
+// extractSkillsFromTags keeps the tags that are skills of the taxonomy, by canonical name
 func (r *RemoteOKScraper) extractSkillsFromTags(tags []string) []string {
-	var skills []string
-	skillSet := make(map[string]bool)
-
+	var tagSkills []string
 	for _, tag := range tags {
-		tagLower := strings.ToLower(tag)
-		// Add relevant technical tags as skills
-		if r.isTechnicalSkill(tagLower) && !skillSet[tagLower] {
-			skills = append(skills, tag)
-			skillSet[tagLower] = true
+		if skill, exists := skills.Lookup(tag); exists {
+			tagSkills = append(tagSkills, skill.Name)
 		}
 	}
-
-	return skills
-}
-
-func (r *RemoteOKScraper) isTechnicalSkill(tag string) bool {
-	technicalSkills := []string{
-		"javascript", "python", "java", "go", "golang", "rust", "php", "ruby", "swift", "kotlin",
-		"react", "angular", "vue", "node", "django", "flask", "spring", "express",
-		"mysql", "postgresql", "mongodb", "redis", "elasticsearch",
-		"aws", "azure", "gcp", "docker", "kubernetes", "jenkins",
-		"git", "linux", "sql", "nosql", "api", "rest", "graphql",
-	}
-
-	for _, skill := range technicalSkills {
-		if strings.Contains(tag, skill) {
-			return true
-		}
-	}
-	return false
+	return skills.Canonicalize(tagSkills)
 }
 
 func (r *RemoteOKScraper) matchesFilters(job models.Job, filters models.SearchFilters) bool {
@@ -216,10 +172,7 @@ func (r *RemoteOKScraper) matchesFilters(job models.Job, filters models.SearchFi
 	}
 
 	// Salary filters
-	if filters.MinSalary > 0 && (job.SalaryMin == 0 || job.SalaryMin < filters.MinSalary) {
-		return false
-	}
-	if filters.MaxSalary > 0 && (job.SalaryMax == 0 || job.SalaryMax > filters.MaxSalary) {
+	if !matchesSalary(job, filters) {
 		return false
 	}
 
@@ -238,17 +191,8 @@ func (r *RemoteOKScraper) matchesFilters(job models.Job, filters models.SearchFi
 	}
 
 	// Skills filter
-	if len(filters.Skills) > 0 {
-		jobSkills := make(map[string]bool)
-		for _, skill := range job.Skills {
-			jobSkills[strings.ToLower(skill)] = true
-		}
-
-		for _, requiredSkill := range filters.Skills {
-			if !jobSkills[strings.ToLower(requiredSkill)] {
-				return false
-			}
-		}
+	if !matchesSkills(job, filters) {
+		return false
 	}
 
 	return true
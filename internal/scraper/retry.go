@@ -0,0 +1,235 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Delays grow exponentially from
+// BaseDelay with random jitter up to MaxDelay, never below a server's Retry-After. A request
+// is not retried when Retry-After asks for a longer wait than MaxDelay. In JSON the delays
+// are duration strings such as "500ms" or "10s".
+type RetryPolicy struct {
+	MaxRetries int           `json:"max_retries"`
+	BaseDelay  time.Duration `json:"base_delay"`
+	MaxDelay   time.Duration `json:"max_delay"`
+}
+
+// retryPolicyJSON is the JSON layout of a RetryPolicy
+type retryPolicyJSON struct {
+	MaxRetries int             `json:"max_retries"`
+	BaseDelay  json.RawMessage `json:"base_delay,omitempty"`
+	MaxDelay   json.RawMessage `json:"max_delay,omitempty"`
+}
+
+// MarshalJSON writes the delays as duration strings
+func (p RetryPolicy) MarshalJSON() ([]byte, error) {
+	baseDelay, _ := json.Marshal(p.BaseDelay.String())
+	maxDelay, _ := json.Marshal(p.MaxDelay.String())
+	return json.Marshal(retryPolicyJSON{MaxRetries: p.MaxRetries, BaseDelay: baseDelay, MaxDelay: maxDelay})
+}
+
+// UnmarshalJSON reads the delays from duration strings. Plain numbers are rejected, since
+// it is unclear whether they are seconds, milliseconds or nanoseconds.
+func (p *RetryPolicy) UnmarshalJSON(data []byte) error {
+	var raw retryPolicyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	baseDelay, err := parseDelay("base_delay", raw.BaseDelay)
+	if err != nil {
+		return err
+	}
+	maxDelay, err := parseDelay("max_delay", raw.MaxDelay)
+	if err != nil {
+		return err
+	}
+
+	*p = RetryPolicy{MaxRetries: raw.MaxRetries, BaseDelay: baseDelay, MaxDelay: maxDelay}
+	return nil
+}
+
+// parseDelay reads a JSON duration string; a missing delay is zero
+func parseDelay(field string, raw json.RawMessage) (time.Duration, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return 0, nil
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return 0, fmt.Errorf("retry %s must be a duration such as \"500ms\", got %s", field, raw)
+	}
+	delay, err := time.ParseDuration(text)
+	if err != nil || delay < 0 {
+		return 0, fmt.Errorf("retry %s must be a duration such as \"500ms\", got %q", field, text)
+	}
+	return delay, nil
+}
+
+// DefaultRetryPolicy is used by scrapers whose configuration has no retry policy
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   10 * time.Second,
+	}
+}
+
+// backoff returns the jittered delay before retry number attempt (starting at 0)
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay << uint(attempt)
+	if delay <= 0 || (p.MaxDelay > 0 && delay > p.MaxDelay) {
+		delay = p.MaxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	// Equal jitter: half fixed, half random, so concurrent clients spread out
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// retryableStatus reports whether a response status is worth retrying
+func retryableStatus(code int) bool {
+	switch {
+	case code == http.StatusTooManyRequests, code == http.StatusRequestTimeout:
+		return true
+	case code == http.StatusNotImplemented, code == http.StatusHTTPVersionNotSupported:
+		return false
+	default:
+		return code >= 500
+	}
+}
+
+// retryableError reports whether a transport error is temporary, such as a timeout or reset connection
+func retryableError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+// retryAfter reads a Retry-After header given in seconds or as an HTTP date
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// WithRetry returns a copy of client that retries requests according to policy
+func WithRetry(client *http.Client, policy RetryPolicy) *http.Client {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	retrying := *client
+	retrying.Transport = &retryTransport{base: base, policy: policy}
+	return &retrying
+}
+
+// retryTransport retries requests that fail with a retryable error or status
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Requests with a body can only be replayed when it can be recreated
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+
+		retryable := false
+		var wait time.Duration
+		switch {
+		case err != nil:
+			retryable = retryableError(err)
+		case retryableStatus(resp.StatusCode):
+			retryable = true
+			wait = retryAfter(resp)
+		}
+		if !retryable || !replayable || attempt >= t.policy.MaxRetries {
+			return resp, err
+		}
+
+		if delay := t.policy.backoff(attempt); delay > wait {
+			wait = delay
+		}
+		if t.policy.MaxDelay > 0 && wait > t.policy.MaxDelay {
+			// The server asked for a longer pause than we are willing to wait
+			return resp, err
+		}
+
+		if resp != nil {
+			// Drain so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		}
+
+		countRetry(req.Context())
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// retryCounterKey is the context key of the per-scrape retry counter
+type retryCounterKey struct{}
+
+// withRetryCounter returns a context that counts the retries of requests made with it
+func withRetryCounter(ctx context.Context) (context.Context, *int64) {
+	counter := new(int64)
+	return context.WithValue(ctx, retryCounterKey{}, counter), counter
+}
+
+// countRetry records a retry against the context's counter, if any
+func countRetry(ctx context.Context) {
+	if counter, ok := ctx.Value(retryCounterKey{}).(*int64); ok {
+		atomic.AddInt64(counter, 1)
+	}
+}
@@ -0,0 +1,45 @@
+package scraper
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyJSON(t *testing.T) {
+	var config ScraperConfig
+	data := `{"name": "Acme", "retry": {"max_retries": 5, "base_delay": "500ms", "max_delay": "1m"}}`
+	if err := json.Unmarshal([]byte(data), &config); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	want := RetryPolicy{MaxRetries: 5, BaseDelay: 500 * time.Millisecond, MaxDelay: time.Minute}
+	if config.Retry == nil || *config.Retry != want {
+		t.Fatalf("got %+v, want %+v", config.Retry, want)
+	}
+
+	// Written back as duration strings, and read again unchanged
+	encoded, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if string(encoded) != `{"max_retries":5,"base_delay":"500ms","max_delay":"1m0s"}` {
+		t.Errorf("encoded as %s", encoded)
+	}
+	var decoded RetryPolicy
+	if err := json.Unmarshal(encoded, &decoded); err != nil || decoded != want {
+		t.Errorf("decoded %s as %+v, %v", encoded, decoded, err)
+	}
+
+	// A bare number would be nanoseconds, which is never what was meant
+	for _, bad := range []string{`{"base_delay": 500}`, `{"max_delay": "ten seconds"}`, `{"base_delay": "-1s"}`} {
+		var policy RetryPolicy
+		if err := json.Unmarshal([]byte(bad), &policy); err == nil {
+			t.Errorf("%s: decoded as %+v, want an error", bad, policy)
+		}
+	}
+
+	var empty RetryPolicy
+	if err := json.Unmarshal([]byte(`{"max_retries": 1}`), &empty); err != nil || empty != (RetryPolicy{MaxRetries: 1}) {
+		t.Errorf("got %+v, %v without delays", empty, err)
+	}
+}
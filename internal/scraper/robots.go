@@ -0,0 +1,327 @@
+package scraper
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RobotsUserAgent is the product token matched against robots.txt user-agent lines
+const RobotsUserAgent = "JobScraper"
+
+const (
+	robotsTTL      = 24 * time.Hour // how long fetched rules are kept
+	robotsErrorTTL = time.Minute    // how long an unreachable robots.txt blocks its host
+	robotsMaxSize  = 500 << 10      // robots.txt content read; the rest is ignored
+)
+
+// RobotsError is returned for requests that robots.txt does not allow
+type RobotsError struct {
+	URL    string
+	Reason string // the matching rule, or why robots.txt could not be read
+}
+
+func (e *RobotsError) Error() string {
+	return fmt.Sprintf("blocked by robots.txt: %s (%s)", e.URL, e.Reason)
+}
+
+// robotsRule is one Allow or Disallow line
+type robotsRule struct {
+	allow   bool
+	pattern string
+	match   *regexp.Regexp
+}
+
+// robotsRules are the rules of one host that apply to us
+type robotsRules struct {
+	rules      []robotsRule
+	crawlDelay time.Duration
+	// unavailable is set when robots.txt could not be read, which disallows everything
+	unavailable string
+}
+
+// parseRobots reads the groups of a robots.txt that apply to agent, falling back to the
+// "*" groups when none names it
+func parseRobots(body io.Reader, agent string) *robotsRules {
+	agent = strings.ToLower(agent)
+	own, wildcard := &robotsRules{}, &robotsRules{}
+
+	var targets []*robotsRules
+	inAgents := false
+	scanner := bufio.NewScanner(io.LimitReader(body, robotsMaxSize))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if key == "user-agent" {
+			// Consecutive user-agent lines share the rules that follow them
+			if !inAgents {
+				targets = nil
+			}
+			inAgents = true
+			switch name := strings.ToLower(value); {
+			case name == "*":
+				targets = append(targets, wildcard)
+			case name == agent:
+				targets = append(targets, own)
+			}
+			continue
+		}
+		inAgents = false
+
+		for _, target := range targets {
+			switch key {
+			case "allow", "disallow":
+				if value == "" {
+					continue
+				}
+				target.rules = append(target.rules, robotsRule{
+					allow:   key == "allow",
+					pattern: value,
+					match:   robotsPattern(value),
+				})
+			case "crawl-delay":
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+					target.crawlDelay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		}
+	}
+
+	if len(own.rules) > 0 || own.crawlDelay > 0 {
+		return own
+	}
+	return wildcard
+}
+
+// robotsPattern compiles a path pattern, where * matches anything and a trailing $ anchors the end
+func robotsPattern(pattern string) *regexp.Regexp {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// allowed reports whether path may be fetched and the rule that decided it. The longest
+// matching pattern wins and Allow wins a tie.
+func (r *robotsRules) allowed(path string) (bool, string) {
+	if r.unavailable != "" {
+		return false, r.unavailable
+	}
+
+	var best *robotsRule
+	for i := range r.rules {
+		rule := &r.rules[i]
+		if !rule.match.MatchString(path) {
+			continue
+		}
+		if best == nil || len(rule.pattern) > len(best.pattern) ||
+			(len(rule.pattern) == len(best.pattern) && rule.allow && !best.allow) {
+			best = rule
+		}
+	}
+
+	if best == nil || best.allow {
+		return true, ""
+	}
+	return false, "Disallow: " + best.pattern
+}
+
+// robotsEntry is the cached robots.txt of one origin; ready is closed once it is fetched
+type robotsEntry struct {
+	ready   chan struct{}
+	rules   *robotsRules
+	status  string
+	expires time.Time
+	blocked int64
+}
+
+// RobotsStats reports the robots.txt of one origin and how many URLs it blocked
+type RobotsStats struct {
+	Origin       string  `json:"origin"`
+	Status       string  `json:"status"` // "ok", "missing" or "unavailable"
+	Rules        int     `json:"rules"`
+	CrawlDelay   float64 `json:"crawl_delay"` // seconds
+	BlockedCount int64   `json:"blocked_count"`
+}
+
+// RobotsCache fetches and caches robots.txt per origin. Crawl delays are passed on to the
+// host rate limiter.
+type RobotsCache struct {
+	client  *http.Client
+	limiter *HostRateLimiter
+	entries map[string]*robotsEntry
+	mu      sync.Mutex
+}
+
+// NewRobotsCache creates a cache that fetches robots.txt with client. limiter may be nil.
+func NewRobotsCache(client *http.Client, limiter *HostRateLimiter) *RobotsCache {
+	return &RobotsCache{
+		client:  client,
+		limiter: limiter,
+		entries: make(map[string]*robotsEntry),
+	}
+}
+
+// Check returns a *RobotsError when robots.txt does not allow fetching target
+func (rc *RobotsCache) Check(ctx context.Context, target *url.URL) error {
+	if target.Path == "/robots.txt" {
+		return nil
+	}
+
+	entry, err := rc.entryFor(ctx, target)
+	if err != nil {
+		return err
+	}
+
+	path := target.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if target.RawQuery != "" {
+		path += "?" + target.RawQuery
+	}
+
+	allowed, reason := entry.rules.allowed(path)
+	if allowed {
+		return nil
+	}
+
+	rc.mu.Lock()
+	entry.blocked++
+	rc.mu.Unlock()
+
+	log.Printf("Blocked by robots.txt: %s (%s)", target, reason)
+	return &RobotsError{URL: target.String(), Reason: reason}
+}
+
+// entryFor returns the rules of target's origin, fetching them once when missing or expired
+func (rc *RobotsCache) entryFor(ctx context.Context, target *url.URL) (*robotsEntry, error) {
+	origin := target.Scheme + "://" + strings.ToLower(target.Host)
+
+	rc.mu.Lock()
+	entry, exists := rc.entries[origin]
+	stale := exists && entry.rules != nil && time.Now().After(entry.expires)
+	if !exists || stale {
+		fresh := &robotsEntry{ready: make(chan struct{})}
+		if stale {
+			// Keep counting against the origin across refreshes
+			fresh.blocked = entry.blocked
+		}
+		entry = fresh
+		rc.entries[origin] = entry
+		rc.mu.Unlock()
+
+		rules, status, ttl := rc.fetch(origin)
+		if rc.limiter != nil {
+			rc.limiter.SetCrawlDelay(target.Hostname(), rules.crawlDelay)
+		}
+
+		rc.mu.Lock()
+		entry.rules, entry.status, entry.expires = rules, status, time.Now().Add(ttl)
+		rc.mu.Unlock()
+		close(entry.ready)
+		return entry, nil
+	}
+	rc.mu.Unlock()
+
+	select {
+	case <-entry.ready:
+		return entry, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fetch downloads an origin's robots.txt. A missing file allows everything; a server error
+// or unreachable host disallows everything for a short while.
+func (rc *RobotsCache) fetch(origin string) (*robotsRules, string, time.Duration) {
+	// Not tied to the first caller's context, since the result is shared
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", origin+"/robots.txt", nil)
+	if err != nil {
+		return &robotsRules{unavailable: "invalid robots.txt URL"}, "unavailable", robotsErrorTTL
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; "+RobotsUserAgent+"/1.0)")
+
+	resp, err := rc.client.Do(req)
+	if err != nil {
+		return &robotsRules{unavailable: fmt.Sprintf("robots.txt unavailable: %v", err)}, "unavailable", robotsErrorTTL
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return parseRobots(resp.Body, RobotsUserAgent), "ok", robotsTTL
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		return &robotsRules{}, "missing", robotsTTL
+	default:
+		reason := fmt.Sprintf("robots.txt unavailable: status code %d", resp.StatusCode)
+		return &robotsRules{unavailable: reason}, "unavailable", robotsErrorTTL
+	}
+}
+
+// Stats returns the robots.txt state of every origin checked so far, ordered by origin
+func (rc *RobotsCache) Stats() []RobotsStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	stats := make([]RobotsStats, 0, len(rc.entries))
+	for origin, entry := range rc.entries {
+		if entry.rules == nil {
+			continue
+		}
+		stats = append(stats, RobotsStats{
+			Origin:       origin,
+			Status:       entry.status,
+			Rules:        len(entry.rules.rules),
+			CrawlDelay:   entry.rules.crawlDelay.Seconds(),
+			BlockedCount: entry.blocked,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Origin < stats[j].Origin })
+	return stats
+}
+
+// robotsTransport refuses requests that robots.txt does not allow
+type robotsTransport struct {
+	base   http.RoundTripper
+	robots *RobotsCache
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *robotsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.robots.Check(req.Context(), req.URL); err != nil {
+		return nil, err
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,299 @@
+package scraper
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/salary"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/andybalholm/cascadia"
+)
+
+// SelectorRule describes how to read one field from a listing element
+type SelectorRule struct {
+	Selector string `json:"selector"`          // CSS selector relative to the listing; empty means the listing itself
+	Attr     string `json:"attr,omitempty"`    // attribute to read instead of the element text
+	Pattern  string `json:"pattern,omitempty"` // optional regex; its first capture group (or whole match) is used
+}
+
+// SiteFields holds the selector rules for each job field
+type SiteFields struct {
+	Title       SelectorRule `json:"title"`
+	Company     SelectorRule `json:"company"`
+	Location    SelectorRule `json:"location"`
+	Salary      SelectorRule `json:"salary"`
+	Date        SelectorRule `json:"date"`
+	Link        SelectorRule `json:"link"`
+	Description SelectorRule `json:"description"`
+}
+
+// SiteDefinition describes a job board as data so it can be scraped without site-specific code
+type SiteDefinition struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	SearchURL       string     `json:"search_url"` // template with {query}, {location} and {page} placeholders
+	ListingSelector string     `json:"listing_selector"`
+	Fields          SiteFields `json:"fields"`
+	Pagination      Pagination `json:"pagination"`
+	RateLimit       int        `json:"rate_limit"` // requests per minute
+	Enabled         bool       `json:"enabled"`
+}
+
+// Validate checks that a definition is complete and its selectors and patterns compile
+func (d SiteDefinition) Validate() error {
+	if d.ID == "" {
+		return fmt.Errorf("site id is required")
+	}
+	if d.Name == "" {
+		return fmt.Errorf("site name is required")
+	}
+
+	parsed, err := url.Parse(strings.NewReplacer("{query}", "", "{location}", "", "{page}", "1").Replace(d.SearchURL))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("search_url must be an absolute http(s) URL")
+	}
+
+	if _, err := cascadia.Compile(d.ListingSelector); err != nil || d.ListingSelector == "" {
+		return fmt.Errorf("invalid listing_selector %q", d.ListingSelector)
+	}
+	if d.Fields.Title.Selector == "" && d.Fields.Title.Attr == "" {
+		return fmt.Errorf("a title selector is required")
+	}
+
+	rules := map[string]SelectorRule{
+		"title":       d.Fields.Title,
+		"company":     d.Fields.Company,
+		"location":    d.Fields.Location,
+		"salary":      d.Fields.Salary,
+		"date":        d.Fields.Date,
+		"link":        d.Fields.Link,
+		"description": d.Fields.Description,
+	}
+	for field, rule := range rules {
+		if rule.Selector != "" {
+			if _, err := cascadia.Compile(rule.Selector); err != nil {
+				return fmt.Errorf("invalid %s selector %q: %v", field, rule.Selector, err)
+			}
+		}
+		if rule.Pattern != "" {
+			if _, err := regexp.Compile(rule.Pattern); err != nil {
+				return fmt.Errorf("invalid %s pattern %q: %v", field, rule.Pattern, err)
+			}
+		}
+	}
+
+	if d.Pagination.NextSelector != "" {
+		if _, err := cascadia.Compile(d.Pagination.NextSelector); err != nil {
+			return fmt.Errorf("invalid next_selector %q: %v", d.Pagination.NextSelector, err)
+		}
+	}
+
+	return nil
+}
+
+// SelectorScraper scrapes any job board described by a SiteDefinition
+type SelectorScraper struct {
+	*BaseScraper
+	site SiteDefinition
+}
+
+// NewSelectorScraper creates a scraper for a site definition
+func NewSelectorScraper(site SiteDefinition, client *http.Client) *SelectorScraper {
+	baseURL := site.SearchURL
+	if parsed, err := url.Parse(site.SearchURL); err == nil && parsed.Host != "" {
+		baseURL = parsed.Scheme + "://" + parsed.Host
+	}
+
+	return &SelectorScraper{
+		BaseScraper: NewBaseScraper(site.Name, baseURL, client),
+		site:        site,
+	}
+}
+
+// Scrape implements the JobScraper interface
+func (s *SelectorScraper) Scrape(ctx context.Context, filters models.SearchFilters) ([]models.Job, error) {
+	var jobs []models.Job
+	wanted := wantedJobs(filters)
+
+	err := s.FetchPages(ctx, s.searchURL(filters), s.site.Pagination, filters, func(doc *goquery.Document, pageURL string) (int, bool) {
+		found := 0
+		doc.Find(s.site.ListingSelector).Each(func(i int, listing *goquery.Selection) {
+			job := s.parseListing(listing, pageURL)
+			if job.Title != "" {
+				jobs = append(jobs, job)
+				found++
+			}
+		})
+		return found, wanted > 0 && len(jobs) >= wanted
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", s.Name(), err)
+	}
+
+	return jobs, nil
+}
+
+// searchURL fills in the query and location of the search URL template, leaving {page} to the paginator
+func (s *SelectorScraper) searchURL(filters models.SearchFilters) string {
+	query := filters.JobTitle
+	if len(filters.Keywords) > 0 {
+		query = strings.TrimSpace(query + " " + strings.Join(filters.Keywords, " "))
+	}
+
+	location := filters.Location
+	if location == "" && len(filters.Locations) > 0 {
+		location = filters.Locations[0]
+	}
+
+	return strings.NewReplacer(
+		"{query}", url.QueryEscape(query),
+		"{location}", url.QueryEscape(location),
+	).Replace(s.site.SearchURL)
+}
+
+func (s *SelectorScraper) parseListing(listing *goquery.Selection, pageURL string) models.Job {
+	fields := s.site.Fields
+
+	title := s.extract(listing, fields.Title)
+	company := s.extract(listing, fields.Company)
+	location := s.extract(listing, fields.Location)
+
+	linkRule := fields.Link
+	if linkRule.Attr == "" {
+		linkRule.Attr = "href"
+	}
+	link := s.extract(listing, linkRule)
+	if link != "" {
+		link = resolveURL(pageURL, link)
+	}
+
+	description := s.extract(listing, fields.Description)
+	if description == "" {
+		description = fmt.Sprintf("%s position at %s", title, company)
+	}
+
+	salaryMin, salaryMax, currency := annualSalary(salary.Parse(s.extract(listing, fields.Salary)), "USD")
+
+	// Listings without a link are told apart by title and company
+	id := jobID(s.site.ID, "", link)
+	if link == "" {
+		id = fmt.Sprintf("%s-%s", s.site.ID, shortHash(title+company))
+	}
+
+	return models.Job{
+		ID:              id,
+		Title:           title,
+		Company:         company,
+		Location:        location,
+		Description:     description,
+		Skills:          s.ExtractSkills(title + " " + description),
+		SalaryMin:       salaryMin,
+		SalaryMax:       salaryMax,
+		SalaryCurrency:  currency,
+		DegreeRequired:  s.CheckDegreeRequirement(description),
+		ExperienceLevel: s.DetermineExperienceLevel(title, description),
+		RemoteOption:    detectRemoteOption(location + " " + title),
+		PostedDate:      parsePostedDate(s.extract(listing, fields.Date)),
+		URL:             link,
+		Source:          s.Name(),
+	}
+}
+
+// extract applies a selector rule to a listing
+func (s *SelectorScraper) extract(listing *goquery.Selection, rule SelectorRule) string {
+	if rule.Selector == "" && rule.Attr == "" {
+		return ""
+	}
+
+	selection := listing
+	if rule.Selector != "" {
+		selection = listing.Find(rule.Selector).First()
+	}
+
+	var value string
+	if rule.Attr != "" {
+		value, _ = selection.Attr(rule.Attr)
+		value = strings.TrimSpace(value)
+	} else {
+		value = s.CleanText(selection.Text())
+	}
+
+	if rule.Pattern == "" || value == "" {
+		return value
+	}
+
+	re, err := regexp.Compile(rule.Pattern)
+	if err != nil {
+		return value
+	}
+	match := re.FindStringSubmatch(value)
+	switch {
+	case len(match) >= 2:
+		return strings.TrimSpace(match[1])
+	case len(match) == 1:
+		return match[0]
+	default:
+		return ""
+	}
+}
+
+// resolveURL resolves a possibly relative link against the page it was found on
+func resolveURL(base, link string) string {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return link
+	}
+	ref, err := url.Parse(strings.TrimSpace(link))
+	if err != nil {
+		return link
+	}
+	return baseURL.ResolveReference(ref).String()
+}
+
+// parsePostedDate reads absolute feed-style dates and relative dates like "3 days ago"
+func parsePostedDate(text string) time.Time {
+	if parsed, ok := parseFeedDate(text); ok {
+		return parsed
+	}
+
+	now := time.Now()
+	textLower := strings.ToLower(text)
+
+	if strings.Contains(textLower, "yesterday") {
+		return now.AddDate(0, 0, -1)
+	}
+
+	re := regexp.MustCompile(`(\d+)\s*(minute|min|hour|hr|day|d|week|wk|month|mo)s?\s+ago`)
+	if match := re.FindStringSubmatch(textLower); len(match) >= 3 {
+		n, _ := strconv.Atoi(match[1])
+		switch match[2] {
+		case "minute", "min":
+			return now.Add(-time.Duration(n) * time.Minute)
+		case "hour", "hr":
+			return now.Add(-time.Duration(n) * time.Hour)
+		case "day", "d":
+			return now.AddDate(0, 0, -n)
+		case "week", "wk":
+			return now.AddDate(0, 0, -7*n)
+		case "month", "mo":
+			return now.AddDate(0, -n, 0)
+		}
+	}
+
+	return now
+}
+
+// shortHash returns a short, stable identifier for a string
+func shortHash(value string) string {
+	sum := sha1.Sum([]byte(value))
+	return hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,158 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// SiteStore persists site definitions as a JSON file
+type SiteStore struct {
+	path  string
+	sites map[string]SiteDefinition
+	mu    sync.RWMutex
+}
+
+// NewSiteStore loads the site definitions stored at path; a missing file is an empty store
+func NewSiteStore(path string) (*SiteStore, error) {
+	store := &SiteStore{
+		path:  path,
+		sites: make(map[string]SiteDefinition),
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read site definitions: %w", err)
+	}
+
+	var sites []SiteDefinition
+	if err := json.Unmarshal(data, &sites); err != nil {
+		return nil, fmt.Errorf("failed to parse site definitions %s: %w", path, err)
+	}
+	for _, site := range sites {
+		store.sites[site.ID] = site
+	}
+
+	return store, nil
+}
+
+// List returns all site definitions ordered by ID
+func (s *SiteStore) List() []SiteDefinition {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.listLocked()
+}
+
+// Get returns a site definition by ID
+func (s *SiteStore) Get(id string) (SiteDefinition, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	site, exists := s.sites[id]
+	return site, exists
+}
+
+// Save validates, adds or replaces a site definition and writes the file
+func (s *SiteStore) Save(site SiteDefinition) error {
+	if err := site.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.sites[site.ID]
+	s.sites[site.ID] = site
+	if err := s.writeLocked(); err != nil {
+		if existed {
+			s.sites[site.ID] = previous
+		} else {
+			delete(s.sites, site.ID)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Delete removes a site definition and writes the file
+func (s *SiteStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	site, exists := s.sites[id]
+	if !exists {
+		return fmt.Errorf("site not found: %s", id)
+	}
+
+	delete(s.sites, id)
+	if err := s.writeLocked(); err != nil {
+		s.sites[id] = site
+		return err
+	}
+
+	return nil
+}
+
+func (s *SiteStore) listLocked() []SiteDefinition {
+	sites := make([]SiteDefinition, 0, len(s.sites))
+	for _, site := range s.sites {
+		sites = append(sites, site)
+	}
+	sort.Slice(sites, func(i, j int) bool {
+		return sites[i].ID < sites[j].ID
+	})
+	return sites
+}
+
+// writeLocked writes all definitions atomically via a temporary file. s.mu must be held.
+func (s *SiteStore) writeLocked() error {
+	data, err := json.MarshalIndent(s.listLocked(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode site definitions: %w", err)
+	}
+
+	if dir := filepath.Dir(s.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create directory for site definitions: %w", err)
+		}
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write site definitions: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write site definitions: %w", err)
+	}
+
+	return nil
+}
+
+// RegisterSite adds a site definition to the registry as a "css" scraper
+func (sr *ScraperRegistry) RegisterSite(site SiteDefinition) {
+	sr.AddCustomScraper(site.ID, ScraperConfig{
+		Name:      site.Name,
+		Enabled:   site.Enabled,
+		URL:       site.SearchURL,
+		Type:      "css",
+		RateLimit: site.RateLimit,
+		Site:      &site,
+	})
+}
+
+// RemoveScraper removes a scraper configuration from the registry
+func (sr *ScraperRegistry) RemoveScraper(name string) {
+	sr.mu.Lock()
+	defer sr.mu.Unlock()
+
+	delete(sr.configs, name)
+	delete(sr.stats, name)
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/salary"
 	"github.com/PuerkitoBio/goquery"
 )
 
@@ -25,6 +26,9 @@ func NewWeWorkRemotelyScraper(client *http.Client) *WeWorkRemotelyScraper {
 	}
 }
 
+// wwrPagination follows the "next page" link of a category listing
+var wwrPagination = Pagination{NextSelector: `a[rel="next"], .pagination a.next_page`, MaxPages: 3}
+
 // Scrape implements the JobScraper interface
 func (w *WeWorkRemotelyScraper) Scrape(ctx context.Context, filters models.SearchFilters) ([]models.Job, error) {
 	var jobs []models.Job
@@ -38,35 +42,44 @@ func (w *WeWorkRemotelyScraper) Scrape(ctx context.Context, filters models.Searc
 		"remote-sales-marketing-jobs",
 	}
 
+	wanted := wantedJobs(filters)
 	for _, category := range categories {
-		categoryJobs, err := w.scrapeCategory(ctx, category, filters)
+		categoryJobs, err := w.scrapeCategory(ctx, category, filters, wanted-len(jobs))
 		if err != nil {
 			// Log error but continue with other categories
+			markPartial(ctx)
 			continue
 		}
 		jobs = append(jobs, categoryJobs...)
+
+		if wanted > 0 && len(jobs) >= wanted {
+			markPartial(ctx)
+			break
+		}
 	}
 
 	return jobs, nil
 }
 
-func (w *WeWorkRemotelyScraper) scrapeCategory(ctx context.Context, category string, filters models.SearchFilters) ([]models.Job, error) {
+// scrapeCategory scrapes the pages of one category until wanted jobs match; wanted <= 0 means no limit
+func (w *WeWorkRemotelyScraper) scrapeCategory(ctx context.Context, category string, filters models.SearchFilters, wanted int) ([]models.Job, error) {
 	url := fmt.Sprintf("%s/remote-jobs/%s", w.baseURL, category)
 
-	doc, err := w.FetchDocument(ctx, url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch %s: %w", url, err)
-	}
-
 	var jobs []models.Job
-
-	// Parse job listings
-	doc.Find("article.job").Each(func(i int, s *goquery.Selection) {
-		job := w.parseJobListing(s, category)
-		if job.Title != "" && w.matchesFilters(job, filters) {
-			jobs = append(jobs, job)
-		}
+	err := w.FetchPages(ctx, url, wwrPagination, filters, func(doc *goquery.Document, pageURL string) (int, bool) {
+		// Parse job listings
+		listings := doc.Find("article.job")
+		listings.Each(func(i int, s *goquery.Selection) {
+			job := w.parseJobListing(s, category)
+			if job.Title != "" && w.matchesFilters(job, filters) {
+				jobs = append(jobs, job)
+			}
+		})
+		return listings.Length(), wanted > 0 && len(jobs) >= wanted
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return jobs, nil
 }
@@ -89,7 +102,7 @@ func (w *WeWorkRemotelyScraper) parseJobListing(s *goquery.Selection, category s
 
 	// Extract salary if available
 	salaryText := w.CleanText(s.Find(".salary").Text())
-	salaryMin, salaryMax := w.parseSalary(salaryText)
+	salaryMin, salaryMax, currency := annualSalary(salary.Parse(salaryText), "USD")
 
 	// Extract posted date
 	dateText := w.CleanText(s.Find("time").Text())
@@ -99,13 +112,13 @@ func (w *WeWorkRemotelyScraper) parseJobListing(s *goquery.Selection, category s
 	expLevel := w.DetermineExperienceLevel(title, "")
 
 	// Extract skills from title and category
-	skills := w.extractSkillsFromText(title + " " + category)
+	skills := w.ExtractSkills(title + " " + category)
 
 	// Create job description placeholder
 	description := fmt.Sprintf("Remote %s position at %s", title, company)
 
 	return models.Job{
-		ID:              fmt.Sprintf("wwr-%s-%d", strings.ReplaceAll(strings.ToLower(title), " ", "-"), time.Now().Unix()),
+		ID:              jobID("wwr", lastPathSegment(jobURL), jobURL),
 		Title:           title,
 		Company:         company,
 		Location:        location,
@@ -113,7 +126,7 @@ func (w *WeWorkRemotelyScraper) parseJobListing(s *goquery.Selection, category s
 		Skills:          skills,
 		SalaryMin:       salaryMin,
 		SalaryMax:       salaryMax,
-		SalaryCurrency:  "USD",
+		SalaryCurrency:  currency,
 		DegreeRequired:  false, // WeWorkRemotely jobs often don't require degrees
 		ExperienceLevel: expLevel,
 		RemoteOption:    "remote",
@@ -124,45 +137,9 @@ func (w *WeWorkRemotelyScraper) parseJobListing(s *goquery.Selection, category s
 	}
 }
 
-func (w *WeWorkRemotelyScraper) parseSalary(salaryText string) (int, int) {
-	if salaryText == "" {
-		return 0, 0
-	}
-
-	// Remove currency symbols and clean up
-	cleaned := strings.ReplaceAll(salaryText, "$", "")
-	cleaned = strings.ReplaceAll(cleaned, ",", "")
-	cleaned = strings.ToLower(cleaned)
-
-	// Look for salary ranges like "80k - 120k" or "80000-120000"
-	re := regexp.MustCompile(`(\d+)k?\s*[-–]\s*(\d+)k?`)
-	matches := re.FindStringSubmatch(cleaned)
-
-	if len(matches) >= 3 {
-		min, _ := strconv.Atoi(matches[1])
-		max, _ := strconv.Atoi(matches[2])
-
-		// Convert k to thousands
-		if strings.Contains(matches[0], "k") {
-			min *= 1000
-			max *= 1000
-		}
-
-		return min, max
-	}
-
-	// Look for single salary values
-	re = regexp.MustCompile(`(\d+)k?`)
-	match := re.FindStringSubmatch(cleaned)
-	if len(match) >= 2 {
-		val, _ := strconv.Atoi(match[1])
-		if strings.Contains(match[0], "k") {
-			val *= 1000
-		}
-		return val, val
-	}
-
-	return 0, 0
+// ParseDetail implements DetailScraper, replacing the placeholder description with the job page
+func (w *WeWorkRemotelyScraper) ParseDetail(doc *goquery.Document, job *models.Job) {
+	w.EnrichFromDetail(doc, job, "#job-listing-show-container, .lis-container__job__content__description")
 }
 
 func (w *WeWorkRemotelyScraper) parseDate(dateText string) time.Time {
@@ -199,31 +176,6 @@ func (w *WeWorkRemotelyScraper) parseDate(dateText string) time.Time {
 	return now
 }
 
-func (w *WeWorkRemotelyScraper) extractSkillsFromText(text string) []string {
-	var skills []string
-	skillSet := make(map[string]bool)
-
-	commonSkills := []string{
-		"javascript", "python", "java", "go", "golang", "rust", "php", "ruby", "swift", "kotlin",
-		"react", "angular", "vue", "node.js", "django", "flask", "spring", "express",
-		"mysql", "postgresql", "mongodb", "redis", "elasticsearch",
-		"aws", "azure", "gcp", "docker", "kubernetes", "jenkins",
-		"git", "linux", "sql", "nosql", "api", "rest", "graphql",
-		"devops", "sysadmin", "frontend", "backend", "fullstack", "full-stack",
-		"design", "ui", "ux", "marketing", "sales", "support",
-	}
-
-	textLower := strings.ToLower(text)
-	for _, skill := range commonSkills {
-		if strings.Contains(textLower, skill) && !skillSet[skill] {
-			skills = append(skills, skill)
-			skillSet[skill] = true
-		}
-	}
-
-	return skills
-}
-
 func (w *WeWorkRemotelyScraper) categoryToIndustry(category string) string {
 	switch category {
 	case "remote-programming-jobs", "remote-devops-sysadmin-jobs":
@@ -263,10 +215,7 @@ func (w *WeWorkRemotelyScraper) matchesFilters(job models.Job, filters models.Se
 	}
 
 	// Salary filters
-	if filters.MinSalary > 0 && (job.SalaryMin == 0 || job.SalaryMin < filters.MinSalary) {
-		return false
-	}
-	if filters.MaxSalary > 0 && (job.SalaryMax == 0 || job.SalaryMax > filters.MaxSalary) {
+	if !matchesSalary(job, filters) {
 		return false
 	}
 
@@ -0,0 +1,315 @@
+package skills
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// builtinTaxonomy is the skill taxonomy used when no taxonomy file is configured
+//
+//go:embed taxonomy.json
+var builtinTaxonomy []byte
+
+// Skill is one entry of the taxonomy
+type Skill struct {
+	Name     string   `json:"name"`     // canonical name, e.g. "Go"
+	Category string   `json:"category"` // language, framework, database, cloud, devops, tool, api, data, practice, discipline
+	Aliases  []string `json:"aliases,omitempty"`
+
+	// MatchCase lists spellings only recognized in text with exactly this capitalization,
+	// for skills that are also common words such as "Go" or "REST". Tags and filters
+	// still match them in any case.
+	MatchCase []string `json:"match_case,omitempty"`
+}
+
+// Taxonomy maps skill names and aliases to canonical skills
+type Taxonomy struct {
+	skills    []Skill
+	names     map[string]int // any spelling, lower case
+	text      map[string]int // spellings matched in text regardless of case, lower case
+	textCased map[string]int // spellings matched in text with their exact case
+	maxWords  int
+}
+
+// taxonomyFile is the JSON layout of a taxonomy file
+type taxonomyFile struct {
+	Skills []Skill `json:"skills"`
+}
+
+// NewTaxonomy indexes skills; a spelling may only belong to one skill
+func NewTaxonomy(skills []Skill) (*Taxonomy, error) {
+	t := &Taxonomy{
+		skills:    skills,
+		names:     make(map[string]int),
+		text:      make(map[string]int),
+		textCased: make(map[string]int),
+	}
+
+	for i, skill := range skills {
+		if strings.TrimSpace(skill.Name) == "" {
+			return nil, fmt.Errorf("skill %d has no name", i+1)
+		}
+		if strings.TrimSpace(skill.Category) == "" {
+			return nil, fmt.Errorf("skill %s has no category", skill.Name)
+		}
+
+		cased := make(map[string]bool, len(skill.MatchCase))
+		for _, spelling := range skill.MatchCase {
+			key := phrase(spelling)
+			if err := t.add(t.textCased, key, i); err != nil {
+				return nil, err
+			}
+			cased[strings.ToLower(key)] = true
+		}
+
+		for _, spelling := range append(append([]string{skill.Name}, skill.Aliases...), skill.MatchCase...) {
+			key := strings.ToLower(phrase(spelling))
+			if key == "" {
+				return nil, fmt.Errorf("skill %s has an empty alias", skill.Name)
+			}
+			if err := t.add(t.names, key, i); err != nil {
+				return nil, err
+			}
+			if !cased[key] {
+				if err := t.add(t.text, key, i); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return t, nil
+}
+
+// add indexes a spelling, rejecting spellings of two different skills
+func (t *Taxonomy) add(index map[string]int, key string, skill int) error {
+	if existing, exists := index[key]; exists && existing != skill {
+		return fmt.Errorf("%q is used by both %s and %s", key, t.skills[existing].Name, t.skills[skill].Name)
+	}
+	index[key] = skill
+	t.maxWords = max(t.maxWords, strings.Count(key, " ")+1)
+	return nil
+}
+
+// Skills returns the skills of the taxonomy
+func (t *Taxonomy) Skills() []Skill {
+	return t.skills
+}
+
+// Lookup finds the skill a name or alias belongs to, ignoring case
+func (t *Taxonomy) Lookup(name string) (Skill, bool) {
+	index, exists := t.names[strings.ToLower(phrase(name))]
+	if !exists {
+		return Skill{}, false
+	}
+	return t.skills[index], true
+}
+
+// Canonical returns the canonical name of a skill; names outside the taxonomy are
+// returned trimmed
+func (t *Taxonomy) Canonical(name string) string {
+	if skill, exists := t.Lookup(name); exists {
+		return skill.Name
+	}
+	return strings.TrimSpace(name)
+}
+
+// Canonicalize replaces names with their canonical names and drops duplicates and
+// empty names, keeping the first occurrence
+func (t *Taxonomy) Canonicalize(names []string) []string {
+	if names == nil {
+		return nil
+	}
+
+	result := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		canonical := t.Canonical(name)
+		if canonical == "" || seen[strings.ToLower(canonical)] {
+			continue
+		}
+		seen[strings.ToLower(canonical)] = true
+		result = append(result, canonical)
+	}
+	return result
+}
+
+// Extract finds the skills mentioned in text and returns their canonical names in order
+// of first mention. Only whole words match, and the longest spelling wins, so
+// "React Native" is not also "React".
+func (t *Taxonomy) Extract(text string) []string {
+	words := tokenize(text)
+
+	var found []string
+	seen := make(map[int]bool)
+	for i := 0; i < len(words); {
+		matched := 0
+		for n := min(t.maxWords, len(words)-i); n > 0; n-- {
+			key := strings.Join(words[i:i+n], " ")
+			index, exists := t.textCased[key]
+			if !exists {
+				index, exists = t.text[strings.ToLower(key)]
+			}
+			if !exists {
+				continue
+			}
+
+			if !seen[index] {
+				seen[index] = true
+				found = append(found, t.skills[index].Name)
+			}
+			matched = n
+			break
+		}
+		i += max(matched, 1)
+	}
+
+	return found
+}
+
+// phrase normalizes a spelling to its words separated by single spaces
+func phrase(text string) string {
+	return strings.Join(tokenize(text), " ")
+}
+
+// tokenize splits text into words. Besides letters and digits, a word keeps trailing
+// "+" and "#" ("C++", "C#") and inner or leading dots ("Node.js", ".NET"). Hyphens and
+// slashes separate words, so "full-stack" is "full stack" and "CI/CD" is "CI CD".
+func tokenize(text string) []string {
+	runes := []rune(text)
+
+	var words []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = current[:0]
+		}
+	}
+
+	for i, r := range runes {
+		var next rune
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		switch {
+		case isWordRune(r):
+			// "+" or "#" joining two words ("Java+Spring") belongs to neither
+			if last := len(current) - 1; last >= 0 && isMark(current[last]) && unicode.IsLetter(r) {
+				for len(current) > 0 && isMark(current[len(current)-1]) {
+					current = current[:len(current)-1]
+				}
+				flush()
+			} else if last >= 0 && isMark(current[last]) {
+				flush()
+			}
+			current = append(current, r)
+		case isMark(r):
+			if len(current) > 0 {
+				current = append(current, r)
+			}
+		case r == '.' && isWordRune(next) && (len(current) == 0 || isWordRune(current[len(current)-1])):
+			current = append(current, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	return words
+}
+
+// isWordRune reports whether r is a letter or digit
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isMark reports whether r may end a word, as in "C++" and "C#"
+func isMark(r rune) bool {
+	return r == '+' || r == '#'
+}
+
+// ParseTaxonomy reads a JSON taxonomy of the form {"skills": [...]}
+func ParseTaxonomy(data []byte) (*Taxonomy, error) {
+	var file taxonomyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse skill taxonomy: %w", err)
+	}
+
+	taxonomy, err := NewTaxonomy(file.Skills)
+	if err != nil {
+		return nil, fmt.Errorf("invalid skill taxonomy: %w", err)
+	}
+	return taxonomy, nil
+}
+
+// BuiltinTaxonomy returns the skill taxonomy shipped with the module
+func BuiltinTaxonomy() *Taxonomy {
+	taxonomy, err := ParseTaxonomy(builtinTaxonomy)
+	if err != nil {
+		panic(err)
+	}
+	return taxonomy
+}
+
+// LoadTaxonomy reads the skill taxonomy stored at path; a missing file is the built-in taxonomy
+func LoadTaxonomy(path string) (*Taxonomy, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return BuiltinTaxonomy(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read skill taxonomy: %w", err)
+	}
+
+	taxonomy, err := ParseTaxonomy(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return taxonomy, nil
+}
+
+var (
+	current   = BuiltinTaxonomy()
+	currentMu sync.RWMutex
+)
+
+// Default returns the taxonomy used by scrapers, filters and analytics
+func Default() *Taxonomy {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+// SetDefault replaces the taxonomy used by scrapers, filters and analytics
+func SetDefault(taxonomy *Taxonomy) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = taxonomy
+}
+
+// Extract finds the skills mentioned in text with the default taxonomy
+func Extract(text string) []string {
+	return Default().Extract(text)
+}
+
+// Lookup finds a skill in the default taxonomy
+func Lookup(name string) (Skill, bool) {
+	return Default().Lookup(name)
+}
+
+// Canonical returns the canonical name of a skill in the default taxonomy
+func Canonical(name string) string {
+	return Default().Canonical(name)
+}
+
+// Canonicalize replaces names with their canonical names in the default taxonomy
+func Canonicalize(names []string) []string {
+	return Default().Canonicalize(names)
+}
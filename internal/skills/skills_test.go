@@ -0,0 +1,78 @@
+package skills
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+)
+
+// extractCase is one entry of testdata/extract.json
+type extractCase struct {
+	Text   string   `json:"text"`
+	Skills []string `json:"skills"`
+}
+
+func TestExtractCorpus(t *testing.T) {
+	data, err := os.ReadFile("testdata/extract.json")
+	if err != nil {
+		t.Fatalf("failed to read skill corpus: %v", err)
+	}
+	var cases []extractCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		t.Fatalf("failed to parse skill corpus: %v", err)
+	}
+
+	taxonomy := BuiltinTaxonomy()
+	for _, c := range cases {
+		if found := taxonomy.Extract(c.Text); strings.Join(found, ", ") != strings.Join(c.Skills, ", ") {
+			t.Errorf("%q: got [%s], want [%s]", c.Text, strings.Join(found, ", "), strings.Join(c.Skills, ", "))
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	taxonomy := BuiltinTaxonomy()
+	tests := []struct {
+		name     string
+		want     string
+		category string
+	}{
+		{"golang", "Go", "language"},
+		{"GO", "Go", "language"},
+		{"k8s", "Kubernetes", "devops"},
+		{"postgres", "PostgreSQL", "database"},
+		{" Node.js ", "Node.js", ""},
+	}
+	for _, test := range tests {
+		skill, exists := taxonomy.Lookup(test.name)
+		if !exists || skill.Name != test.want || (test.category != "" && skill.Category != test.category) {
+			t.Errorf("Lookup(%q) = %+v, %v; want %s (%s)", test.name, skill, exists, test.want, test.category)
+		}
+	}
+
+	if skill, exists := taxonomy.Lookup("underwater basket weaving"); exists {
+		t.Errorf("Lookup of an unknown skill found %+v", skill)
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	taxonomy := BuiltinTaxonomy()
+	got := taxonomy.Canonicalize([]string{"golang", "Go", "k8s", "", "  Basket Weaving ", "kubernetes"})
+	if want := "Go,Kubernetes,Basket Weaving"; strings.Join(got, ",") != want {
+		t.Errorf("got %q, want %q", strings.Join(got, ","), want)
+	}
+	if taxonomy.Canonicalize(nil) != nil {
+		t.Errorf("Canonicalize(nil) is not nil")
+	}
+}
+
+func TestNewTaxonomyRejectsSharedAliases(t *testing.T) {
+	_, err := NewTaxonomy([]Skill{
+		{Name: "Go", Category: "language", Aliases: []string{"golang"}},
+		{Name: "Golang Tools", Category: "tool", Aliases: []string{"Golang"}},
+	})
+	if err == nil {
+		t.Error("an alias of two skills was accepted")
+	}
+}
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/skills"
+)
+
+// salaryValue is the value of the salary field in a FieldChange
+type salaryValue struct {
+	Min      int    `json:"min"`
+	Max      int    `json:"max"`
+	Currency string `json:"currency,omitempty"`
+}
+
+// trackedFields are the fields of a job whose changes are recorded, by their JSON names.
+// Each value is normalized so that an equal value read back from storage compares equal.
+var trackedFields = []struct {
+	name  string
+	value func(job models.Job) interface{}
+}{
+	{"title", func(job models.Job) interface{} { return job.Title }},
+	{"company", func(job models.Job) interface{} { return job.Company }},
+	{"location", func(job models.Job) interface{} { return job.Location }},
+	{"description", func(job models.Job) interface{} { return job.Description }},
+	{"requirements", func(job models.Job) interface{} { return listValue(job.Requirements) }},
+	{"skills", func(job models.Job) interface{} {
+		names := skills.Canonicalize(job.Skills)
+		sort.Strings(names)
+		return listValue(names)
+	}},
+	{"salary", func(job models.Job) interface{} {
+		if job.SalaryMin == 0 && job.SalaryMax == 0 {
+			return nil
+		}
+		return salaryValue{Min: job.SalaryMin, Max: job.SalaryMax, Currency: job.SalaryCurrency}
+	}},
+	{"experience_level", func(job models.Job) interface{} { return job.ExperienceLevel }},
+	{"remote_option", func(job models.Job) interface{} { return job.RemoteOption }},
+	{"degree_required", func(job models.Job) interface{} { return job.DegreeRequired }},
+	{"employment_type", func(job models.Job) interface{} { return job.EmploymentType }},
+	{"valid_through", func(job models.Job) interface{} {
+		if job.ValidThrough == nil {
+			return nil
+		}
+		return job.ValidThrough.UTC().Round(0)
+	}},
+	{"benefits", func(job models.Job) interface{} { return listValue(job.Benefits) }},
+	{"company_size", func(job models.Job) interface{} { return job.CompanySize }},
+	{"industry", func(job models.Job) interface{} { return job.Industry }},
+	{"url", func(job models.Job) interface{} { return job.URL }},
+}
+
+// listValue treats an empty list like a missing one
+func listValue(list []string) interface{} {
+	if len(list) == 0 {
+		return nil
+	}
+	return list
+}
+
+// diffJobs returns the tracked fields that differ between two versions of a job
+func diffJobs(before, after models.Job) []models.FieldChange {
+	var changes []models.FieldChange
+	for _, field := range trackedFields {
+		old, current := field.value(before), field.value(after)
+		if !reflect.DeepEqual(old, current) {
+			changes = append(changes, models.FieldChange{Field: field.name, Old: old, New: current})
+		}
+	}
+	return changes
+}
+
+// recordChanges compares a stored job before and after an update by a listing from
+// source, and notes on the job when each changed field changed. It returns the revision,
+// or nil when no tracked field changed.
+func recordChanges(before models.Job, after *models.Job, source string, now time.Time) *models.JobRevision {
+	changes := diffJobs(before, *after)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	// Copied so that the version before the update keeps its own map
+	changed := make(map[string]time.Time, len(after.FieldsChanged)+len(changes))
+	for field, at := range after.FieldsChanged {
+		changed[field] = at
+	}
+	for _, change := range changes {
+		changed[change.Field] = now
+	}
+	after.FieldsChanged = changed
+
+	return &models.JobRevision{ChangedAt: now, Source: source, Changes: changes}
+}
+
+// changedField is the tracked field a filter name refers to; the salary columns change
+// together as "salary"
+func changedField(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if strings.HasPrefix(name, "salary") {
+		return "salary"
+	}
+	return name
+}
+
+// matchesChanges checks a job against the recently changed filters: one of the
+// requested fields, or any field when none are requested, changed within the period
+func matchesChanges(job models.Job, filters models.SearchFilters, now time.Time) bool {
+	if filters.ChangedWithinDays <= 0 && len(filters.ChangedFields) == 0 {
+		return true
+	}
+
+	var since time.Time
+	if filters.ChangedWithinDays > 0 {
+		since = now.AddDate(0, 0, -filters.ChangedWithinDays)
+	}
+
+	if len(filters.ChangedFields) == 0 {
+		for _, at := range job.FieldsChanged {
+			if !at.Before(since) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, field := range filters.ChangedFields {
+		if at, changed := job.FieldsChanged[changedField(field)]; changed && !at.Before(since) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,223 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/skills"
+)
+
+// DefaultClosedAfterMisses is how many consecutive scrapes of its sources must not list
+// a job before it is marked closed
+const DefaultClosedAfterMisses = 3
+
+// markSeen records that a scrape found the job. A closed job found again is open again
+// and counts as reposted.
+func markSeen(job *models.Job, now time.Time) {
+	if job.FirstSeen.IsZero() {
+		job.FirstSeen = now
+	}
+	job.LastSeen = now
+	job.MissedScrapes = 0
+	if job.Status == models.JobStatusClosed {
+		job.Reposts++
+		job.ClosedAt = nil
+	}
+	job.Status = models.JobStatusOpen
+}
+
+// markMissed records that a complete scrape of one of the job's sources did not list it,
+// closing the job after closeAfter consecutive misses. It reports whether the job changed.
+func markMissed(job *models.Job, now time.Time, closeAfter int) bool {
+	if closeAfter <= 0 || job.Status == models.JobStatusClosed {
+		return false
+	}
+
+	job.MissedScrapes++
+	if job.MissedScrapes >= closeAfter {
+		closed := now
+		job.Status = models.JobStatusClosed
+		job.ClosedAt = &closed
+	}
+	return true
+}
+
+// reposted reports whether listing is a new posting of a closed job from the same source,
+// matched as a duplicate because the source gave it a new ID and URL
+func reposted(stored, listing models.Job) bool {
+	return stored.Status == models.JobStatusClosed && strings.EqualFold(stored.Source, listing.Source)
+}
+
+// foundListings holds the IDs and URLs a scrape of one source returned
+type foundListings struct {
+	ids  map[string]bool
+	urls map[string]bool
+}
+
+// newFoundListings indexes the jobs of a scrape
+func newFoundListings(jobs []models.Job) foundListings {
+	found := foundListings{ids: make(map[string]bool, len(jobs)), urls: make(map[string]bool, len(jobs))}
+	for _, job := range jobs {
+		if job.ID != "" {
+			found.ids[job.ID] = true
+		}
+		found.urls[job.URL] = true
+	}
+	return found
+}
+
+// missedBy reports whether the job is listed on source but the scrape returned none of
+// its listings there
+func (f foundListings) missedBy(job models.Job, source string) bool {
+	listings := append([]models.JobSource{{Source: job.Source, URL: job.URL, ID: job.ID}}, job.AlsoOn...)
+
+	listed := false
+	for _, listing := range listings {
+		if !strings.EqualFold(listing.Source, source) {
+			continue
+		}
+		listed = true
+		if (listing.ID != "" && f.ids[listing.ID]) || f.urls[listing.URL] {
+			return false
+		}
+	}
+	return listed
+}
+
+// scrapeScope returns the filters a scrape searched with, without those about stored
+// jobs. Only jobs within the scope of a scrape can be missed by it.
+func scrapeScope(filters models.SearchFilters) models.SearchFilters {
+	filters.Status = ""
+	filters.ClosedWithinDays = 0
+	filters.OpenLongerThanDays = 0
+	filters.MinReposts = 0
+	filters.ChangedWithinDays = 0
+	filters.ChangedFields = nil
+	filters.Limit = 0
+	filters.Offset = 0
+	return filters
+}
+
+// currentStatus is the status of a job at now. An open job past its ValidThrough date is
+// expired, and jobs that were never stored are open.
+func currentStatus(job models.Job, now time.Time) string {
+	if job.Status == models.JobStatusClosed {
+		return models.JobStatusClosed
+	}
+	if job.ValidThrough != nil && job.ValidThrough.Before(now) {
+		return models.JobStatusExpired
+	}
+	return models.JobStatusOpen
+}
+
+// openedAt is when a job went up: its posting date, or when it was first seen if that is
+// earlier or the posting date is unknown
+func openedAt(job models.Job) time.Time {
+	if job.PostedDate.IsZero() || (!job.FirstSeen.IsZero() && job.FirstSeen.Before(job.PostedDate)) {
+		return job.FirstSeen
+	}
+	return job.PostedDate
+}
+
+// matchesLifecycle checks a job against the status, closing and repost filters
+func matchesLifecycle(job models.Job, filters models.SearchFilters, now time.Time) bool {
+	status := currentStatus(job, now)
+
+	if filters.Status != "" && !strings.EqualFold(status, filters.Status) {
+		return false
+	}
+
+	if filters.ClosedWithinDays > 0 {
+		if status != models.JobStatusClosed || job.ClosedAt == nil || job.ClosedAt.Before(now.AddDate(0, 0, -filters.ClosedWithinDays)) {
+			return false
+		}
+	}
+
+	if filters.OpenLongerThanDays > 0 {
+		opened := openedAt(job)
+		if status != models.JobStatusOpen || opened.IsZero() || opened.After(now.AddDate(0, 0, -filters.OpenLongerThanDays)) {
+			return false
+		}
+	}
+
+	if filters.MinReposts > 0 && job.Reposts < filters.MinReposts {
+		return false
+	}
+
+	return true
+}
+
+// calculateTimeToFill summarizes how long the closed jobs stayed open, overall and for the
+// companies and skills with the most closed jobs. It returns nil without closed jobs.
+func calculateTimeToFill(jobs []models.Job, limit int) *models.TimeToFill {
+	var all []float64
+	byCompany := make(map[string][]float64)
+	bySkill := make(map[string][]float64)
+
+	for _, job := range jobs {
+		opened := openedAt(job)
+		if job.Status != models.JobStatusClosed || opened.IsZero() || job.LastSeen.Before(opened) {
+			continue
+		}
+
+		days := job.LastSeen.Sub(opened).Hours() / 24
+		all = append(all, days)
+		if job.Company != "" {
+			byCompany[job.Company] = append(byCompany[job.Company], days)
+		}
+		for _, skill := range skills.Canonicalize(job.Skills) {
+			bySkill[skill] = append(bySkill[skill], days)
+		}
+	}
+	if len(all) == 0 {
+		return nil
+	}
+
+	average, median := fillStats(all)
+	return &models.TimeToFill{
+		ClosedJobs:  len(all),
+		AverageDays: average,
+		MedianDays:  median,
+		ByCompany:   groupFillTimes(byCompany, limit),
+		BySkill:     groupFillTimes(bySkill, limit),
+	}
+}
+
+// groupFillTimes returns the fill times of the groups with the most closed jobs
+func groupFillTimes(groups map[string][]float64, limit int) []models.GroupFillTime {
+	result := make([]models.GroupFillTime, 0, len(groups))
+	for name, days := range groups {
+		average, median := fillStats(days)
+		result = append(result, models.GroupFillTime{
+			Name:        name,
+			ClosedJobs:  len(days),
+			AverageDays: average,
+			MedianDays:  median,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ClosedJobs != result[j].ClosedJobs {
+			return result[i].ClosedJobs > result[j].ClosedJobs
+		}
+		return result[i].Name < result[j].Name
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// fillStats returns the average and median of durations in days
+func fillStats(days []float64) (float64, float64) {
+	sorted := append([]float64(nil), days...)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, d := range sorted {
+		sum += d
+	}
+	return sum / float64(len(sorted)), percentile(sorted, 50)
+}
@@ -4,8 +4,13 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/Illuminateee/web-scrapper.git/internal/currency"
+	"github.com/Illuminateee/web-scrapper.git/internal/dedupe"
+	"github.com/Illuminateee/web-scrapper.git/internal/fulltext"
 	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/skills"
 )
 
 // JobStorage interface defines methods for job storage
@@ -14,42 +19,146 @@ type JobStorage interface {
 	Search(filters models.SearchFilters) (*models.SearchResponse, error)
 	Clear() error
 	GetAnalytics(jobs []models.Job) models.JobAnalytics
+
+	// RecordScrape records a complete scrape of source with filters that returned found.
+	// Open jobs listed on source within the filters that were not found count a miss.
+	RecordScrape(source string, filters models.SearchFilters, found []models.Job) error
+
+	// Get returns the job with the ID, or the job a listing with the ID or URL was merged
+	// into; nil when there is no such job
+	Get(id string) (*models.Job, error)
+
+	// History returns the revisions of the job with the ID, or of the job a listing with
+	// the ID was merged into; nil when there is no such job
+	History(id string) (*models.JobHistory, error)
 }
 
 // InMemoryStorage implements JobStorage using in-memory storage
 type InMemoryStorage struct {
-	jobs []models.Job
-	mu   sync.RWMutex
+	jobs         []models.Job
+	fingerprints []dedupe.Fingerprint         // parallel to jobs
+	blocks       map[string][]int             // indexes of jobs by fingerprint block
+	ids          map[string]int               // indexes of jobs by their ID and their merged duplicates' IDs
+	urls         map[string]int               // indexes of jobs by their URL and their merged duplicates' URLs
+	history      map[int][]models.JobRevision // revisions of jobs by index
+	fullText     *fulltext.Index              // text of jobs by index
+	matcher      *dedupe.Matcher
+	closeAfter   int // consecutive missed scrapes after which a job is closed
+	mu           sync.RWMutex
 }
 
 // NewInMemoryStorage creates a new in-memory storage instance
 func NewInMemoryStorage() *InMemoryStorage {
 	return &InMemoryStorage{
-		jobs: make([]models.Job, 0),
+		jobs:       make([]models.Job, 0),
+		blocks:     make(map[string][]int),
+		ids:        make(map[string]int),
+		urls:       make(map[string]int),
+		history:    make(map[int][]models.JobRevision),
+		fullText:   fulltext.NewIndex(),
+		matcher:    dedupe.NewMatcher(dedupe.DefaultThresholds),
+		closeAfter: DefaultClosedAfterMisses,
 	}
 }
 
-// Store saves jobs to memory
-func (s *InMemoryStorage) Store(jobs []models.Job) error {
+// SetDuplicateThresholds changes when jobs from different sources are merged as duplicates.
+// Jobs stored before keep their merges.
+func (s *InMemoryStorage) SetDuplicateThresholds(thresholds dedupe.Thresholds) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	s.matcher = dedupe.NewMatcher(thresholds)
+}
 
-	// Add new jobs, avoiding duplicates by URL
-	existingURLs := make(map[string]bool)
-	for _, job := range s.jobs {
-		existingURLs[job.URL] = true
-	}
+// SetClosedAfterMisses changes how many consecutive scrapes must miss a job before it is
+// closed; zero never closes jobs
+func (s *InMemoryStorage) SetClosedAfterMisses(misses int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeAfter = misses
+}
+
+// Store saves jobs to memory. A job already stored, by ID or URL, is updated; a listing of
+// a stored job on another source is merged into it, and a closed job posted again by its
+// source is reopened as a repost; other jobs are added.
+func (s *InMemoryStorage) Store(jobs []models.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
+	now := time.Now()
 	for _, job := range jobs {
-		if !existingURLs[job.URL] {
+		index, found := s.ids[job.ID]
+		if !found {
+			index, found = s.urls[job.URL]
+		}
+
+		listed := found && dedupe.Listed(s.jobs[index], job)
+		if !listed {
+			index, found = s.findDuplicate(job, dedupe.NewFingerprint(job))
+		}
+
+		if found {
+			before := s.jobs[index]
+			if listed || reposted(before, job) {
+				dedupe.Refresh(&s.jobs[index], job)
+			} else {
+				dedupe.Merge(&s.jobs[index], job)
+			}
+			if revision := recordChanges(before, &s.jobs[index], job.Source, now); revision != nil {
+				s.history[index] = append(s.history[index], *revision)
+			}
+		} else {
 			s.jobs = append(s.jobs, job)
-			existingURLs[job.URL] = true
+			index = len(s.jobs) - 1
+		}
+
+		markSeen(&s.jobs[index], now)
+		if job.ID != "" {
+			s.ids[job.ID] = index
 		}
+		s.urls[job.URL] = index
+		s.reindex(index)
+		s.fullText.Add(index, s.jobs[index])
 	}
 
 	return nil
 }
 
+// reindex updates the fingerprint of the job at index after it was added or changed
+func (s *InMemoryStorage) reindex(index int) {
+	fingerprint := dedupe.NewFingerprint(s.jobs[index])
+	if index < len(s.fingerprints) {
+		previous := s.fingerprints[index]
+		s.fingerprints[index] = fingerprint
+		if previous.Block == fingerprint.Block {
+			return
+		}
+	} else {
+		s.fingerprints = append(s.fingerprints, fingerprint)
+	}
+
+	// A job whose company changed stays listed under its old block too; candidates are
+	// compared by their current fingerprint, so that only costs a comparison
+	if fingerprint.Block != "" {
+		s.blocks[fingerprint.Block] = append(s.blocks[fingerprint.Block], index)
+	}
+}
+
+// findDuplicate returns the index of the stored job that best matches job from another
+// source, or that job's source closed before posting it again
+func (s *InMemoryStorage) findDuplicate(job models.Job, fingerprint dedupe.Fingerprint) (int, bool) {
+	best, bestScore := -1, 0.0
+	for _, index := range s.blocks[fingerprint.Block] {
+		if dedupe.HasSource(s.jobs[index], job.Source) && !reposted(s.jobs[index], job) {
+			continue
+		}
+		match := s.matcher.Compare(s.fingerprints[index], fingerprint)
+		if match.Duplicate && match.Score() > bestScore {
+			best, bestScore = index, match.Score()
+		}
+	}
+	return best, best >= 0
+}
+
 // Search filters and returns jobs based on criteria
 func (s *InMemoryStorage) Search(filters models.SearchFilters) (*models.SearchResponse, error) {
 	s.mu.RLock()
@@ -57,34 +166,34 @@ func (s *InMemoryStorage) Search(filters models.SearchFilters) (*models.SearchRe
 
 	var filteredJobs []models.Job
 
-	for _, job := range s.jobs {
-		if s.matchesFilters(job, filters) {
+	// The index finds the jobs with the keywords, so the other filters are checked without them
+	matching, searched := s.fullText.MatchingKeywords(filters.Keywords)
+	rest := filters
+	rest.Keywords = nil
+
+	query := textQuery(filters)
+	scores := s.fullText.Scores(query)
+
+	now := time.Now()
+	for index, job := range s.jobs {
+		if searched && !matching[index] {
+			continue
+		}
+		job.Status = currentStatus(job, now)
+		if matchesFilters(job, rest) {
+			job.Score = scores[index]
 			filteredJobs = append(filteredJobs, job)
 		}
 	}
 
-	// Sort by posted date (newest first)
-	sort.Slice(filteredJobs, func(i, j int) bool {
-		return filteredJobs[i].PostedDate.After(filteredJobs[j].PostedDate)
-	})
+	sortJobs(filteredJobs, filters, query)
 
 	total := len(filteredJobs)
 
 	// Apply pagination
-	start := filters.Offset
-	end := start + filters.Limit
-	if filters.Limit == 0 {
-		end = total
-	}
-	if start > total {
-		start = total
-	}
-	if end > total {
-		end = total
-	}
-
-	paginatedJobs := filteredJobs[start:end]
-	analytics := s.GetAnalytics(filteredJobs)
+	paginatedJobs := paginate(filteredJobs, filters)
+	highlightJobs(paginatedJobs, query)
+	analytics := calculateAnalytics(filteredJobs, filters.SalaryCurrency)
 
 	return &models.SearchResponse{
 		Jobs:      paginatedJobs,
@@ -94,16 +203,104 @@ func (s *InMemoryStorage) Search(filters models.SearchFilters) (*models.SearchRe
 	}, nil
 }
 
+// RecordScrape counts a miss for the open jobs listed on source within filters that the
+// scrape did not return
+func (s *InMemoryStorage) RecordScrape(source string, filters models.SearchFilters, found []models.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	listings := newFoundListings(found)
+	scope := scrapeScope(filters)
+	for i := range s.jobs {
+		if listings.missedBy(s.jobs[i], source) && matchesFilters(s.jobs[i], scope) {
+			markMissed(&s.jobs[i], now, s.closeAfter)
+		}
+	}
+	return nil
+}
+
+// Get returns a stored job, found by its ID or URL or those of a merged listing
+func (s *InMemoryStorage) Get(id string) (*models.Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if id == "" {
+		return nil, nil
+	}
+	index, found := s.ids[id]
+	if !found {
+		index, found = s.urls[id]
+	}
+	if !found {
+		return nil, nil
+	}
+	job := s.jobs[index]
+	job.Status = currentStatus(job, time.Now())
+	return &job, nil
+}
+
+// History returns the revisions of a stored job, found by its ID or a merged listing's ID
+func (s *InMemoryStorage) History(id string) (*models.JobHistory, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	index, found := s.ids[id]
+	if !found {
+		return nil, nil
+	}
+	return &models.JobHistory{
+		JobID:     s.jobs[index].ID,
+		Revisions: append([]models.JobRevision{}, s.history[index]...),
+	}, nil
+}
+
 // Clear removes all jobs from storage
 func (s *InMemoryStorage) Clear() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.jobs = make([]models.Job, 0)
+	s.fingerprints = nil
+	s.blocks = make(map[string][]int)
+	s.ids = make(map[string]int)
+	s.urls = make(map[string]int)
+	s.history = make(map[int][]models.JobRevision)
+	s.fullText.Clear()
 	return nil
 }
 
+// FilterJobs returns the jobs that match the search filters, ignoring pagination
+func FilterJobs(jobs []models.Job, filters models.SearchFilters) []models.Job {
+	var filteredJobs []models.Job
+	for _, job := range jobs {
+		if matchesFilters(job, filters) {
+			filteredJobs = append(filteredJobs, job)
+		}
+	}
+	return filteredJobs
+}
+
+// paginate applies the offset and limit from the filters to a sorted job list
+func paginate(jobs []models.Job, filters models.SearchFilters) []models.Job {
+	total := len(jobs)
+
+	start := filters.Offset
+	end := start + filters.Limit
+	if filters.Limit == 0 {
+		end = total
+	}
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+
+	return jobs[start:end]
+}
+
 // matchesFilters checks if a job matches the search filters
-func (s *InMemoryStorage) matchesFilters(job models.Job, filters models.SearchFilters) bool {
+func matchesFilters(job models.Job, filters models.SearchFilters) bool {
 	// Job title filter
 	if filters.JobTitle != "" {
 		if !strings.Contains(strings.ToLower(job.Title), strings.ToLower(filters.JobTitle)) {
@@ -111,19 +308,9 @@ func (s *InMemoryStorage) matchesFilters(job models.Job, filters models.SearchFi
 		}
 	}
 
-	// Keywords filter
-	if len(filters.Keywords) > 0 {
-		jobText := strings.ToLower(job.Title + " " + job.Description + " " + strings.Join(job.Skills, " "))
-		found := false
-		for _, keyword := range filters.Keywords {
-			if strings.Contains(jobText, strings.ToLower(keyword)) {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return false
-		}
+	// Keywords filter, matching words in any form
+	if len(filters.Keywords) > 0 && !fulltext.MatchesKeywords(job, filters.Keywords) {
+		return false
 	}
 
 	// Location filter (single location)
@@ -152,11 +339,22 @@ func (s *InMemoryStorage) matchesFilters(job models.Job, filters models.SearchFi
 		return false
 	}
 
-	// Salary filters
-	if filters.MinSalary > 0 && (job.SalaryMin == 0 || job.SalaryMin < filters.MinSalary) {
-		return false
+	// Salary filters, compared in the requested currency
+	if filters.MinSalary > 0 || filters.MaxSalary > 0 {
+		salaryMin, salaryMax, ok := currency.ConvertRange(job.SalaryMin, job.SalaryMax, job.SalaryCurrency, filters.SalaryCurrency)
+		if !ok {
+			return false
+		}
+		if filters.MinSalary > 0 && (salaryMin == 0 || salaryMin < filters.MinSalary) {
+			return false
+		}
+		if filters.MaxSalary > 0 && (salaryMax == 0 || salaryMax > filters.MaxSalary) {
+			return false
+		}
 	}
-	if filters.MaxSalary > 0 && (job.SalaryMax == 0 || job.SalaryMax > filters.MaxSalary) {
+
+	// Recency filter
+	if filters.PostedWithinDays > 0 && job.PostedDate.Before(time.Now().AddDate(0, 0, -filters.PostedWithinDays)) {
 		return false
 	}
 
@@ -174,40 +372,58 @@ func (s *InMemoryStorage) matchesFilters(job models.Job, filters models.SearchFi
 		}
 	}
 
-	// Skills filter
+	// Skills filter, by canonical name so "golang" finds "Go"
 	if len(filters.Skills) > 0 {
 		jobSkills := make(map[string]bool)
 		for _, skill := range job.Skills {
-			jobSkills[strings.ToLower(skill)] = true
+			jobSkills[strings.ToLower(skills.Canonical(skill))] = true
 		}
 
 		for _, requiredSkill := range filters.Skills {
-			if !jobSkills[strings.ToLower(requiredSkill)] {
+			if !jobSkills[strings.ToLower(skills.Canonical(requiredSkill))] {
 				return false
 			}
 		}
 	}
 
-	return true
+	// Recently changed filter
+	if !matchesChanges(job, filters, time.Now()) {
+		return false
+	}
+
+	// Status, closing and repost filters
+	return matchesLifecycle(job, filters, time.Now())
 }
 
-// GetAnalytics calculates analytics from job data
+// GetAnalytics calculates analytics from job data, with salaries in the base currency
 func (s *InMemoryStorage) GetAnalytics(jobs []models.Job) models.JobAnalytics {
+	return calculateAnalytics(jobs, "")
+}
+
+// calculateAnalytics builds analytics for a set of jobs, shared by all storage backends.
+// Salaries are converted to salaryCurrency; jobs paid in a currency without an exchange
+// rate are left out of the salary figures.
+func calculateAnalytics(jobs []models.Job, salaryCurrency string) models.JobAnalytics {
 	if len(jobs) == 0 {
 		return models.JobAnalytics{}
 	}
 
+	rates := currency.Rates()
+	now := time.Now()
 	analytics := models.JobAnalytics{
+		SalaryCurrency:       rates.Resolve(salaryCurrency),
 		TotalJobs:            len(jobs),
 		ExperienceLevels:     make(map[string]int),
 		RemoteOptions:        make(map[string]int),
 		DegreeRequirements:   make(map[string]int),
 		LocationDistribution: make(map[string]int),
 		IndustryDistribution: make(map[string]int),
+		StatusDistribution:   make(map[string]int),
 	}
 
 	skillCounts := make(map[string]int)
 	companyCounts := make(map[string]int)
+	repostCounts := make(map[string]int)
 	salaries := make([]float64, 0)
 
 	for _, job := range jobs {
@@ -238,24 +454,35 @@ func (s *InMemoryStorage) GetAnalytics(jobs []models.Job) models.JobAnalytics {
 			analytics.IndustryDistribution[job.Industry]++
 		}
 
-		// Skills counting
-		for _, skill := range job.Skills {
+		// Skills counting, once per job and canonical name
+		for _, skill := range skills.Canonicalize(job.Skills) {
 			skillCounts[skill]++
 		}
 
 		// Company counting
 		if job.Company != "" {
 			companyCounts[job.Company]++
+			if job.Reposts > 0 {
+				repostCounts[job.Company] += job.Reposts
+			}
 		}
 
+		// Lifecycle status
+		analytics.StatusDistribution[currentStatus(job, now)]++
+
 		// Salary calculation
+		var jobSalary float64
 		if job.SalaryMin > 0 && job.SalaryMax > 0 {
-			avgSalary := float64(job.SalaryMin+job.SalaryMax) / 2
-			salaries = append(salaries, avgSalary)
+			jobSalary = float64(job.SalaryMin+job.SalaryMax) / 2
 		} else if job.SalaryMin > 0 {
-			salaries = append(salaries, float64(job.SalaryMin))
+			jobSalary = float64(job.SalaryMin)
 		} else if job.SalaryMax > 0 {
-			salaries = append(salaries, float64(job.SalaryMax))
+			jobSalary = float64(job.SalaryMax)
+		}
+		if jobSalary > 0 {
+			if converted, ok := rates.Convert(jobSalary, job.SalaryCurrency, analytics.SalaryCurrency); ok {
+				salaries = append(salaries, converted)
+			}
 		}
 	}
 
@@ -284,6 +511,12 @@ func (s *InMemoryStorage) GetAnalytics(jobs []models.Job) models.JobAnalytics {
 	// Top companies
 	analytics.TopCompanies = getTopCompanies(companyCounts, 10)
 
+	// Time to fill of closed jobs, and the companies that post the same jobs again
+	analytics.TimeToFill = calculateTimeToFill(jobs, 10)
+	if len(repostCounts) > 0 {
+		analytics.TopReposters = getTopCompanies(repostCounts, 10)
+	}
+
 	return analytics
 }
 
@@ -330,10 +563,14 @@ func getTopCounts(counts map[string]int, limit int) []models.SkillCount {
 		if i >= limit {
 			break
 		}
-		result = append(result, models.SkillCount{
+		skillCount := models.SkillCount{
 			Skill: pair.skill,
 			Count: pair.count,
-		})
+		}
+		if skill, exists := skills.Lookup(pair.skill); exists {
+			skillCount.Category = skill.Category
+		}
+		result = append(result, skillCount)
 	}
 
 	return result
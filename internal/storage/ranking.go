@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/currency"
+	"github.com/Illuminateee/web-scrapper.git/internal/fulltext"
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+)
+
+// snippetLength is about how many bytes of the description a highlighted snippet shows
+const snippetLength = 200
+
+// textQuery is what a search looks for in the text of jobs: the job title and keywords
+func textQuery(filters models.SearchFilters) fulltext.Query {
+	return fulltext.ParseQuery(filters.JobTitle + " " + strings.Join(filters.Keywords, " "))
+}
+
+// sortOrder is how search results are ordered: as requested, or by relevance when there
+// is text to search for and by date otherwise
+func sortOrder(filters models.SearchFilters, query fulltext.Query) string {
+	switch strings.ToLower(filters.Sort) {
+	case models.SortDate:
+		return models.SortDate
+	case models.SortSalary:
+		return models.SortSalary
+	}
+	if query.Empty() {
+		return models.SortDate
+	}
+	return models.SortRelevance
+}
+
+// sortJobs orders search results scored against query. Ties are broken by posted date,
+// newest first.
+func sortJobs(jobs []models.Job, filters models.SearchFilters, query fulltext.Query) {
+	newer := func(i, j int) bool {
+		return jobs[i].PostedDate.After(jobs[j].PostedDate)
+	}
+
+	switch sortOrder(filters, query) {
+	case models.SortRelevance:
+		sort.SliceStable(jobs, func(i, j int) bool {
+			if jobs[i].Score != jobs[j].Score {
+				return jobs[i].Score > jobs[j].Score
+			}
+			return newer(i, j)
+		})
+	case models.SortSalary:
+		// Highest salary first in the requested currency; jobs without one come last
+		sort.SliceStable(jobs, func(i, j int) bool {
+			salaryI := salaryMidpoint(jobs[i], filters.SalaryCurrency)
+			salaryJ := salaryMidpoint(jobs[j], filters.SalaryCurrency)
+			if salaryI != salaryJ {
+				return salaryI > salaryJ
+			}
+			return newer(i, j)
+		})
+	default:
+		sort.SliceStable(jobs, newer)
+	}
+}
+
+// salaryMidpoint is the middle of a job's salary range in the currency, or 0 when the
+// salary is unknown or cannot be converted
+func salaryMidpoint(job models.Job, to string) float64 {
+	salaryMin, salaryMax, ok := currency.ConvertRange(job.SalaryMin, job.SalaryMax, job.SalaryCurrency, to)
+	if !ok {
+		return 0
+	}
+	switch {
+	case salaryMin > 0 && salaryMax > 0:
+		return float64(salaryMin+salaryMax) / 2
+	case salaryMin > 0:
+		return float64(salaryMin)
+	default:
+		return float64(salaryMax)
+	}
+}
+
+// highlightJobs marks where the query matched the title and description of each job
+func highlightJobs(jobs []models.Job, query fulltext.Query) {
+	if query.Empty() {
+		return
+	}
+	for i := range jobs {
+		highlight := models.JobHighlight{
+			Title:   fulltext.Highlight(jobs[i].Title, query),
+			Snippet: fulltext.Snippet(jobs[i].Description, query, snippetLength),
+		}
+		if highlight.Title != "" || highlight.Snippet != "" {
+			jobs[i].Highlight = &highlight
+		}
+	}
+}
@@ -0,0 +1,1001 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/dedupe"
+	"github.com/Illuminateee/web-scrapper.git/internal/fulltext"
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+	"github.com/Illuminateee/web-scrapper.git/internal/skills"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// migrations are applied in order; the index of each entry + 1 is its schema version
+var migrations = []string{
+	// 1: jobs table and indexes for the columns used by matchesFilters
+	`CREATE TABLE IF NOT EXISTS jobs (
+		pk               INTEGER PRIMARY KEY AUTOINCREMENT,
+		id               TEXT NOT NULL,
+		title            TEXT NOT NULL DEFAULT '',
+		company          TEXT NOT NULL DEFAULT '',
+		location         TEXT NOT NULL DEFAULT '',
+		description      TEXT NOT NULL DEFAULT '',
+		requirements     TEXT NOT NULL DEFAULT '[]',
+		skills           TEXT NOT NULL DEFAULT '[]',
+		salary_min       INTEGER NOT NULL DEFAULT 0,
+		salary_max       INTEGER NOT NULL DEFAULT 0,
+		salary_currency  TEXT NOT NULL DEFAULT '',
+		degree_required  INTEGER NOT NULL DEFAULT 0,
+		experience_level TEXT NOT NULL DEFAULT '',
+		remote_option    TEXT NOT NULL DEFAULT '',
+		posted_date      INTEGER NOT NULL DEFAULT 0,
+		url              TEXT NOT NULL UNIQUE,
+		source           TEXT NOT NULL DEFAULT '',
+		company_size     TEXT NOT NULL DEFAULT '',
+		industry         TEXT NOT NULL DEFAULT '',
+		benefits         TEXT NOT NULL DEFAULT '[]'
+	);
+	CREATE INDEX IF NOT EXISTS idx_jobs_id ON jobs(id);
+	CREATE INDEX IF NOT EXISTS idx_jobs_posted_date ON jobs(posted_date);
+	CREATE INDEX IF NOT EXISTS idx_jobs_experience_level ON jobs(experience_level COLLATE NOCASE);
+	CREATE INDEX IF NOT EXISTS idx_jobs_remote_option ON jobs(remote_option);
+	CREATE INDEX IF NOT EXISTS idx_jobs_degree_required ON jobs(degree_required);
+	CREATE INDEX IF NOT EXISTS idx_jobs_salary_min ON jobs(salary_min);
+	CREATE INDEX IF NOT EXISTS idx_jobs_salary_max ON jobs(salary_max);
+	CREATE INDEX IF NOT EXISTS idx_jobs_location ON jobs(location);`,
+
+	// 2: normalized skills so the skills filter can use an index
+	`CREATE TABLE IF NOT EXISTS job_skills (
+		job_pk INTEGER NOT NULL REFERENCES jobs(pk) ON DELETE CASCADE,
+		skill  TEXT NOT NULL,
+		PRIMARY KEY (job_pk, skill)
+	);
+	CREATE INDEX IF NOT EXISTS idx_job_skills_skill ON job_skills(skill);`,
+
+	// 3: employment type and expiry from structured (schema.org) postings
+	`ALTER TABLE jobs ADD COLUMN employment_type TEXT NOT NULL DEFAULT '';
+	ALTER TABLE jobs ADD COLUMN valid_through INTEGER NOT NULL DEFAULT 0;`,
+
+	// 4: duplicate detection; company_block narrows candidates and job_urls holds the URLs
+	// of stored jobs and of the listings merged into them
+	`ALTER TABLE jobs ADD COLUMN company_block TEXT NOT NULL DEFAULT '';
+	ALTER TABLE jobs ADD COLUMN also_on TEXT NOT NULL DEFAULT '[]';
+	CREATE INDEX IF NOT EXISTS idx_jobs_company_block ON jobs(company_block);
+	CREATE TABLE IF NOT EXISTS job_urls (
+		url    TEXT PRIMARY KEY,
+		job_pk INTEGER NOT NULL REFERENCES jobs(pk) ON DELETE CASCADE
+	);
+	INSERT OR IGNORE INTO job_urls (url, job_pk) SELECT url, pk FROM jobs;`,
+
+	// 5: upserts by ID; job_ids holds the IDs of stored jobs and of the listings merged into them
+	`ALTER TABLE jobs ADD COLUMN last_seen INTEGER NOT NULL DEFAULT 0;
+	CREATE TABLE IF NOT EXISTS job_ids (
+		id     TEXT PRIMARY KEY,
+		job_pk INTEGER NOT NULL REFERENCES jobs(pk) ON DELETE CASCADE
+	);
+	INSERT OR IGNORE INTO job_ids (id, job_pk) SELECT id, pk FROM jobs;
+	INSERT OR IGNORE INTO job_ids (id, job_pk)
+		SELECT json_extract(listing.value, '$.id'), jobs.pk FROM jobs, json_each(jobs.also_on) AS listing
+		WHERE json_extract(listing.value, '$.id') <> '';`,
+
+	// 6: lifecycle tracking; jobs stored before were first seen when posted or last seen
+	`ALTER TABLE jobs ADD COLUMN first_seen INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE jobs ADD COLUMN status TEXT NOT NULL DEFAULT 'open';
+	ALTER TABLE jobs ADD COLUMN closed_at INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE jobs ADD COLUMN reposts INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE jobs ADD COLUMN missed_scrapes INTEGER NOT NULL DEFAULT 0;
+	UPDATE jobs SET first_seen = CASE
+		WHEN posted_date > 0 AND (last_seen = 0 OR posted_date < last_seen) THEN posted_date
+		ELSE last_seen END;
+	CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status, closed_at);
+	CREATE INDEX IF NOT EXISTS idx_jobs_source ON jobs(source);`,
+
+	// 7: change history; fields_changed holds when each field last changed
+	`ALTER TABLE jobs ADD COLUMN fields_changed TEXT NOT NULL DEFAULT '{}';
+	CREATE TABLE IF NOT EXISTS job_revisions (
+		pk         INTEGER PRIMARY KEY AUTOINCREMENT,
+		job_pk     INTEGER NOT NULL REFERENCES jobs(pk) ON DELETE CASCADE,
+		changed_at INTEGER NOT NULL,
+		source     TEXT NOT NULL DEFAULT '',
+		changes    TEXT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_job_revisions_job ON job_revisions(job_pk, changed_at);`,
+
+	// 8: URLs are no longer unique per row; boards link to pages of other sources, and a job
+	// may move to a URL another job had. job_urls maps each URL to one job. SQLite cannot
+	// drop a constraint, so the table is rebuilt.
+	`CREATE TABLE jobs_rebuilt (
+		pk               INTEGER PRIMARY KEY AUTOINCREMENT,
+		id               TEXT NOT NULL,
+		title            TEXT NOT NULL DEFAULT '',
+		company          TEXT NOT NULL DEFAULT '',
+		location         TEXT NOT NULL DEFAULT '',
+		description      TEXT NOT NULL DEFAULT '',
+		requirements     TEXT NOT NULL DEFAULT '[]',
+		skills           TEXT NOT NULL DEFAULT '[]',
+		salary_min       INTEGER NOT NULL DEFAULT 0,
+		salary_max       INTEGER NOT NULL DEFAULT 0,
+		salary_currency  TEXT NOT NULL DEFAULT '',
+		degree_required  INTEGER NOT NULL DEFAULT 0,
+		experience_level TEXT NOT NULL DEFAULT '',
+		remote_option    TEXT NOT NULL DEFAULT '',
+		posted_date      INTEGER NOT NULL DEFAULT 0,
+		url              TEXT NOT NULL,
+		source           TEXT NOT NULL DEFAULT '',
+		company_size     TEXT NOT NULL DEFAULT '',
+		industry         TEXT NOT NULL DEFAULT '',
+		benefits         TEXT NOT NULL DEFAULT '[]',
+		employment_type  TEXT NOT NULL DEFAULT '',
+		valid_through    INTEGER NOT NULL DEFAULT 0,
+		company_block    TEXT NOT NULL DEFAULT '',
+		also_on          TEXT NOT NULL DEFAULT '[]',
+		last_seen        INTEGER NOT NULL DEFAULT 0,
+		first_seen       INTEGER NOT NULL DEFAULT 0,
+		status           TEXT NOT NULL DEFAULT 'open',
+		closed_at        INTEGER NOT NULL DEFAULT 0,
+		reposts          INTEGER NOT NULL DEFAULT 0,
+		missed_scrapes   INTEGER NOT NULL DEFAULT 0,
+		fields_changed   TEXT NOT NULL DEFAULT '{}'
+	);
+	INSERT INTO jobs_rebuilt SELECT pk, id, title, company, location, description, requirements, skills,
+			salary_min, salary_max, salary_currency, degree_required, experience_level,
+			remote_option, posted_date, url, source, company_size, industry, benefits,
+			employment_type, valid_through, company_block, also_on, last_seen, first_seen, status,
+			closed_at, reposts, missed_scrapes, fields_changed
+		FROM jobs;
+	DROP TABLE jobs;
+	ALTER TABLE jobs_rebuilt RENAME TO jobs;
+	CREATE INDEX IF NOT EXISTS idx_jobs_id ON jobs(id);
+	CREATE INDEX IF NOT EXISTS idx_jobs_posted_date ON jobs(posted_date);
+	CREATE INDEX IF NOT EXISTS idx_jobs_experience_level ON jobs(experience_level COLLATE NOCASE);
+	CREATE INDEX IF NOT EXISTS idx_jobs_remote_option ON jobs(remote_option);
+	CREATE INDEX IF NOT EXISTS idx_jobs_degree_required ON jobs(degree_required);
+	CREATE INDEX IF NOT EXISTS idx_jobs_salary_min ON jobs(salary_min);
+	CREATE INDEX IF NOT EXISTS idx_jobs_salary_max ON jobs(salary_max);
+	CREATE INDEX IF NOT EXISTS idx_jobs_location ON jobs(location);
+	CREATE INDEX IF NOT EXISTS idx_jobs_company_block ON jobs(company_block);
+	CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status, closed_at);
+	CREATE INDEX IF NOT EXISTS idx_jobs_source ON jobs(source);`,
+}
+
+// jobColumns are the columns read into a models.Job, in scanJob order
+const jobColumns = `id, title, company, location, description, requirements, skills,
+		salary_min, salary_max, salary_currency, degree_required, experience_level,
+		remote_option, posted_date, url, source, company_size, industry, benefits,
+		employment_type, valid_through, also_on, last_seen, first_seen, status, closed_at,
+		reposts, missed_scrapes, fields_changed`
+
+// SQLiteStorage implements JobStorage on top of a SQLite database
+type SQLiteStorage struct {
+	db         *sql.DB
+	fullText   *fulltext.Index // text of jobs by pk, kept in memory
+	matcher    *dedupe.Matcher
+	closeAfter int          // consecutive missed scrapes after which a job is closed
+	mu         sync.RWMutex // guards matcher and closeAfter
+}
+
+// NewSQLiteStorage opens (or creates) the SQLite database at path and migrates its schema
+func NewSQLiteStorage(path string) (*SQLiteStorage, error) {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000", path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	// SQLite allows a single writer; serializing connections avoids "database is locked" errors
+	db.SetMaxOpenConns(1)
+
+	s := &SQLiteStorage{
+		db:         db,
+		fullText:   fulltext.NewIndex(),
+		matcher:    dedupe.NewMatcher(dedupe.DefaultThresholds),
+		closeAfter: DefaultClosedAfterMisses,
+	}
+	if err := s.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := s.reindexSkills(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := s.backfillCompanyBlocks(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if err := s.indexText(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Close closes the underlying database
+func (s *SQLiteStorage) Close() error {
+	return s.db.Close()
+}
+
+// migrate brings the schema up to the latest version. Foreign keys are off while it runs,
+// so a rebuilt table does not cascade deletes to the rows referencing it; each migration
+// is checked for broken references before it commits.
+func (s *SQLiteStorage) migrate() error {
+	ctx := context.Background()
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open migration connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+
+	var current int
+	if err := conn.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if current >= len(migrations) {
+		return nil
+	}
+
+	if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+		return fmt.Errorf("failed to disable foreign keys: %w", err)
+	}
+	defer conn.ExecContext(ctx, `PRAGMA foreign_keys = ON`)
+
+	for i := current; i < len(migrations); i++ {
+		version := i + 1
+
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %d: %w", version, err)
+		}
+		if _, err := tx.Exec(migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", version, err)
+		}
+		if err := checkForeignKeys(tx); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d: %w", version, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, version); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to record migration %d: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", version, err)
+		}
+	}
+
+	return nil
+}
+
+// checkForeignKeys fails if any row references a missing row
+func checkForeignKeys(tx *sql.Tx) error {
+	rows, err := tx.Query(`PRAGMA foreign_key_check`)
+	if err != nil {
+		return fmt.Errorf("failed to check foreign keys: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		var table string
+		var rowID sql.NullInt64
+		var parent string
+		var fkID int
+		if err := rows.Scan(&table, &rowID, &parent, &fkID); err != nil {
+			return fmt.Errorf("failed to check foreign keys: %w", err)
+		}
+		return fmt.Errorf("row %d of %s references a missing %s row", rowID.Int64, table, parent)
+	}
+	return rows.Err()
+}
+
+// reindexSkills rebuilds job_skills with the current skill taxonomy, so jobs stored under
+// an older taxonomy are found by their canonical skill names
+func (s *SQLiteStorage) reindexSkills() error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin skill reindex: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT pk, skills FROM jobs`)
+	if err != nil {
+		return fmt.Errorf("failed to read job skills: %w", err)
+	}
+	jobSkills := make(map[int64][]string)
+	for rows.Next() {
+		var pk int64
+		var encoded string
+		if err := rows.Scan(&pk, &encoded); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan job skills: %w", err)
+		}
+		var names []string
+		if err := json.Unmarshal([]byte(encoded), &names); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to decode skills of job %d: %w", pk, err)
+		}
+		jobSkills[pk] = names
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read job skills: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM job_skills`); err != nil {
+		return fmt.Errorf("failed to clear skill index: %w", err)
+	}
+	insertSkill, err := tx.Prepare(`INSERT OR IGNORE INTO job_skills (job_pk, skill) VALUES (?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare skill insert: %w", err)
+	}
+	defer insertSkill.Close()
+
+	for pk, names := range jobSkills {
+		for _, name := range names {
+			if _, err := insertSkill.Exec(pk, skillKey(name)); err != nil {
+				return fmt.Errorf("failed to index skills of job %d: %w", pk, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// backfillCompanyBlocks sets the duplicate detection block of jobs stored before it existed
+func (s *SQLiteStorage) backfillCompanyBlocks() error {
+	rows, err := s.db.Query(`SELECT pk, company FROM jobs WHERE company_block = '' AND company <> ''`)
+	if err != nil {
+		return fmt.Errorf("failed to read companies: %w", err)
+	}
+	blocks := make(map[int64]string)
+	for rows.Next() {
+		var pk int64
+		var company string
+		if err := rows.Scan(&pk, &company); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan company: %w", err)
+		}
+		blocks[pk] = dedupe.NewFingerprint(models.Job{Company: company}).Block
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read companies: %w", err)
+	}
+
+	for pk, block := range blocks {
+		if _, err := s.db.Exec(`UPDATE jobs SET company_block = ? WHERE pk = ?`, block, pk); err != nil {
+			return fmt.Errorf("failed to set company block of job %d: %w", pk, err)
+		}
+	}
+	return nil
+}
+
+// indexText builds the full-text index of the stored jobs
+func (s *SQLiteStorage) indexText() error {
+	rows, err := s.db.Query(`SELECT ` + jobColumns + `, pk FROM jobs`)
+	if err != nil {
+		return fmt.Errorf("failed to read jobs for full-text index: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var pk int64
+		job, err := scanJob(rows, &pk)
+		if err != nil {
+			return err
+		}
+		s.fullText.Add(int(pk), job)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read jobs for full-text index: %w", err)
+	}
+	return nil
+}
+
+// SetDuplicateThresholds changes when jobs from different sources are merged as duplicates.
+// Jobs stored before keep their merges.
+func (s *SQLiteStorage) SetDuplicateThresholds(thresholds dedupe.Thresholds) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.matcher = dedupe.NewMatcher(thresholds)
+}
+
+// SetClosedAfterMisses changes how many consecutive scrapes must miss a job before it is
+// closed; zero never closes jobs
+func (s *SQLiteStorage) SetClosedAfterMisses(misses int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeAfter = misses
+}
+
+// Store saves jobs to the database. A job already stored, by ID or URL, is updated; a
+// listing of a stored job on another source is merged into it, and a closed job posted
+// again by its source is reopened as a repost; other jobs are added.
+func (s *SQLiteStorage) Store(jobs []models.Job) error {
+	s.mu.RLock()
+	matcher := s.matcher
+	s.mu.RUnlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	insertJob, err := tx.Prepare(`INSERT INTO jobs (` + jobColumns + `, company_block)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare job insert: %w", err)
+	}
+	defer insertJob.Close()
+
+	updateJob, err := tx.Prepare(`UPDATE jobs SET
+		id = ?, title = ?, company = ?, location = ?, description = ?, requirements = ?, skills = ?,
+		salary_min = ?, salary_max = ?, salary_currency = ?, degree_required = ?, experience_level = ?,
+		remote_option = ?, posted_date = ?, url = ?, source = ?, company_size = ?, industry = ?, benefits = ?,
+		employment_type = ?, valid_through = ?, also_on = ?, last_seen = ?, first_seen = ?, status = ?, closed_at = ?,
+		reposts = ?, missed_scrapes = ?, fields_changed = ?, company_block = ?
+		WHERE pk = ?`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare job update: %w", err)
+	}
+	defer updateJob.Close()
+
+	insertSkill, err := tx.Prepare(`INSERT OR IGNORE INTO job_skills (job_pk, skill) VALUES (?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare skill insert: %w", err)
+	}
+	defer insertSkill.Close()
+
+	insertURL, err := tx.Prepare(`INSERT OR REPLACE INTO job_urls (url, job_pk) VALUES (?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare URL insert: %w", err)
+	}
+	defer insertURL.Close()
+
+	insertID, err := tx.Prepare(`INSERT OR REPLACE INTO job_ids (id, job_pk) VALUES (?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare ID insert: %w", err)
+	}
+	defer insertID.Close()
+
+	insertRevision, err := tx.Prepare(`INSERT INTO job_revisions (job_pk, changed_at, source, changes) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare revision insert: %w", err)
+	}
+	defer insertRevision.Close()
+
+	now := time.Now()
+	indexed := make(map[int64]models.Job, len(jobs)) // stored jobs by pk, for the full-text index
+	for _, job := range jobs {
+		pk, found, err := findListing(tx, job)
+		if err != nil {
+			return err
+		}
+
+		var stored models.Job
+		if found {
+			if stored, err = loadJob(tx, pk); err != nil {
+				return err
+			}
+			found = dedupe.Listed(stored, job)
+		}
+
+		listed := found
+		if !listed {
+			if stored, pk, found, err = findDuplicate(tx, matcher, job, dedupe.NewFingerprint(job)); err != nil {
+				return err
+			}
+		}
+
+		var revision *models.JobRevision
+		if found {
+			before := stored
+			if listed || reposted(before, job) {
+				dedupe.Refresh(&stored, job)
+			} else {
+				dedupe.Merge(&stored, job)
+			}
+			revision = recordChanges(before, &stored, job.Source, now)
+		} else {
+			stored = job
+		}
+		markSeen(&stored, now)
+
+		values := jobValues(stored)
+		if found {
+			if _, err := updateJob.Exec(append(values, pk)...); err != nil {
+				return fmt.Errorf("failed to update job %s: %w", stored.ID, err)
+			}
+			if _, err := tx.Exec(`DELETE FROM job_skills WHERE job_pk = ?`, pk); err != nil {
+				return fmt.Errorf("failed to update skills of job %s: %w", stored.ID, err)
+			}
+		} else {
+			result, err := insertJob.Exec(values...)
+			if err != nil {
+				return fmt.Errorf("failed to store job %s: %w", job.ID, err)
+			}
+			if pk, err = result.LastInsertId(); err != nil {
+				return fmt.Errorf("failed to read id of job %s: %w", job.ID, err)
+			}
+		}
+
+		indexed[pk] = stored
+
+		if _, err := insertURL.Exec(job.URL, pk); err != nil {
+			return fmt.Errorf("failed to store URL of job %s: %w", job.ID, err)
+		}
+		if job.ID != "" {
+			if _, err := insertID.Exec(job.ID, pk); err != nil {
+				return fmt.Errorf("failed to store ID of job %s: %w", job.ID, err)
+			}
+		}
+		for _, skill := range stored.Skills {
+			if _, err := insertSkill.Exec(pk, skillKey(skill)); err != nil {
+				return fmt.Errorf("failed to store skills of job %s: %w", stored.ID, err)
+			}
+		}
+		if revision != nil {
+			changes, _ := json.Marshal(revision.Changes)
+			if _, err := insertRevision.Exec(pk, encodeTime(revision.ChangedAt), revision.Source, string(changes)); err != nil {
+				return fmt.Errorf("failed to store revision of job %s: %w", stored.ID, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	// Indexed once committed, so searches never find jobs that were rolled back
+	for pk, job := range indexed {
+		s.fullText.Add(int(pk), job)
+	}
+	return nil
+}
+
+// jobValues returns the values of jobColumns for a job, followed by its company block
+func jobValues(job models.Job) []interface{} {
+	requirements, _ := json.Marshal(job.Requirements)
+	skills, _ := json.Marshal(job.Skills)
+	benefits, _ := json.Marshal(job.Benefits)
+	alsoOn := []byte("[]")
+	if len(job.AlsoOn) > 0 {
+		alsoOn, _ = json.Marshal(job.AlsoOn)
+	}
+	fieldsChanged := []byte("{}")
+	if len(job.FieldsChanged) > 0 {
+		fieldsChanged, _ = json.Marshal(job.FieldsChanged)
+	}
+	status := job.Status
+	if status == "" {
+		status = models.JobStatusOpen
+	}
+
+	return []interface{}{
+		job.ID, job.Title, job.Company, job.Location, job.Description, string(requirements), string(skills),
+		job.SalaryMin, job.SalaryMax, job.SalaryCurrency, job.DegreeRequired, job.ExperienceLevel,
+		job.RemoteOption, encodeTime(job.PostedDate), job.URL, job.Source, job.CompanySize, job.Industry, string(benefits),
+		job.EmploymentType, encodeOptionalTime(job.ValidThrough), string(alsoOn), encodeTime(job.LastSeen), encodeTime(job.FirstSeen),
+		status, encodeOptionalTime(job.ClosedAt), job.Reposts, job.MissedScrapes, string(fieldsChanged),
+		dedupe.NewFingerprint(job).Block,
+	}
+}
+
+// findListing returns the pk of the stored job that job is a listing of, by ID or URL
+func findListing(tx *sql.Tx, job models.Job) (int64, bool, error) {
+	var pk int64
+	err := tx.QueryRow(`SELECT job_pk FROM job_ids WHERE id = ? AND id <> ''`, job.ID).Scan(&pk)
+	if err == sql.ErrNoRows {
+		err = tx.QueryRow(`SELECT job_pk FROM job_urls WHERE url = ?`, job.URL).Scan(&pk)
+	}
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to look up job %s: %w", job.ID, err)
+	}
+	return pk, true, nil
+}
+
+// loadJob reads the job stored under pk
+func loadJob(tx *sql.Tx, pk int64) (models.Job, error) {
+	rows, err := tx.Query(`SELECT `+jobColumns+` FROM jobs WHERE pk = ?`, pk)
+	if err != nil {
+		return models.Job{}, fmt.Errorf("failed to read job %d: %w", pk, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return models.Job{}, fmt.Errorf("failed to read job %d: %w", pk, err)
+		}
+		return models.Job{}, fmt.Errorf("job %d not found", pk)
+	}
+	return scanJob(rows)
+}
+
+// findDuplicate returns the stored job that best matches job from another source, or that
+// job's source closed before posting it again, and its pk
+func findDuplicate(tx *sql.Tx, matcher *dedupe.Matcher, job models.Job, fingerprint dedupe.Fingerprint) (models.Job, int64, bool, error) {
+	if fingerprint.Block == "" {
+		return models.Job{}, 0, false, nil
+	}
+
+	rows, err := tx.Query(`SELECT `+jobColumns+`, pk FROM jobs WHERE company_block = ?`, fingerprint.Block)
+	if err != nil {
+		return models.Job{}, 0, false, fmt.Errorf("failed to query duplicate candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var best models.Job
+	var bestPK int64
+	bestScore := 0.0
+	for rows.Next() {
+		var pk int64
+		candidate, err := scanJob(rows, &pk)
+		if err != nil {
+			return models.Job{}, 0, false, err
+		}
+		if dedupe.HasSource(candidate, job.Source) && !reposted(candidate, job) {
+			continue
+		}
+
+		match := matcher.Compare(dedupe.NewFingerprint(candidate), fingerprint)
+		if match.Duplicate && match.Score() > bestScore {
+			best, bestPK, bestScore = candidate, pk, match.Score()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return models.Job{}, 0, false, fmt.Errorf("failed to read duplicate candidates: %w", err)
+	}
+
+	return best, bestPK, bestPK != 0, nil
+}
+
+// Search filters and returns jobs based on criteria
+func (s *SQLiteStorage) Search(filters models.SearchFilters) (*models.SearchResponse, error) {
+	query, args := buildSearchQuery(filters)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query jobs: %w", err)
+	}
+	defer rows.Close()
+
+	// The full-text index finds the jobs with the keywords, so the other filters are
+	// checked without them
+	matching, searched := s.fullText.MatchingKeywords(filters.Keywords)
+	rest := filters
+	rest.Keywords = nil
+
+	text := textQuery(filters)
+	scores := s.fullText.Scores(text)
+
+	var filteredJobs []models.Job
+	now := time.Now()
+	for rows.Next() {
+		var pk int64
+		job, err := scanJob(rows, &pk)
+		if err != nil {
+			return nil, err
+		}
+		if searched && !matching[int(pk)] {
+			continue
+		}
+		job.Status = currentStatus(job, now)
+
+		// The SQL query narrows candidates using indexed columns; the shared
+		// matcher keeps text matching identical to the in-memory backend
+		if matchesFilters(job, rest) {
+			job.Score = scores[int(pk)]
+			filteredJobs = append(filteredJobs, job)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read jobs: %w", err)
+	}
+
+	sortJobs(filteredJobs, filters, text)
+	page := paginate(filteredJobs, filters)
+	highlightJobs(page, text)
+
+	return &models.SearchResponse{
+		Jobs:      page,
+		Total:     len(filteredJobs),
+		Analytics: calculateAnalytics(filteredJobs, filters.SalaryCurrency),
+		Filters:   filters,
+	}, nil
+}
+
+// RecordScrape counts a miss for the open jobs listed on source within filters that the
+// scrape did not return
+func (s *SQLiteStorage) RecordScrape(source string, filters models.SearchFilters, found []models.Job) error {
+	s.mu.RLock()
+	closeAfter := s.closeAfter
+	s.mu.RUnlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT `+jobColumns+`, pk FROM jobs WHERE status <> ? AND (source = ? COLLATE NOCASE
+		OR EXISTS (SELECT 1 FROM json_each(jobs.also_on) WHERE json_extract(value, '$.source') = ? COLLATE NOCASE))`,
+		models.JobStatusClosed, source, source)
+	if err != nil {
+		return fmt.Errorf("failed to query jobs of %s: %w", source, err)
+	}
+
+	now := time.Now()
+	listings := newFoundListings(found)
+	scope := scrapeScope(filters)
+	missed := make(map[int64]models.Job)
+	for rows.Next() {
+		var pk int64
+		job, err := scanJob(rows, &pk)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		if listings.missedBy(job, source) && matchesFilters(job, scope) && markMissed(&job, now, closeAfter) {
+			missed[pk] = job
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read jobs of %s: %w", source, err)
+	}
+
+	for pk, job := range missed {
+		if _, err := tx.Exec(`UPDATE jobs SET status = ?, closed_at = ?, missed_scrapes = ? WHERE pk = ?`,
+			job.Status, encodeOptionalTime(job.ClosedAt), job.MissedScrapes, pk); err != nil {
+			return fmt.Errorf("failed to record missed scrape of job %s: %w", job.ID, err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// History returns the revisions of a stored job, found by its ID or a merged listing's ID
+func (s *SQLiteStorage) History(id string) (*models.JobHistory, error) {
+	var pk int64
+	var jobID string
+	err := s.db.QueryRow(`SELECT jobs.pk, jobs.id FROM job_ids JOIN jobs ON jobs.pk = job_ids.job_pk
+		WHERE job_ids.id = ? AND job_ids.id <> ''`, id).Scan(&pk, &jobID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up job %s: %w", id, err)
+	}
+
+	rows, err := s.db.Query(`SELECT changed_at, source, changes FROM job_revisions WHERE job_pk = ? ORDER BY changed_at, pk`, pk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query history of job %s: %w", id, err)
+	}
+	defer rows.Close()
+
+	history := &models.JobHistory{JobID: jobID, Revisions: []models.JobRevision{}}
+	for rows.Next() {
+		var revision models.JobRevision
+		var changedAt int64
+		var changes string
+		if err := rows.Scan(&changedAt, &revision.Source, &changes); err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		if err := json.Unmarshal([]byte(changes), &revision.Changes); err != nil {
+			return nil, fmt.Errorf("failed to decode revision of job %s: %w", id, err)
+		}
+		revision.ChangedAt = decodeTime(changedAt)
+		history.Revisions = append(history.Revisions, revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read history of job %s: %w", id, err)
+	}
+
+	return history, nil
+}
+
+// Get returns a stored job, found by its ID or URL or those of a merged listing
+func (s *SQLiteStorage) Get(id string) (*models.Job, error) {
+	rows, err := s.db.Query(`SELECT `+jobColumns+` FROM jobs WHERE pk = COALESCE(
+		(SELECT job_pk FROM job_ids WHERE id = ?1 AND id <> ''),
+		(SELECT job_pk FROM job_urls WHERE url = ?1))`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up job %s: %w", id, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to look up job %s: %w", id, err)
+		}
+		return nil, nil
+	}
+	job, err := scanJob(rows)
+	if err != nil {
+		return nil, err
+	}
+	job.Status = currentStatus(job, time.Now())
+	return &job, nil
+}
+
+// Clear removes all jobs from storage
+func (s *SQLiteStorage) Clear() error {
+	if _, err := s.db.Exec(`DELETE FROM job_revisions; DELETE FROM job_skills; DELETE FROM job_urls; DELETE FROM job_ids; DELETE FROM jobs;`); err != nil {
+		return fmt.Errorf("failed to clear jobs: %w", err)
+	}
+	s.fullText.Clear()
+	return nil
+}
+
+// GetAnalytics calculates analytics from job data, with salaries in the base currency
+func (s *SQLiteStorage) GetAnalytics(jobs []models.Job) models.JobAnalytics {
+	return calculateAnalytics(jobs, "")
+}
+
+// buildSearchQuery translates the indexable parts of the filters into SQL
+func buildSearchQuery(filters models.SearchFilters) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filters.RemoteOnly {
+		conditions = append(conditions, `instr(lower(remote_option), 'remote') > 0`)
+	}
+
+	// Salaries are stored in their own currency, so the amounts are compared after
+	// conversion by matchesFilters
+	if filters.MinSalary > 0 {
+		conditions = append(conditions, `salary_min > 0`)
+	}
+	if filters.MaxSalary > 0 {
+		conditions = append(conditions, `salary_max > 0`)
+	}
+
+	if filters.PostedWithinDays > 0 {
+		conditions = append(conditions, `posted_date >= ?`)
+		args = append(args, time.Now().AddDate(0, 0, -filters.PostedWithinDays).UnixNano())
+	}
+
+	if filters.ExperienceLevel != "" {
+		conditions = append(conditions, `experience_level = ? COLLATE NOCASE`)
+		args = append(args, filters.ExperienceLevel)
+	}
+
+	if filters.DegreeRequired != nil {
+		conditions = append(conditions, `degree_required = ?`)
+		args = append(args, *filters.DegreeRequired)
+	}
+
+	// Expired jobs are open jobs past their ValidThrough date; matchesFilters tells them apart
+	if strings.EqualFold(filters.Status, models.JobStatusClosed) || filters.ClosedWithinDays > 0 {
+		conditions = append(conditions, `status = ?`)
+		args = append(args, models.JobStatusClosed)
+	} else if filters.Status != "" || filters.OpenLongerThanDays > 0 {
+		conditions = append(conditions, `status <> ?`)
+		args = append(args, models.JobStatusClosed)
+	}
+
+	if filters.ClosedWithinDays > 0 {
+		conditions = append(conditions, `closed_at >= ?`)
+		args = append(args, time.Now().AddDate(0, 0, -filters.ClosedWithinDays).UnixNano())
+	}
+
+	if filters.MinReposts > 0 {
+		conditions = append(conditions, `reposts >= ?`)
+		args = append(args, filters.MinReposts)
+	}
+
+	// When fields changed is compared by matchesFilters
+	if filters.ChangedWithinDays > 0 || len(filters.ChangedFields) > 0 {
+		conditions = append(conditions, `fields_changed <> '{}'`)
+	}
+
+	for _, skill := range filters.Skills {
+		conditions = append(conditions, `EXISTS (SELECT 1 FROM job_skills WHERE job_skills.job_pk = jobs.pk AND job_skills.skill = ?)`)
+		args = append(args, skillKey(skill))
+	}
+
+	query := `SELECT ` + jobColumns + `, pk FROM jobs`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// Sort by posted date (newest first)
+	query += " ORDER BY posted_date DESC, pk ASC"
+
+	return query, args
+}
+
+// skillKey is how a skill is stored in job_skills: its canonical name in lower case
+func skillKey(skill string) string {
+	return strings.ToLower(skills.Canonical(skill))
+}
+
+// scanJob reads a single row selecting jobColumns, followed by any extra columns
+func scanJob(rows *sql.Rows, extra ...interface{}) (models.Job, error) {
+	var job models.Job
+	var requirements, skills, benefits, alsoOn, fieldsChanged string
+	var postedDate, validThrough, lastSeen, firstSeen, closedAt int64
+
+	dest := []interface{}{
+		&job.ID, &job.Title, &job.Company, &job.Location, &job.Description, &requirements, &skills,
+		&job.SalaryMin, &job.SalaryMax, &job.SalaryCurrency, &job.DegreeRequired, &job.ExperienceLevel,
+		&job.RemoteOption, &postedDate, &job.URL, &job.Source, &job.CompanySize, &job.Industry, &benefits,
+		&job.EmploymentType, &validThrough, &alsoOn, &lastSeen, &firstSeen, &job.Status, &closedAt,
+		&job.Reposts, &job.MissedScrapes, &fieldsChanged,
+	}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return job, fmt.Errorf("failed to scan job: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(requirements), &job.Requirements); err != nil {
+		return job, fmt.Errorf("failed to decode requirements of job %s: %w", job.ID, err)
+	}
+	if err := json.Unmarshal([]byte(skills), &job.Skills); err != nil {
+		return job, fmt.Errorf("failed to decode skills of job %s: %w", job.ID, err)
+	}
+	if err := json.Unmarshal([]byte(benefits), &job.Benefits); err != nil {
+		return job, fmt.Errorf("failed to decode benefits of job %s: %w", job.ID, err)
+	}
+	if err := json.Unmarshal([]byte(alsoOn), &job.AlsoOn); err != nil {
+		return job, fmt.Errorf("failed to decode other listings of job %s: %w", job.ID, err)
+	}
+	if len(job.AlsoOn) == 0 {
+		job.AlsoOn = nil
+	}
+	if err := json.Unmarshal([]byte(fieldsChanged), &job.FieldsChanged); err != nil {
+		return job, fmt.Errorf("failed to decode changed fields of job %s: %w", job.ID, err)
+	}
+	if len(job.FieldsChanged) == 0 {
+		job.FieldsChanged = nil
+	}
+	job.PostedDate = decodeTime(postedDate)
+	job.LastSeen = decodeTime(lastSeen)
+	job.FirstSeen = decodeTime(firstSeen)
+	if closedAt != 0 {
+		closed := decodeTime(closedAt)
+		job.ClosedAt = &closed
+	}
+	if validThrough != 0 {
+		expires := decodeTime(validThrough)
+		job.ValidThrough = &expires
+	}
+
+	return job, nil
+}
+
+// encodeTime stores times as unix nanoseconds, keeping the zero time as 0
+func encodeTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+// encodeOptionalTime stores an unset time as 0
+func encodeOptionalTime(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return encodeTime(*t)
+}
+
+// decodeTime reverses encodeTime
+func decodeTime(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
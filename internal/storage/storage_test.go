@@ -0,0 +1,480 @@
+package storage
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Illuminateee/web-scrapper.git/internal/models"
+)
+
+// backends returns a fresh instance of every JobStorage implementation, so each behavior
+// is checked the same way on all of them
+func backends(t *testing.T) []struct {
+	name    string
+	storage JobStorage
+} {
+	t.Helper()
+
+	sqliteStorage, err := NewSQLiteStorage(":memory:")
+	if err != nil {
+		t.Fatalf("failed to open SQLite storage: %v", err)
+	}
+	t.Cleanup(func() { sqliteStorage.Close() })
+
+	return []struct {
+		name    string
+		storage JobStorage
+	}{
+		{"memory", NewInMemoryStorage()},
+		{"sqlite", sqliteStorage},
+	}
+}
+
+// ids returns the IDs of jobs in order
+func ids(jobs []models.Job) string {
+	result := make([]string, 0, len(jobs))
+	for _, job := range jobs {
+		result = append(result, job.ID)
+	}
+	return strings.Join(result, ",")
+}
+
+// sampleJobs are four unrelated jobs, from newest to oldest
+func sampleJobs() []models.Job {
+	now := time.Now()
+	return []models.Job{
+		{ID: "a", URL: "https://example.com/a", Source: "RemoteOK", Title: "Senior Go Developer", Company: "Acme",
+			Location: "Remote", RemoteOption: "remote", ExperienceLevel: "senior", Skills: []string{"Go", "PostgreSQL"},
+			SalaryMin: 120000, SalaryMax: 150000, SalaryCurrency: "USD", PostedDate: now.AddDate(0, 0, -1),
+			Description: "Build payment APIs in Go."},
+		{ID: "b", URL: "https://example.com/b", Source: "RemoteOK", Title: "Python Engineer", Company: "Globex",
+			Location: "New York, NY", RemoteOption: "onsite", ExperienceLevel: "mid", Skills: []string{"Python", "SQL"},
+			SalaryMin: 90000, SalaryMax: 110000, SalaryCurrency: "USD", DegreeRequired: true, PostedDate: now.AddDate(0, 0, -3),
+			Description: "Data pipelines with Python and Airflow."},
+		{ID: "c", URL: "https://example.com/c", Source: "Indeed", Title: "Frontend Developer", Company: "Initech",
+			Location: "Austin, TX", RemoteOption: "hybrid", ExperienceLevel: "entry", Skills: []string{"React"},
+			PostedDate: now.AddDate(0, 0, -10), Description: "React developers for our design system."},
+		{ID: "d", URL: "https://example.com/d", Source: "Indeed", Title: "Go Platform Engineer", Company: "Acme",
+			Location: "Remote", RemoteOption: "remote", ExperienceLevel: "senior", Skills: []string{"golang", "Kubernetes"},
+			SalaryMin: 140000, SalaryMax: 170000, SalaryCurrency: "USD", PostedDate: now.AddDate(0, 0, -30),
+			Description: "Run Kubernetes clusters."},
+	}
+}
+
+func TestSearchFilters(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name    string
+		filters models.SearchFilters
+		want    string
+	}{
+		{"no filters, newest first", models.SearchFilters{}, "a,b,c,d"},
+		{"title", models.SearchFilters{JobTitle: "developer", Sort: models.SortDate}, "a,c"},
+		{"keywords", models.SearchFilters{Keywords: []string{"airflow", "kubernetes"}, Sort: models.SortDate}, "b,d"},
+		{"location", models.SearchFilters{Location: "remote"}, "a,d"},
+		{"locations", models.SearchFilters{Locations: []string{"Austin", "New York"}}, "b,c"},
+		{"remote only", models.SearchFilters{RemoteOnly: true}, "a,d"},
+		{"min salary", models.SearchFilters{MinSalary: 100000}, "a,d"},
+		{"max salary", models.SearchFilters{MaxSalary: 150000}, "a,b"},
+		{"posted within days", models.SearchFilters{PostedWithinDays: 7}, "a,b"},
+		{"experience level", models.SearchFilters{ExperienceLevel: "SENIOR"}, "a,d"},
+		{"degree required", models.SearchFilters{DegreeRequired: &yes}, "b"},
+		{"no degree required", models.SearchFilters{DegreeRequired: &no}, "a,c,d"},
+		{"skills by canonical name", models.SearchFilters{Skills: []string{"go"}}, "a,d"},
+		{"all skills required", models.SearchFilters{Skills: []string{"Go", "Kubernetes"}}, "d"},
+		{"combined", models.SearchFilters{RemoteOnly: true, MaxSalary: 160000}, "a"},
+		{"nothing matches", models.SearchFilters{Skills: []string{"Rust"}}, ""},
+	}
+
+	for _, backend := range backends(t) {
+		if err := backend.storage.Store(sampleJobs()); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+		for _, test := range tests {
+			response, err := backend.storage.Search(test.filters)
+			if err != nil {
+				t.Errorf("%s: %s: Search: %v", backend.name, test.name, err)
+				continue
+			}
+			if got := ids(response.Jobs); got != test.want {
+				t.Errorf("%s: %s: got %q, want %q", backend.name, test.name, got, test.want)
+			}
+			if response.Total != len(response.Jobs) {
+				t.Errorf("%s: %s: total %d, want %d", backend.name, test.name, response.Total, len(response.Jobs))
+			}
+		}
+	}
+}
+
+func TestSearchPagination(t *testing.T) {
+	tests := []struct {
+		limit, offset int
+		want          string
+	}{
+		{0, 0, "a,b,c,d"},
+		{2, 0, "a,b"},
+		{2, 2, "c,d"},
+		{3, 3, "d"},
+		{2, 10, ""},
+	}
+
+	for _, backend := range backends(t) {
+		if err := backend.storage.Store(sampleJobs()); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+		for _, test := range tests {
+			response, err := backend.storage.Search(models.SearchFilters{Limit: test.limit, Offset: test.offset})
+			if err != nil {
+				t.Fatalf("%s: Search: %v", backend.name, err)
+			}
+			if got := ids(response.Jobs); got != test.want {
+				t.Errorf("%s: limit %d offset %d: got %q, want %q", backend.name, test.limit, test.offset, got, test.want)
+			}
+			if response.Total != 4 {
+				t.Errorf("%s: limit %d offset %d: total %d, want 4", backend.name, test.limit, test.offset, response.Total)
+			}
+		}
+	}
+}
+
+func TestSearchAnalytics(t *testing.T) {
+	for _, backend := range backends(t) {
+		if err := backend.storage.Store(sampleJobs()); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+
+		// Analytics cover every matching job, not only the page
+		response, err := backend.storage.Search(models.SearchFilters{Limit: 1})
+		if err != nil {
+			t.Fatalf("%s: Search: %v", backend.name, err)
+		}
+		analytics := response.Analytics
+
+		if analytics.TotalJobs != 4 {
+			t.Errorf("%s: total jobs %d, want 4", backend.name, analytics.TotalJobs)
+		}
+		// Midpoints 135000, 100000 and 155000
+		if analytics.AverageSalary != 130000 || analytics.SalaryRange.Min != 100000 || analytics.SalaryRange.Max != 155000 || analytics.SalaryRange.Median != 135000 {
+			t.Errorf("%s: salary average %v range %+v", backend.name, analytics.AverageSalary, analytics.SalaryRange)
+		}
+		if len(analytics.TopSkills) == 0 || analytics.TopSkills[0].Skill != "Go" || analytics.TopSkills[0].Count != 2 {
+			t.Errorf("%s: top skills %+v, want Go x2 first", backend.name, analytics.TopSkills)
+		}
+		if len(analytics.TopCompanies) == 0 || analytics.TopCompanies[0] != (models.CompanyCount{Company: "Acme", Count: 2}) {
+			t.Errorf("%s: top companies %+v, want Acme x2 first", backend.name, analytics.TopCompanies)
+		}
+		if analytics.ExperienceLevels["senior"] != 2 || analytics.RemoteOptions["remote"] != 2 ||
+			analytics.DegreeRequirements["Required"] != 1 || analytics.LocationDistribution["Remote"] != 2 {
+			t.Errorf("%s: unexpected distributions %+v", backend.name, analytics)
+		}
+	}
+}
+
+func TestStoreUpdatesStoredJobs(t *testing.T) {
+	for _, backend := range backends(t) {
+		if err := backend.storage.Store(sampleJobs()); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+
+		// Storing the same jobs again updates them instead of adding copies
+		again := sampleJobs()
+		again[0].SalaryMax = 160000
+		if err := backend.storage.Store(again); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+		response, err := backend.storage.Search(models.SearchFilters{})
+		if err != nil {
+			t.Fatalf("%s: Search: %v", backend.name, err)
+		}
+		if got := ids(response.Jobs); got != "a,b,c,d" {
+			t.Errorf("%s: got %q after storing again, want a,b,c,d", backend.name, got)
+		}
+		if len(response.Jobs) > 0 && response.Jobs[0].SalaryMax != 160000 {
+			t.Errorf("%s: salary max %d, want the updated 160000", backend.name, response.Jobs[0].SalaryMax)
+		}
+	}
+}
+
+func TestClear(t *testing.T) {
+	for _, backend := range backends(t) {
+		if err := backend.storage.Store(sampleJobs()); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+		if err := backend.storage.Clear(); err != nil {
+			t.Fatalf("%s: Clear: %v", backend.name, err)
+		}
+		response, err := backend.storage.Search(models.SearchFilters{Keywords: []string{"go"}})
+		if err != nil {
+			t.Fatalf("%s: Search: %v", backend.name, err)
+		}
+		if response.Total != 0 {
+			t.Errorf("%s: %d jobs after Clear, want none", backend.name, response.Total)
+		}
+	}
+}
+
+func TestSourcesSharingAURLStaySeparate(t *testing.T) {
+	for _, backend := range backends(t) {
+		// An aggregator links to the employer's page, which another source lists too
+		jobs := []models.Job{
+			{ID: "board-1", URL: "https://careers.example.com/apply", Source: "Board", Title: "Senior Go Developer",
+				Company: "Acme", Location: "Remote"},
+			{ID: "feed-7", URL: "https://careers.example.com/apply", Source: "Feed", Title: "Office Manager",
+				Company: "Globex", Location: "Berlin"},
+		}
+		if err := backend.storage.Store(jobs); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+		response, err := backend.storage.Search(models.SearchFilters{Sort: models.SortDate})
+		if err != nil {
+			t.Fatalf("%s: Search: %v", backend.name, err)
+		}
+		if got := ids(response.Jobs); got != "board-1,feed-7" && got != "feed-7,board-1" {
+			t.Errorf("%s: got %q, want both board-1 and feed-7", backend.name, got)
+		}
+	}
+}
+
+func TestJobMovesToAnotherJobsURL(t *testing.T) {
+	for _, backend := range backends(t) {
+		if err := backend.storage.Store(sampleJobs()); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+
+		// The source reuses b's old URL for a, which keeps its ID
+		moved := sampleJobs()[0]
+		moved.URL = "https://example.com/b"
+		if err := backend.storage.Store([]models.Job{moved}); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+
+		response, err := backend.storage.Search(models.SearchFilters{})
+		if err != nil {
+			t.Fatalf("%s: Search: %v", backend.name, err)
+		}
+		if got := ids(response.Jobs); got != "a,b,c,d" {
+			t.Fatalf("%s: got %q, want a,b,c,d", backend.name, got)
+		}
+		if url := response.Jobs[0].URL; url != "https://example.com/b" {
+			t.Errorf("%s: a has URL %q, want its new URL", backend.name, url)
+		}
+	}
+}
+
+func TestMigrationKeepsStoredJobs(t *testing.T) {
+	path := t.TempDir() + "/jobs.db"
+
+	// Store jobs under the schema from before URLs stopped being unique
+	all := migrations
+	migrations = all[:7]
+	old, err := NewSQLiteStorage(path)
+	migrations = all
+	if err != nil {
+		t.Fatalf("failed to open schema 7: %v", err)
+	}
+	if err := old.Store(sampleJobs()); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	old.Close()
+
+	migrated, err := NewSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	defer migrated.Close()
+
+	// Skills and IDs reference jobs by pk, so they must survive the rebuild
+	response, err := migrated.Search(models.SearchFilters{Skills: []string{"go"}})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if got := ids(response.Jobs); got != "a,d" {
+		t.Errorf("skills after migrating: got %q, want a,d", got)
+	}
+	again := sampleJobs()
+	again[1].SalaryMax = 120000
+	if err := migrated.Store(again); err != nil {
+		t.Fatalf("Store: %v", err)
+	}
+	response, err = migrated.Search(models.SearchFilters{})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if got := ids(response.Jobs); got != "a,b,c,d" || response.Jobs[1].SalaryMax != 120000 {
+		t.Errorf("after migrating: got %q, want a,b,c,d with b updated", got)
+	}
+}
+
+func TestGet(t *testing.T) {
+	for _, backend := range backends(t) {
+		// a is also listed on another board, which is merged into it
+		elsewhere := sampleJobs()[0]
+		elsewhere.ID, elsewhere.URL, elsewhere.Source = "z", "https://elsewhere.example.com/z", "WeWorkRemotely"
+		if err := backend.storage.Store(append(sampleJobs(), elsewhere)); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+
+		for _, id := range []string{"a", "z", "https://elsewhere.example.com/z"} {
+			job, err := backend.storage.Get(id)
+			if err != nil {
+				t.Fatalf("%s: Get(%q): %v", backend.name, id, err)
+			}
+			if job == nil || job.ID != "a" || job.Status != models.JobStatusOpen {
+				t.Errorf("%s: Get(%q) = %+v, want open job a", backend.name, id, job)
+			}
+		}
+
+		for _, id := range []string{"unknown", ""} {
+			if job, err := backend.storage.Get(id); err != nil || job != nil {
+				t.Errorf("%s: Get(%q) = %v, %v; want nothing", backend.name, id, job, err)
+			}
+		}
+	}
+}
+
+// changedFields returns the fields changed by each revision, one revision per entry
+func changedFields(history *models.JobHistory) []string {
+	var result []string
+	if history == nil {
+		return result
+	}
+	for _, revision := range history.Revisions {
+		fields := make([]string, 0, len(revision.Changes))
+		for _, change := range revision.Changes {
+			fields = append(fields, change.Field)
+		}
+		result = append(result, strings.Join(fields, ","))
+	}
+	return result
+}
+
+func TestRefreshOverwritesTrackedFields(t *testing.T) {
+	for _, backend := range backends(t) {
+		if err := backend.storage.Store(sampleJobs()); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+
+		// The source shortens the description, drops a skill and the salary of a
+		edited := sampleJobs()[0]
+		edited.Description = "Build APIs."
+		edited.Skills = []string{"Go"}
+		edited.SalaryMin, edited.SalaryMax, edited.SalaryCurrency = 0, 0, ""
+		if err := backend.storage.Store([]models.Job{edited}); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+
+		job, err := backend.storage.Get("a")
+		if err != nil || job == nil {
+			t.Fatalf("%s: Get: %v, %v", backend.name, job, err)
+		}
+		if job.Description != "Build APIs." || strings.Join(job.Skills, ",") != "Go" || job.SalaryMax != 0 {
+			t.Errorf("%s: got %q, skills %v, salary max %d; want the edited listing",
+				backend.name, job.Description, job.Skills, job.SalaryMax)
+		}
+
+		history, err := backend.storage.History("a")
+		if err != nil {
+			t.Fatalf("%s: History: %v", backend.name, err)
+		}
+		if got := changedFields(history); len(got) != 1 || got[0] != "description,skills,salary" {
+			t.Errorf("%s: revisions %q, want one changing description,skills,salary", backend.name, got)
+		}
+	}
+}
+
+func TestDuplicateFillsInJob(t *testing.T) {
+	for _, backend := range backends(t) {
+		// a is also listed on another board, with a shorter description and another skill
+		elsewhere := sampleJobs()[0]
+		elsewhere.ID, elsewhere.URL, elsewhere.Source = "z", "https://elsewhere.example.com/z", "WeWorkRemotely"
+		elsewhere.Description = "Go APIs."
+		elsewhere.Skills = []string{"Go", "Docker"}
+		if err := backend.storage.Store(append(sampleJobs(), elsewhere)); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+		// and refreshing that listing keeps what the job has
+		if err := backend.storage.Store([]models.Job{elsewhere}); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+
+		job, err := backend.storage.Get("a")
+		if err != nil || job == nil {
+			t.Fatalf("%s: Get: %v, %v", backend.name, job, err)
+		}
+		if job.Description != "Build payment APIs in Go." {
+			t.Errorf("%s: description %q, want the longer one", backend.name, job.Description)
+		}
+		if got := strings.Join(job.Skills, ","); got != "Go,PostgreSQL,Docker" {
+			t.Errorf("%s: skills %q, want Go,PostgreSQL,Docker", backend.name, got)
+		}
+	}
+}
+
+func TestRecordScrapeClosesMissedJobs(t *testing.T) {
+	for _, backend := range backends(t) {
+		backend.storage.(interface{ SetClosedAfterMisses(int) }).SetClosedAfterMisses(2)
+		if err := backend.storage.Store(sampleJobs()); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+
+		// RemoteOK stops listing b; Indeed is not scraped
+		found := sampleJobs()[:1]
+		status := func(id string) string {
+			job, err := backend.storage.Get(id)
+			if err != nil || job == nil {
+				t.Fatalf("%s: Get(%q): %v, %v", backend.name, id, job, err)
+			}
+			return job.Status
+		}
+		for miss := 1; miss <= 2; miss++ {
+			if err := backend.storage.RecordScrape("RemoteOK", models.SearchFilters{}, found); err != nil {
+				t.Fatalf("%s: RecordScrape: %v", backend.name, err)
+			}
+			want := models.JobStatusOpen
+			if miss == 2 {
+				want = models.JobStatusClosed
+			}
+			if got := status("b"); got != want {
+				t.Errorf("%s: b is %s after %d missed scrapes, want %s", backend.name, got, miss, want)
+			}
+		}
+		for _, id := range []string{"a", "c", "d"} {
+			if got := status(id); got != models.JobStatusOpen {
+				t.Errorf("%s: %s is %s, want open", backend.name, id, got)
+			}
+		}
+
+		// Listed again, it reopens
+		if err := backend.storage.Store(sampleJobs()[1:2]); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+		if got := status("b"); got != models.JobStatusOpen {
+			t.Errorf("%s: b is %s after it is listed again, want open", backend.name, got)
+		}
+	}
+}
+
+func TestSearchFollowsUpdatedJobs(t *testing.T) {
+	for _, backend := range backends(t) {
+		if err := backend.storage.Store(sampleJobs()); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+
+		rewritten := sampleJobs()[0]
+		rewritten.Title, rewritten.Description, rewritten.Skills = "Rust Developer", "Maintain Rust crates.", []string{"Rust"}
+		if err := backend.storage.Store([]models.Job{rewritten}); err != nil {
+			t.Fatalf("%s: Store: %v", backend.name, err)
+		}
+
+		for keyword, want := range map[string]string{"payment": "", "rust": "a", "go": "d"} {
+			response, err := backend.storage.Search(models.SearchFilters{Keywords: []string{keyword}})
+			if err != nil {
+				t.Fatalf("%s: Search: %v", backend.name, err)
+			}
+			if got := ids(response.Jobs); got != want {
+				t.Errorf("%s: %q found %q after the update, want %q", backend.name, keyword, got, want)
+			}
+		}
+	}
+}
@@ -1,4 +1,4 @@
-import React, { useState } from 'react';
+import React, { useEffect, useRef, useState } from 'react';
 import {
   Box,
   Container,
@@ -21,7 +21,7 @@ import SearchForm from './components/SearchForm';
 import JobCard from './components/JobCard';
 import Analytics from './components/Analytics';
 import { JobService, handleApiError } from './services/jobService';
-import { SearchFilters, SearchResponse, Job } from './types';
+import { SearchFilters, SearchResponse, Job, JobAnalytics } from './types';
 
 const theme = createTheme({
   palette: {
@@ -62,25 +62,59 @@ function App() {
   const [snackbarOpen, setSnackbarOpen] = useState(false);
   const [snackbarMessage, setSnackbarMessage] = useState('');
 
-  const handleSearch = async (filters: SearchFilters) => {
+  const [sourcesDone, setSourcesDone] = useState<string[]>([]);
+  const stopStreamRef = useRef<(() => void) | null>(null);
+
+  // Close any open stream when the app unmounts
+  useEffect(() => () => stopStreamRef.current?.(), []);
+
+  const handleSearch = (filters: SearchFilters) => {
+    stopStreamRef.current?.();
+
     setLoading(true);
     setError(null);
-    
-    try {
-      const response = await JobService.searchJobs(filters);
-      setSearchResponse(response);
-      setSnackbarMessage(`Found ${response.total} jobs matching your criteria`);
-      setSnackbarOpen(true);
-    } catch (err) {
-      const errorMessage = handleApiError(err);
-      setError(errorMessage);
-      console.error('Search error:', err);
-    } finally {
-      setLoading(false);
-    }
+    setSourcesDone([]);
+    setSearchResponse({
+      jobs: [],
+      total: 0,
+      analytics: {} as JobAnalytics,
+      filters,
+    });
+
+    // Render jobs as each source finishes instead of waiting for the slowest one
+    stopStreamRef.current = JobService.streamSearch(filters, {
+      onResult: (result) => {
+        setSourcesDone((done) => [...done, result.source]);
+        if (result.error) {
+          console.warn(`Source ${result.source} failed:`, result.error);
+          return;
+        }
+        setSearchResponse((current) =>
+          current && {
+            ...current,
+            jobs: [...current.jobs, ...(result.jobs || [])],
+            total: current.total + result.job_count,
+          }
+        );
+      },
+      onComplete: (summary) => {
+        setSearchResponse((current) =>
+          current && { ...current, total: summary.total, analytics: summary.analytics }
+        );
+        setSnackbarMessage(`Found ${summary.total} jobs matching your criteria`);
+        setSnackbarOpen(true);
+        setLoading(false);
+      },
+      onError: (message) => {
+        setError(message);
+        setLoading(false);
+      },
+    });
   };
 
   const handleClear = () => {
+    stopStreamRef.current?.();
+    setLoading(false);
     setSearchResponse(null);
     setError(null);
   };
@@ -152,13 +186,14 @@ function App() {
               <Box sx={{ ml: 2, display: 'flex', alignItems: 'center' }}>
                 <Typography variant="h6">
                   Searching jobs across multiple sites...
+                  {sourcesDone.length > 0 && ` (${sourcesDone.length} sources done)`}
                 </Typography>
               </Box>
             </Box>
           )}
 
           {/* Results */}
-          {searchResponse && !loading && (
+          {searchResponse && (
             <Paper sx={{ width: '100%' }}>
               <Box sx={{ borderBottom: 1, borderColor: 'divider' }}>
                 <Tabs value={tabValue} onChange={handleTabChange} aria-label="job results tabs">
@@ -206,7 +241,13 @@ function App() {
 
               {/* Analytics Tab */}
               <TabPanel value={tabValue} index={1}>
-                <Analytics analytics={searchResponse.analytics} />
+                {loading ? (
+                  <Typography variant="body2" color="text.secondary">
+                    Analytics will appear once every source has finished.
+                  </Typography>
+                ) : (
+                  <Analytics analytics={searchResponse.analytics} />
+                )}
               </TabPanel>
             </Paper>
           )}
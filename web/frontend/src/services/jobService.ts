@@ -1,5 +1,13 @@
 import axios from 'axios';
-import { SearchFilters, SearchResponse, Job, JobAnalytics } from './types';
+import {
+  SearchFilters,
+  SearchResponse,
+  SearchProgress,
+  StreamResult,
+  StreamSummary,
+  Job,
+  JobAnalytics,
+} from './types';
 
 const API_BASE_URL = process.env.REACT_APP_API_URL || 'http://localhost:8080/api/v1';
 
@@ -8,31 +16,88 @@ const api = axios.create({
   timeout: 60000, // 60 seconds timeout for scraping operations
 });
 
+export interface StreamHandlers {
+  onResult: (result: StreamResult) => void;
+  onComplete: (summary: StreamSummary) => void;
+  onError: (message: string) => void;
+}
+
+// Build query parameters for the search endpoints
+const buildSearchParams = (filters: SearchFilters): URLSearchParams => {
+  const params = new URLSearchParams();
+
+  if (filters.job_title) params.append('title', filters.job_title);
+  if (filters.keywords?.length) params.append('keywords', filters.keywords.join(','));
+  if (filters.location) params.append('location', filters.location);
+  if (filters.remote_only) params.append('remote_only', 'true');
+  if (filters.min_salary) params.append('min_salary', filters.min_salary.toString());
+  if (filters.max_salary) params.append('max_salary', filters.max_salary.toString());
+  if (filters.experience_level) params.append('experience_level', filters.experience_level);
+  if (filters.degree_required !== undefined) {
+    params.append('degree_required', filters.degree_required.toString());
+  }
+  if (filters.skills?.length) params.append('skills', filters.skills.join(','));
+  if (filters.company_size) params.append('company_size', filters.company_size);
+  if (filters.industry) params.append('industry', filters.industry);
+  if (filters.sort) params.append('sort', filters.sort);
+  if (filters.limit) params.append('limit', filters.limit.toString());
+  if (filters.offset) params.append('offset', filters.offset.toString());
+
+  return params;
+};
+
 export class JobService {
   // Search jobs with filters
   static async searchJobs(filters: SearchFilters): Promise<SearchResponse> {
-    const params = new URLSearchParams();
-    
-    if (filters.job_title) params.append('title', filters.job_title);
-    if (filters.keywords?.length) params.append('keywords', filters.keywords.join(','));
-    if (filters.location) params.append('location', filters.location);
-    if (filters.remote_only) params.append('remote_only', 'true');
-    if (filters.min_salary) params.append('min_salary', filters.min_salary.toString());
-    if (filters.max_salary) params.append('max_salary', filters.max_salary.toString());
-    if (filters.experience_level) params.append('experience_level', filters.experience_level);
-    if (filters.degree_required !== undefined) {
-      params.append('degree_required', filters.degree_required.toString());
-    }
-    if (filters.skills?.length) params.append('skills', filters.skills.join(','));
-    if (filters.company_size) params.append('company_size', filters.company_size);
-    if (filters.industry) params.append('industry', filters.industry);
-    if (filters.limit) params.append('limit', filters.limit.toString());
-    if (filters.offset) params.append('offset', filters.offset.toString());
-    
+    const params = buildSearchParams(filters);
     const response = await api.get(`/jobs/search?${params.toString()}`);
     return response.data;
   }
 
+  // Stream search results as each source finishes; returns a function that stops the stream
+  static streamSearch(filters: SearchFilters, handlers: StreamHandlers): () => void {
+    const params = buildSearchParams(filters);
+    const source = new EventSource(`${API_BASE_URL}/jobs/search/stream?${params.toString()}`);
+
+    source.addEventListener('result', (event) => {
+      handlers.onResult(JSON.parse((event as MessageEvent).data));
+    });
+
+    source.addEventListener('analytics', (event) => {
+      handlers.onComplete(JSON.parse((event as MessageEvent).data));
+      source.close();
+    });
+
+    source.onerror = () => {
+      handlers.onError('Connection to the search stream was lost.');
+      source.close();
+    };
+
+    return () => source.close();
+  }
+
+  // Start a background search and return its ID straight away
+  static async submitSearch(filters: SearchFilters): Promise<SearchProgress> {
+    const response = await api.post('/searches', filters);
+    return response.data;
+  }
+
+  // Poll a background search for progress and (partial) results
+  static async getSearch(id: string, limit?: number, offset?: number): Promise<SearchProgress> {
+    const params = new URLSearchParams();
+    if (limit) params.append('limit', limit.toString());
+    if (offset) params.append('offset', offset.toString());
+
+    const response = await api.get(`/searches/${id}?${params.toString()}`);
+    return response.data;
+  }
+
+  // Cancel a running background search
+  static async cancelSearch(id: string): Promise<SearchProgress> {
+    const response = await api.delete(`/searches/${id}`);
+    return response.data;
+  }
+
   // Get specific job by ID
   static async getJob(id: string): Promise<Job> {
     const response = await api.get(`/jobs/${id}`);
@@ -19,6 +19,14 @@ export interface Job {
   company_size?: string;
   industry?: string;
   benefits?: string[];
+  score?: number;
+  highlight?: JobHighlight;
+}
+
+// Where a text search matched a job, as HTML with matches in <mark>
+export interface JobHighlight {
+  title?: string;
+  snippet?: string;
 }
 
 // Search filter types
@@ -34,6 +42,7 @@ export interface SearchFilters {
   skills?: string[];
   company_size?: string;
   industry?: string;
+  sort?: 'relevance' | 'date' | 'salary';
   limit?: number;
   offset?: number;
 }
@@ -78,6 +87,39 @@ export interface SearchResponse {
   filters: SearchFilters;
 }
 
+// Asynchronous search types
+export type SearchStatus = 'running' | 'completed' | 'cancelled';
+
+export interface SourceProgress {
+  source: string;
+  status: 'running' | 'completed' | 'failed' | 'cancelled';
+  job_count: number;
+  error?: string;
+}
+
+export interface SearchProgress {
+  id: string;
+  status: SearchStatus;
+  created_at: string;
+  completed_at?: string;
+  expires_at?: string;
+  sources: SourceProgress[];
+  results: SearchResponse | null;
+}
+
+// Streaming search event types
+export interface StreamResult {
+  source: string;
+  jobs: Job[] | null;
+  job_count: number;
+  error?: string;
+}
+
+export interface StreamSummary {
+  total: number;
+  analytics: JobAnalytics;
+}
+
 // Experience level options
 export const EXPERIENCE_LEVELS = [
   { value: '', label: 'Any Experience Level' },